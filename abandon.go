@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Распределение ожидания брошенных звонков. Интервалы ожидания задаются
+// границами в секундах; порог коротких брошенных группы очередей
+// добавляется к границам, чтобы короткие брошенные считались точно.
+
+// abandonWaitEdges границы интервалов ожидания брошенных звонков, сек
+var abandonWaitEdges = []int{5, 10, 20, 30, 60, 120, 300}
+
+// abandonGranularities детализация распределения: по дням, часам дня или месяцам
+var abandonGranularities = []string{"day", "hour", "month"}
+
+// checkWaitGranularity проверяет детализацию распределения
+func checkWaitGranularity(granularity string) error {
+	if !slices.Contains(abandonGranularities, granularity) {
+		return fmt.Errorf("неподдерживаемая детализация: %s (допустимо %v)", granularity, abandonGranularities)
+	}
+	return nil
+}
+
+// waitEdges границы интервалов отчета с порогом коротких брошенных
+func waitEdges(shortAbandonSeconds int) []int {
+	if shortAbandonSeconds <= 0 {
+		return abandonWaitEdges
+	}
+	return insertSorted(abandonWaitEdges, shortAbandonSeconds)
+}
+
+// waitBinLabels подписи интервалов ожидания: 0-5s, 5-10s, ..., 300s+
+func waitBinLabels(edges []int) []string {
+	labels := make([]string, 0, len(edges)+1)
+	from := 0
+	for _, edge := range edges {
+		labels = append(labels, fmt.Sprintf("%d-%ds", from, edge))
+		from = edge
+	}
+	return append(labels, fmt.Sprintf("%ds+", from))
+}
+
+// waitPeriod подпись строки распределения для дня и часа поступления
+func waitPeriod(count WaitBinCount, granularity string) string {
+	switch granularity {
+	case "hour":
+		return fmt.Sprintf("%02d:00", count.Hour)
+	case "month":
+		return count.Date[:7]
+	}
+	return count.Date
+}
+
+// abandonHistogram сводит брошенные звонки по интервалам ожидания в строки
+// выбранной детализации и итог. Интервалы, которые заканчиваются не позже
+// порога коротких брошенных, считаются короткими.
+func abandonHistogram(counts []WaitBinCount, edges []int, shortAbandonSeconds int, granularity string) ([]WaitHistogramRow, WaitHistogramRow) {
+	shortBins := 0
+	if shortAbandonSeconds > 0 {
+		shortBins, _ = slices.BinarySearch(edges, shortAbandonSeconds)
+		shortBins++
+	}
+
+	add := func(row *WaitHistogramRow, count WaitBinCount) {
+		if count.Bin < 0 || count.Bin >= len(row.Bins) {
+			return
+		}
+		row.Bins[count.Bin] += count.Count
+		if count.Bin < shortBins {
+			row.Short += count.Count
+		} else {
+			row.Abandoned += count.Count
+		}
+	}
+
+	byPeriod := make(map[string]*WaitHistogramRow)
+	total := WaitHistogramRow{Bins: make([]int, len(edges)+1)}
+	for _, count := range counts {
+		period := waitPeriod(count, granularity)
+		row := byPeriod[period]
+		if row == nil {
+			row = &WaitHistogramRow{Period: period, Bins: make([]int, len(edges)+1)}
+			byPeriod[period] = row
+		}
+		add(row, count)
+		add(&total, count)
+	}
+
+	rows := make([]WaitHistogramRow, 0, len(byPeriod))
+	for _, period := range sortedKeys(byPeriod) {
+		rows = append(rows, *byPeriod[period])
+	}
+	return rows, total
+}
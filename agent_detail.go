@@ -0,0 +1,183 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Детализация агента. Звонки считаются по времени ответа, чаты — по времени
+// назначения, как в рейтинге агентов (agents.go); из тех же записей строятся
+// ряды по дням и часам, распределения длительностей и одновременные чаты.
+
+// agentTopicLimit сколько топиков обращений агента показывать
+const agentTopicLimit = 10
+
+// agentDetailTotals суммы и активные интервалы агента по его записям
+func agentDetailTotals(calls []AgentCall, chats []AgentChat) agentTotals {
+	var totals agentTotals
+	type slot struct {
+		date   string
+		minute int
+	}
+	slots := make(map[slot]bool)
+	add := func(date string, second int) {
+		minute := second / 60
+		slots[slot{date, minute - minute%slotMinutes}] = true
+	}
+
+	for _, call := range calls {
+		totals.calls.Calls++
+		if call.HasDuration {
+			totals.calls.DurationSum += call.Duration
+			totals.calls.DurationCount++
+		}
+		add(call.Date, call.Second)
+	}
+	for _, chat := range chats {
+		totals.chats.Chats++
+		totals.chats.FRTSum += chat.FRT
+		totals.chats.FRTCount++
+		if chat.HasRT {
+			totals.chats.RTSum += chat.RT
+			totals.chats.RTCount++
+		}
+		add(chat.Date, chat.Second)
+	}
+	totals.slots = len(slots)
+	return totals
+}
+
+// agentDaily звонки и чаты агента по дням
+func agentDaily(calls []AgentCall, chats []AgentChat) []AgentDay {
+	days := make(map[string]*AgentDay)
+	get := func(date string) *AgentDay {
+		if days[date] == nil {
+			days[date] = &AgentDay{Date: date}
+		}
+		return days[date]
+	}
+	for _, call := range calls {
+		get(call.Date).Calls++
+	}
+	for _, chat := range chats {
+		get(chat.Date).Chats++
+	}
+
+	daily := make([]AgentDay, 0, len(days))
+	for _, date := range sortedKeys(days) {
+		daily = append(daily, *days[date])
+	}
+	return daily
+}
+
+// agentHourly звонки, чаты и занятость агента по часам суток за весь период
+// и занятость агента в часы работы за период
+func agentHourly(userID, startDate, endDate string, calls []AgentCall, chats []AgentChat) ([]AgentHour, float64) {
+	hourly := make([]AgentHour, 24)
+	for hour := range hourly {
+		hourly[hour].Hour = hour
+	}
+	spans := make([]HandleSpan, 0, len(calls)+len(chats))
+	for _, call := range calls {
+		hourly[call.Second/3600%24].Calls++
+		if call.HasDuration {
+			spans = append(spans, HandleSpan{UserID: userID, Date: call.Date, Second: call.Second, Duration: call.Duration})
+		}
+	}
+	for _, chat := range chats {
+		hourly[chat.Second/3600%24].Chats++
+		if chat.HasRT {
+			spans = append(spans, HandleSpan{UserID: userID, Date: chat.Date, Second: chat.Second, Duration: chat.RT})
+		}
+	}
+
+	busy := busySeconds(60, startDate, endDate, spans)
+	for hour, value := range groupOccupancy(60, busy, func(i agentInterval) int { return i.bucket }) {
+		hourly[hour].Occupancy = asPercent(value)
+	}
+	overall := groupOccupancy(60, busy, func(agentInterval) bool { return true })
+	return hourly, overall[true]
+}
+
+// agentDurations количества значений длительности по записям
+func agentDurations[T any](records []T, value func(T) (float64, bool)) []ValueCount {
+	counts := make(map[float64]int)
+	for _, record := range records {
+		if v, ok := value(record); ok {
+			counts[v]++
+		}
+	}
+	values := make([]ValueCount, 0, len(counts))
+	for v, count := range counts {
+		values = append(values, ValueCount{Value: v, Count: count})
+	}
+	return values
+}
+
+// chatConcurrency одновременные чаты агента: проход по началам и концам чатов
+// в порядке времени. Чат, закрытый в момент назначения следующего, с ним не пересекается.
+func chatConcurrency(chats []AgentChat) AgentConcurrency {
+	type event struct {
+		at    float64 // секунды Unix
+		delta int
+	}
+	events := make([]event, 0, 2*len(chats))
+	var result AgentConcurrency
+	for _, chat := range chats {
+		if !chat.HasRT || chat.RT <= 0 {
+			continue
+		}
+		day, err := time.Parse(dateLayout, chat.Date)
+		if err != nil {
+			continue
+		}
+		start := float64(day.Unix() + int64(chat.Second))
+		events = append(events, event{start, 1}, event{start + chat.RT, -1})
+		result.Chats++
+	}
+	slices.SortFunc(events, func(a, b event) int {
+		return cmp.Or(cmp.Compare(a.at, b.at), cmp.Compare(a.delta, b.delta))
+	})
+
+	var open int
+	var busy, weighted, overlap float64
+	for i, e := range events {
+		if i > 0 && open > 0 {
+			span := e.at - events[i-1].at
+			busy += span
+			weighted += span * float64(open)
+			if open >= 2 {
+				overlap += span
+			}
+		}
+		open += e.delta
+		if open > result.Max {
+			result.Max = open
+			result.PeakAt = time.Unix(int64(e.at), 0).UTC().Format("2006-01-02 15:04:05")
+		}
+	}
+	if busy > 0 {
+		result.Average = roundTo(weighted/busy, 2)
+		result.OverlapShare = asPercent(overlap / busy * 100)
+	}
+	return result
+}
+
+// agentTopics первые limit топиков агента по убыванию количества с долей от всех его обращений
+func agentTopics(counts []TopicCount, limit int) []AgentTopic {
+	sorted := slices.Clone(counts)
+	slices.SortStableFunc(sorted, func(a, b TopicCount) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), cmp.Compare(a.Topic, b.Topic))
+	})
+	var total int
+	for _, c := range sorted {
+		total += c.Total
+	}
+
+	topics := make([]AgentTopic, 0, min(limit, len(sorted)))
+	for _, c := range sorted[:min(limit, len(sorted))] {
+		topics = append(topics, AgentTopic{Topic: c.Topic, Total: c.Total, Ratio: asPercent(ratio(float64(c.Total)*100, total))})
+	}
+	return topics
+}
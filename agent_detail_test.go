@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestChatConcurrency(t *testing.T) {
+	chats := []AgentChat{
+		// 09:00-09:10 и 09:05-09:20 пересекаются 5 минут
+		{Date: "2024-03-01", Second: 9 * 3600, RT: 600, HasRT: true},
+		{Date: "2024-03-01", Second: 9*3600 + 300, RT: 900, HasRT: true},
+		// Начинается в момент закрытия второго — пересечения нет
+		{Date: "2024-03-01", Second: 9*3600 + 1200, RT: 600, HasRT: true},
+		// Без времени решения не учитывается
+		{Date: "2024-03-01", Second: 9*3600 + 100},
+		// Через полночь: 23:55-00:05 и 00:00-00:02 следующего дня
+		{Date: "2024-03-01", Second: 23*3600 + 55*60, RT: 600, HasRT: true},
+		{Date: "2024-03-02", Second: 0, RT: 120, HasRT: true},
+	}
+
+	got := chatConcurrency(chats)
+	// Занято 30 + 10 минут, из них вдвоем 5 + 2 минуты
+	want := AgentConcurrency{Chats: 5, Max: 2, PeakAt: "2024-03-01 09:05:00", Average: 1.18, OverlapShare: 17.5}
+	if got != want {
+		t.Errorf("chatConcurrency = %+v, want %+v", got, want)
+	}
+
+	if got := chatConcurrency(nil); got != (AgentConcurrency{}) {
+		t.Errorf("empty = %+v", got)
+	}
+}
+
+func TestAgentTopics(t *testing.T) {
+	counts := []TopicCount{{"Cards", 1}, {"Billing", 3}, {"Account", 1}}
+	got := agentTopics(counts, 2)
+	want := []AgentTopic{{"Billing", 3, 60}, {"Account", 1, 20}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("agentTopics = %+v, want %+v", got, want)
+	}
+}
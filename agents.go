@@ -0,0 +1,195 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// Рейтинг агентов. Звонки агента считаются по дате ответа, чаты — по дате
+// назначения, активные часы — по интервалам slotMinutes с ответами в звонках
+// или чатах. Длительности, которых у агента нет (AHT без звонков, FRT и RT
+// без чатов), не участвуют ни в медиане команды, ни в ранге.
+
+// agentSortMetrics метрики сортировки рейтинга: true — лучше меньшее значение
+var agentSortMetrics = map[string]bool{
+	"handled":      false,
+	"calls":        false,
+	"chats":        false,
+	"active_hours": false,
+	"aht":          true,
+	"frt":          true,
+	"rt":           true,
+}
+
+// checkAgentSort проверяет метрику сортировки рейтинга
+func checkAgentSort(sortBy string) error {
+	if _, ok := agentSortMetrics[sortBy]; !ok {
+		keys := make([]string, 0, len(agentSortMetrics))
+		for key := range agentSortMetrics {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return fmt.Errorf("неподдерживаемая сортировка агентов: %s (допустимо %v)", sortBy, keys)
+	}
+	return nil
+}
+
+// agentTotals суммы агента по звонкам, чатам и активности
+type agentTotals struct {
+	calls AgentCallTotals
+	chats AgentChatTotals
+	slots int // интервалы slotMinutes с ответами
+}
+
+// metrics показатели агента, округленные для ответа
+func (t agentTotals) metrics() AgentMetrics {
+	return AgentMetrics{
+		Calls:       t.calls.Calls,
+		Chats:       t.chats.Chats,
+		Handled:     t.calls.Calls + t.chats.Chats,
+		AHT:         asSeconds(ratio(t.calls.DurationSum, t.calls.DurationCount)),
+		FRT:         asSeconds(ratio(t.chats.FRTSum, t.chats.FRTCount)),
+		RT:          asSeconds(ratio(t.chats.RTSum, t.chats.RTCount)),
+		ActiveHours: roundTo(float64(t.slots*slotMinutes)/60, 2),
+	}
+}
+
+// value значение метрики агента; false — у агента нет этой метрики
+func (t agentTotals) value(metric string) (float64, bool) {
+	m := t.metrics()
+	switch metric {
+	case "handled":
+		return float64(m.Handled), true
+	case "calls":
+		return float64(m.Calls), true
+	case "chats":
+		return float64(m.Chats), true
+	case "active_hours":
+		return m.ActiveHours, true
+	case "aht":
+		return m.AHT, t.calls.DurationCount > 0
+	case "frt":
+		return m.FRT, t.chats.FRTCount > 0
+	case "rt":
+		return m.RT, t.chats.RTCount > 0
+	}
+	return 0, false
+}
+
+// collectAgentTotals сводит суммы звонков, чатов и активность по user_id
+func collectAgentTotals(calls []AgentCallTotals, chats []AgentChatTotals, activity ...[]AgentActivity) map[string]*agentTotals {
+	agents := make(map[string]*agentTotals)
+	get := func(userID string) *agentTotals {
+		if agents[userID] == nil {
+			agents[userID] = &agentTotals{}
+		}
+		return agents[userID]
+	}
+	for _, c := range calls {
+		get(c.UserID).calls = c
+	}
+	for _, c := range chats {
+		get(c.UserID).chats = c
+	}
+
+	// Интервал с ответами и в звонках, и в чатах считается один раз
+	type slot struct {
+		date   string
+		minute int
+	}
+	seen := make(map[string]map[slot]bool)
+	for _, entries := range activity {
+		for _, entry := range entries {
+			if seen[entry.UserID] == nil {
+				seen[entry.UserID] = make(map[slot]bool)
+			}
+			seen[entry.UserID][slot{entry.Date, entry.Minute}] = true
+		}
+	}
+	for userID, slots := range seen {
+		get(userID).slots = len(slots)
+	}
+	return agents
+}
+
+// teamMedian медиана метрики по агентам, у которых она есть
+func teamMedian(agents map[string]*agentTotals, metric string) float64 {
+	values := make([]ValueCount, 0, len(agents))
+	for _, agent := range agents {
+		if v, ok := agent.value(metric); ok {
+			values = append(values, ValueCount{Value: v, Count: 1})
+		}
+	}
+	return newCountedValues(values).percentile(50)
+}
+
+// agentRanking строки рейтинга по метрике sortBy от лучшего к худшему и медиана
+// команды. Равные значения делят место; агенты без метрики идут в конце без места.
+func agentRanking(agents map[string]*agentTotals, names map[string]string, sortBy string) ([]AgentPerformance, AgentMedian) {
+	median := AgentMedian{
+		Calls:       teamMedian(agents, "calls"),
+		Chats:       teamMedian(agents, "chats"),
+		Handled:     teamMedian(agents, "handled"),
+		AHT:         asSeconds(teamMedian(agents, "aht")),
+		FRT:         asSeconds(teamMedian(agents, "frt")),
+		RT:          asSeconds(teamMedian(agents, "rt")),
+		ActiveHours: roundTo(teamMedian(agents, "active_hours"), 2),
+	}
+	sortMedian := teamMedian(agents, sortBy)
+	lowerIsBetter := agentSortMetrics[sortBy]
+
+	type ranked struct {
+		row   AgentPerformance
+		value float64
+		has   bool
+	}
+	rows := make([]ranked, 0, len(agents))
+	for userID, agent := range agents {
+		name := names[userID]
+		if name == "" {
+			name = userID
+		}
+		value, has := agent.value(sortBy)
+		row := AgentPerformance{UserID: userID, Name: name, Metrics: agent.metrics()}
+		if has {
+			if sortMedian != 0 {
+				row.VsMedian = asPercent((value - sortMedian) / sortMedian * 100)
+			}
+			row.BetterThanMedian = value > sortMedian
+			if lowerIsBetter {
+				row.BetterThanMedian = value < sortMedian
+			}
+		}
+		rows = append(rows, ranked{row: row, value: value, has: has})
+	}
+
+	slices.SortFunc(rows, func(a, b ranked) int {
+		if a.has != b.has {
+			if a.has {
+				return -1
+			}
+			return 1
+		}
+		if a.has && a.value != b.value {
+			if lowerIsBetter {
+				return cmp.Compare(a.value, b.value)
+			}
+			return cmp.Compare(b.value, a.value)
+		}
+		return cmp.Or(cmp.Compare(a.row.Name, b.row.Name), cmp.Compare(a.row.UserID, b.row.UserID))
+	})
+
+	result := make([]AgentPerformance, len(rows))
+	for i, r := range rows {
+		if r.has {
+			r.row.Rank = i + 1
+			if i > 0 && rows[i-1].has && rows[i-1].value == r.value {
+				r.row.Rank = result[i-1].Rank
+			}
+		}
+		result[i] = r.row
+	}
+	return result, median
+}
@@ -0,0 +1,1007 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func at(value string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04:05", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Звонки за 1-2 марта 2024 и по одному звонку вне периода и вне отчетных очередей
+var testCalls = []fakeCall{
+	{EnterQueue: at("2024-03-01 09:10:00"), Answer: at("2024-03-01 09:10:15"), Type: "in", Queue: "m10", Duration: 120, Wait: 15, UserID: "a1"},
+	{EnterQueue: at("2024-03-01 09:40:00"), Answer: at("2024-03-01 09:41:00"), Type: "in", Queue: "m10", Duration: 240, Wait: 60, UserID: "a2"},
+	{EnterQueue: at("2024-03-01 10:05:00"), Type: "abandon", Queue: "m10", Wait: 30},
+	{EnterQueue: at("2024-03-01 10:20:00"), Answer: at("2024-03-01 10:20:10"), Type: "in", Queue: "m10-shikayet", Duration: 300, Wait: 10, UserID: "a3"},
+	{EnterQueue: at("2024-03-02 14:00:00"), Answer: at("2024-03-02 14:00:05"), Type: "in", Queue: "m10", Duration: 61, Wait: 5, UserID: "a1"},
+	{EnterQueue: at("2024-03-02 14:30:00"), Type: "abandon", Queue: "m10-shikayet", Wait: 40},
+	{EnterQueue: at("2024-03-02 15:00:00"), Answer: at("2024-03-02 15:00:01"), Type: "in", Queue: "other", Duration: 999, Wait: 1, UserID: "x9"},
+	{EnterQueue: at("2024-02-29 09:00:00"), Answer: at("2024-02-29 09:00:05"), Type: "in", Queue: "m10", Duration: 50, Wait: 5, UserID: "a1"},
+}
+
+// Имена агентов из omni.conversation; у a3 имени нет
+var testAgentNames = map[string]string{
+	"a1": "Aysel Mammadova",
+	"a2": "Rashad Aliyev",
+	"a4": "Leyla Huseynova",
+	"a5": "Orkhan Guliyev",
+}
+
+// Чаты каналов m10 и один чат канала aml-chat, который не входит в группы по умолчанию
+var testChats = []fakeChat{
+	{Created: at("2024-03-01 09:00:00"), Assign: at("2024-03-01 09:02:00"), Type: "in", Channel: "WHATSAPP", FRT: 30, RT: 600, AgentFRT: 20, UserID: "a1"},
+	{Created: at("2024-03-01 09:30:00"), Assign: at("2024-03-01 09:31:00"), Type: "in", Channel: "telegram", FRT: 90, RT: 1200, UserID: "a4"},
+	{Created: at("2024-03-02 23:55:00"), Assign: at("2024-03-03 00:01:00"), Type: "in", Channel: "m10 Instagram", FRT: 10, RT: 100, AgentFRT: 5, UserID: "a5"},
+	{Created: at("2024-03-02 10:00:00"), Assign: at("2024-03-02 10:00:30"), Type: "out", Channel: "WHATSAPP", AgentFRT: 5, UserID: "a6"},
+	{Created: at("2024-03-02 11:00:00"), Assign: at("2024-03-02 11:05:00"), Type: "in", Channel: "aml-chat", FRT: 300, RT: 900, AgentFRT: 240, UserID: "a3"},
+}
+
+var testRequests = []fakeRequest{
+	{ReportDate: "2024-03-01", Type: "in", Queue: "m10", UserID: "a1", Paths: []string{"M10/Billing/Refund/Card"}},
+	{ReportDate: "2024-03-01", Type: "in", Queue: "WHATSAPP", UserID: "a1", Paths: []string{"M10/Billing/Refund/Card", "M10/Account"}},
+	{ReportDate: "2024-03-01", Type: "in", Queue: "m10-shikayet", UserID: "a3", Paths: []string{"Complaint"}},
+	{ReportDate: "2024-03-02", Type: "in", Queue: "m10", Paths: []string{" M10 / Cards / Limit "}},
+	{ReportDate: "2024-03-01", Type: "out", Queue: "m10", Paths: []string{"M10/Billing/Refund"}},
+	{ReportDate: "2024-02-29", Type: "in", Queue: "m10", Paths: []string{"M10/Billing/Refund"}},
+}
+
+func newTestApp() *App {
+	return &App{repos: newFakeSource(testCalls, testChats, testRequests)}
+}
+
+func assertPoints(t *testing.T, name string, got []DailyPoint, want []DailyPoint) {
+	t.Helper()
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s:\n got %v\nwant %v", name, got, want)
+	}
+}
+
+func assertHours(t *testing.T, name string, got *HourlyMatrix, want []HourlyValues) {
+	t.Helper()
+	if len(got.Rows) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got.Rows, want) {
+		t.Errorf("%s:\n got %v\nwant %v", name, got.Rows, want)
+	}
+}
+
+func TestGetDailyData(t *testing.T) {
+	result, err := newTestApp().GetDailyData("", "2024-03-01", "2024-03-02", "all")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPoints(t, "calls", result.Calls, []DailyPoint{
+		{"2024-03-01", 4},
+		{"2024-03-02", 2},
+	})
+	assertPoints(t, "aht", result.AHT, []DailyPoint{
+		{"2024-03-01", 220},
+		{"2024-03-02", 61},
+	})
+	assertPoints(t, "sl", result.SL, []DailyPoint{
+		{"2024-03-01", 66.67},
+		{"2024-03-02", 100.0},
+	})
+	assertPoints(t, "abandoned", result.Abandoned, []DailyPoint{
+		{"2024-03-01", 1},
+		{"2024-03-02", 1},
+	})
+	assertPoints(t, "abandon_rate", result.AbandonRate, []DailyPoint{
+		{"2024-03-01", 25},
+		{"2024-03-02", 50},
+	})
+	assertPoints(t, "chats", result.Chats, []DailyPoint{
+		{"2024-03-01", 2},
+	})
+	assertPoints(t, "frt", result.FRT, []DailyPoint{
+		{"2024-03-01", 60},
+	})
+	assertPoints(t, "rt", result.RT, []DailyPoint{
+		{"2024-03-01", 900},
+	})
+	// Агент a1 работал и в звонках, и в чатах 1 марта — считается один раз
+	assertPoints(t, "agents", result.Agents, []DailyPoint{
+		{"2024-03-01", 3},
+		{"2024-03-02", 2},
+	})
+	// a1 615 с (звонок внутри чата), a2 240 с в 09:00 и a3 300 с в 10:00 —
+	// три часовых интервала агентов; у a6 исходящий чат без времени решения
+	assertPoints(t, "occupancy", result.Occupancy, []DailyPoint{
+		{"2024-03-01", 10.69},
+		{"2024-03-02", 1.69},
+	})
+}
+
+func TestGetDailyDataQueueFilter(t *testing.T) {
+	app := newTestApp()
+
+	for _, queueName := range []string{"aml", "AML"} {
+		result, err := app.GetDailyData("", "2024-03-01", "2024-03-02", queueName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPoints(t, queueName+" calls", result.Calls, []DailyPoint{
+			{"2024-03-01", 1},
+			{"2024-03-02", 1},
+		})
+	}
+
+	result, err := app.GetDailyData("", "2024-03-01", "2024-03-02", "m10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPoints(t, "m10 calls", result.Calls, []DailyPoint{
+		{"2024-03-01", 3},
+		{"2024-03-02", 1},
+	})
+	assertPoints(t, "m10 agents", result.Agents, []DailyPoint{
+		{"2024-03-01", 2},
+		{"2024-03-02", 2},
+	})
+}
+
+func TestGetDailyDataTimings(t *testing.T) {
+	source := newFakeSource(testCalls, testChats, testRequests)
+	source.MaxQueries = 2
+	app := &App{repos: source}
+
+	result, err := app.GetDailyData("", "2024-03-01", "2024-03-02", "all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var queries []string
+	for _, timing := range result.Timings {
+		queries = append(queries, timing.Query)
+	}
+	want := []string{"calls", "aht", "sl", "abandoned", "abandon_rate", "waits", "chats", "frt", "rt", "agents", "occupancy"}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("timings: got %v, want %v", queries, want)
+	}
+}
+
+// failingSL отдает ошибку в DailySL, а DailyCalls ждет отмены контекста
+type failingSL struct {
+	CallReportRepository
+}
+
+func (f failingSL) DailySL(ctx context.Context, startDate, endDate string, thresholdSeconds int, queues []string) ([]DailyValue, error) {
+	return nil, errors.New("ошибка выполнения запроса SL")
+}
+
+func (f failingSL) DailyCalls(ctx context.Context, startDate, endDate string, queues []string) ([]DailyValue, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(5 * time.Second):
+		return nil, errors.New("запрос не отменен")
+	}
+}
+
+func TestGetDailyDataCancelsOnError(t *testing.T) {
+	source := newFakeSource(testCalls, testChats, testRequests)
+	source.Calls = failingSL{source.Calls}
+	app := &App{repos: source}
+
+	_, err := app.GetDailyData("", "2024-03-01", "2024-03-02", "all")
+	if err == nil || !strings.Contains(err.Error(), "SL") {
+		t.Fatalf("ожидалась ошибка запроса SL, получено %v", err)
+	}
+}
+
+func TestRunQueriesLimit(t *testing.T) {
+	var running, peak atomic.Int32
+	query := namedQuery{name: "q", run: func(ctx context.Context) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}}
+
+	timings, err := runQueries(context.Background(), 3, []namedQuery{query, query, query, query, query, query, query, query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timings) != 8 {
+		t.Errorf("timings: got %d, want 8", len(timings))
+	}
+	if peak.Load() > 3 {
+		t.Errorf("одновременно выполнялось %d запросов при лимите 3", peak.Load())
+	}
+}
+
+func TestChatQueueFilter(t *testing.T) {
+	source := newFakeSource(testCalls, testChats, testRequests)
+	source.Queues = DefaultQueueGroups()
+	source.Queues[2].ChatChannels = []string{"aml-chat"}
+	app := &App{repos: source}
+
+	m10, err := app.GetDailyData("", "2024-03-01", "2024-03-02", "m10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPoints(t, "m10 chats", m10.Chats, []DailyPoint{
+		{"2024-03-01", 2},
+	})
+	assertPoints(t, "m10 frt", m10.FRT, []DailyPoint{
+		{"2024-03-01", 60},
+	})
+
+	aml, err := app.GetDailyData("", "2024-03-01", "2024-03-02", "aml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPoints(t, "aml chats", aml.Chats, []DailyPoint{
+		{"2024-03-02", 1},
+	})
+	assertPoints(t, "aml frt", aml.FRT, []DailyPoint{
+		{"2024-03-02", 300},
+	})
+	assertPoints(t, "aml rt", aml.RT, []DailyPoint{
+		{"2024-03-02", 900},
+	})
+	// a3 отвечал и на звонок, и в чате aml — один агент за каждый день
+	assertPoints(t, "aml agents", aml.Agents, []DailyPoint{
+		{"2024-03-01", 1},
+		{"2024-03-02", 1},
+	})
+
+	hourly, err := app.GetHourlyData("", "2024-03-01", "2024-03-02", "aml", "chats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHours(t, "aml hourly chats", hourly, []HourlyValues{
+		hourlyRow("2024-03-02", map[int]float64{11: 1}),
+	})
+
+	// В total звонки и чаты берутся из одной группы
+	hourly, err = app.GetHourlyData("", "2024-03-01", "2024-03-02", "aml", "total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHours(t, "aml hourly total", hourly, []HourlyValues{
+		hourlyRow("2024-03-01", map[int]float64{10: 1}),
+		hourlyRow("2024-03-02", map[int]float64{11: 1}),
+	})
+
+	// В группе aml по умолчанию каналов чатов нет
+	aml, err = newTestApp().GetDailyData("", "2024-03-01", "2024-03-02", "aml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPoints(t, "default aml chats", aml.Chats, nil)
+}
+
+func hourlyRow(date string, hours map[int]float64) HourlyValues {
+	row := HourlyValues{Date: date, Hours: make([]float64, 24)}
+	for hour, value := range hours {
+		row.Hours[hour] = value
+	}
+	return row
+}
+
+func TestGetHourlyData(t *testing.T) {
+	app := newTestApp()
+
+	tests := []struct {
+		metric string
+		want   []HourlyValues
+	}{
+		{"calls", []HourlyValues{
+			hourlyRow("2024-03-01", map[int]float64{9: 2, 10: 1}),
+			hourlyRow("2024-03-02", map[int]float64{14: 1}),
+		}},
+		{"aht", []HourlyValues{
+			hourlyRow("2024-03-01", map[int]float64{9: 180.0, 10: 300.0}),
+			hourlyRow("2024-03-02", map[int]float64{14: 61.0}),
+		}},
+		{"sl", []HourlyValues{
+			hourlyRow("2024-03-01", map[int]float64{9: 50.0, 10: 100.0}),
+			hourlyRow("2024-03-02", map[int]float64{14: 100.0}),
+		}},
+		{"abandoned", []HourlyValues{
+			hourlyRow("2024-03-01", map[int]float64{10: 1}),
+			hourlyRow("2024-03-02", map[int]float64{14: 1}),
+		}},
+		{"chats", []HourlyValues{
+			hourlyRow("2024-03-01", map[int]float64{9: 2}),
+			hourlyRow("2024-03-02", map[int]float64{23: 1}),
+		}},
+		{"frt", []HourlyValues{
+			hourlyRow("2024-03-01", map[int]float64{9: 60.0}),
+		}},
+		{"agents", []HourlyValues{
+			hourlyRow("2024-03-01", map[int]float64{9: 2, 10: 1}),
+			hourlyRow("2024-03-02", map[int]float64{10: 1, 14: 1}),
+		}},
+		{"total", []HourlyValues{
+			hourlyRow("2024-03-01", map[int]float64{9: 4, 10: 1}),
+			hourlyRow("2024-03-02", map[int]float64{14: 1, 23: 1}),
+		}},
+		{"occupancy", []HourlyValues{
+			hourlyRow("2024-03-01", map[int]float64{9: 11.88, 10: 8.33}),
+			hourlyRow("2024-03-02", map[int]float64{14: 1.69}),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			result, err := app.GetHourlyData("", "2024-03-01", "2024-03-02", "all", tt.metric)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Metric != tt.metric {
+				t.Errorf("metric = %q, want %q", result.Metric, tt.metric)
+			}
+			assertHours(t, tt.metric, result, tt.want)
+		})
+	}
+
+	if _, err := app.GetHourlyData("", "2024-03-01", "2024-03-02", "all", "unknown"); err == nil {
+		t.Fatal("expected error for unsupported metric")
+	}
+}
+
+func TestGetIntervalData(t *testing.T) {
+	app := newTestApp()
+
+	result, err := app.GetIntervalData("", "2024-03-01", "2024-03-02", "all", "calls", 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IntervalMinutes != 15 || result.Timezone != "Asia/Baku" || len(result.Labels) != 96 {
+		t.Fatalf("interval = %d, timezone = %q, labels = %d", result.IntervalMinutes, result.Timezone, len(result.Labels))
+	}
+	if result.Labels[0] != "00:00" || result.Labels[38] != "09:30" || result.Labels[95] != "23:45" {
+		t.Errorf("labels = %v", result.Labels)
+	}
+	if len(result.Rows) != 2 || result.Rows[0].Date != "2024-03-01" || len(result.Rows[0].Values) != 96 {
+		t.Fatalf("rows = %+v", result.Rows)
+	}
+	if got := result.Rows[0].Values; got[36] != 1 || got[38] != 1 || got[41] != 1 || got[40] != 0 {
+		t.Errorf("2024-03-01: 09:00 = %v, 09:30 = %v, 10:15 = %v, 10:00 = %v", got[36], got[38], got[41], got[40])
+	}
+	// Средний день объемов делит сумму на все дни периода
+	if got := result.Average; got[36] != 0.5 || got[56] != 0.5 || got[0] != 0 {
+		t.Errorf("average: 09:00 = %v, 14:00 = %v, 00:00 = %v", got[36], got[56], got[0])
+	}
+
+	// Проценты усредняются по дням, где в интервале были звонки
+	sl, err := app.GetIntervalData("", "2024-03-01", "2024-03-03", "all", "sl", 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sl.Average; got[18] != 100 || got[19] != 0 || got[28] != 100 {
+		t.Errorf("sl average: 09:00 = %v, 09:30 = %v, 14:00 = %v", got[18], got[19], got[28])
+	}
+
+	// ASA интервала считается по звонкам интервала, а не из дневного значения
+	asa, err := app.GetIntervalData("", "2024-03-01", "2024-03-02", "all", "asa", 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := asa.Rows[0].Values; got[9] != 38 || got[10] != 10 || got[14] != 0 {
+		t.Errorf("asa 2024-03-01: 09:00 = %v, 10:00 = %v, 14:00 = %v", got[9], got[10], got[14])
+	}
+
+	agents, err := app.GetIntervalData("", "2024-03-01", "2024-03-01", "all", "agents", 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := agents.Rows[0].Values; got[36] != 1 || got[38] != 1 || got[41] != 1 {
+		t.Errorf("agents: 09:00 = %v, 09:30 = %v, 10:15 = %v", got[36], got[38], got[41])
+	}
+
+	if _, err := app.GetIntervalData("", "2024-03-01", "2024-03-02", "all", "calls", 45); err == nil {
+		t.Error("expected error for 45-minute interval")
+	}
+	if _, err := app.GetIntervalData("", "2024-03-02", "2024-03-01", "all", "calls", 15); err == nil {
+		t.Error("expected error for reversed period")
+	}
+}
+
+func TestGetPeriodData(t *testing.T) {
+	app := newTestApp()
+
+	// AHT, SL и FRT недели считаются из сумм, а не как среднее дневных значений:
+	// AHT (120+240+300+61)/4, а не (220+61)/2; SL 3/4, а не (66.67+100)/2
+	result, err := app.GetPeriodData("", "2024-03-01", "2024-03-03", "all", "week")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PeriodMetrics{{
+		Period: "2024-W09", Start: "2024-03-01", End: "2024-03-03",
+		Calls: 6, AHT: 180, SL: 75, Abandoned: 2, AbandonRate: 33.33,
+		ASA: 23, MaxWait: 60, WaitP50: 13, WaitP90: 47, WaitP95: 53,
+		Chats: 3, FRT: 43, RT: 633, Agents: 5,
+		// Занятость из занятых секунд по часам агентов: 1316 с за 5 часов
+		Occupancy: 7.31,
+	}}
+	if result.Granularity != "week" || !reflect.DeepEqual(result.Periods, want) {
+		t.Errorf("week:\n got %+v\nwant %+v", result.Periods, want)
+	}
+	if len(result.Timings) != 5 {
+		t.Errorf("timings = %+v, want 5 queries", result.Timings)
+	}
+
+	// Дни без данных входят в отчет с нулями
+	days, err := app.GetPeriodData("", "2024-03-01", "2024-03-03", "all", "day")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days.Periods) != 3 || days.Periods[2].Calls != 0 || days.Periods[2].Chats != 1 {
+		t.Errorf("days = %+v", days.Periods)
+	}
+	if got := days.Periods[0]; got.AHT != 220 || got.SL != 66.67 || got.Agents != 3 {
+		t.Errorf("2024-03-01 = %+v", got)
+	}
+
+	if _, err := app.GetPeriodData("", "2024-03-01", "2024-03-03", "all", "fortnight"); err == nil {
+		t.Error("expected error for unknown granularity")
+	}
+}
+
+func TestGetServiceLevel(t *testing.T) {
+	result, err := newTestApp().GetServiceLevel("", "2024-03-01", "2024-03-02", "all")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Звонки 1 марта ожидали 15, 60 и 10 секунд, 2 марта — 5 секунд
+	thresholds := func(sl ...float64) []ThresholdSL {
+		values := make([]ThresholdSL, len(sl))
+		for i, seconds := range slThresholds {
+			values[i] = ThresholdSL{Seconds: seconds, SL: sl[i]}
+		}
+		return values
+	}
+	want := ServiceLevelSeries{
+		Target: SLTarget{ThresholdSeconds: 20, TargetPercent: 95},
+		Days: []ServiceLevelDay{
+			{Date: "2024-03-01", Total: 3, Thresholds: thresholds(33.33, 66.67, 66.67, 100), SL: 66.67},
+			{Date: "2024-03-02", Total: 1, Thresholds: thresholds(100, 100, 100, 100), SL: 100, Met: true},
+		},
+		// Итог периода из количеств: 2 из 4, а не среднее (66.67+100)/2
+		Total: ServiceLevelDay{Total: 4, Thresholds: thresholds(50, 75, 75, 100), SL: 75},
+	}
+	if !reflect.DeepEqual(result.Calls, want) {
+		t.Errorf("calls:\n got %+v\nwant %+v", result.Calls, want)
+	}
+
+	// Чаты считаются по FRT против порога 60 секунд: 30 и 90 секунд
+	if got := result.Chats.Total; got.Total != 2 || got.SL != 50 || got.Met ||
+		!reflect.DeepEqual(got.Thresholds, thresholds(0, 0, 50, 50)) {
+		t.Errorf("chats total = %+v", got)
+	}
+	if len(result.Timings) != 2 {
+		t.Errorf("timings = %+v, want 2 queries", result.Timings)
+	}
+}
+
+// Порог SL из группы очередей применяется во всех отчетах
+func TestConfiguredSLTarget(t *testing.T) {
+	source := newFakeSource(testCalls, testChats, testRequests)
+	source.Queues = QueueRegistry{
+		{ID: "slow", CallQueues: []string{"m10", "m10-shikayet"}, CallSL: SLTarget{ThresholdSeconds: 60, TargetPercent: 90}},
+	}
+	app := &App{repos: source}
+
+	daily, err := app.GetDailyData("", "2024-03-01", "2024-03-02", "slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPoints(t, "sl", daily.SL, []DailyPoint{{"2024-03-01", 100}, {"2024-03-02", 100}})
+	if daily.SLTarget != (SLTarget{ThresholdSeconds: 60, TargetPercent: 90}) {
+		t.Errorf("sl_target = %+v", daily.SLTarget)
+	}
+
+	periods, err := app.GetPeriodData("", "2024-03-01", "2024-03-02", "slow", "period")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := periods.Periods[0].SL; got != 100 {
+		t.Errorf("period sl = %v, want 100", got)
+	}
+
+	level, err := app.GetServiceLevel("", "2024-03-01", "2024-03-02", "slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := level.Calls.Total; got.SL != 100 || !got.Met {
+		t.Errorf("calls total = %+v", got)
+	}
+
+	// Нестандартный порог цели добавляется к стандартным
+	if got := reportThresholds(SLTarget{ThresholdSeconds: 45}); !reflect.DeepEqual(got, []int{10, 20, 30, 45, 60}) {
+		t.Errorf("reportThresholds(45) = %v", got)
+	}
+}
+
+// Звонки, брошенные быстрее порога группы, не считаются брошенными
+// и не входят в поступившие для процента брошенных
+func TestShortAbandon(t *testing.T) {
+	source := newFakeSource(testCalls, testChats, testRequests)
+	source.Queues = QueueRegistry{
+		{ID: "short", CallQueues: []string{"m10", "m10-shikayet"}, ShortAbandonSeconds: 35},
+	}
+	app := &App{repos: source}
+
+	// 1 марта брошен звонок после 30 секунд ожидания, 2 марта — после 40
+	daily, err := app.GetDailyData("", "2024-03-01", "2024-03-02", "short")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPoints(t, "abandoned", daily.Abandoned, []DailyPoint{{"2024-03-02", 1}})
+	assertPoints(t, "abandon_rate", daily.AbandonRate, []DailyPoint{{"2024-03-01", 0}, {"2024-03-02", 50}})
+	assertPoints(t, "calls", daily.Calls, []DailyPoint{{"2024-03-01", 4}, {"2024-03-02", 2}})
+
+	periods, err := app.GetPeriodData("", "2024-03-01", "2024-03-02", "short", "period")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := periods.Periods[0]; got.Calls != 6 || got.Abandoned != 1 || got.AbandonRate != 20 {
+		t.Errorf("period = %+v", got)
+	}
+
+	rate, err := app.GetIntervalData("", "2024-03-01", "2024-03-02", "short", "abandon_rate", 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rate.Rows[1].Values[14]; got != 50 {
+		t.Errorf("2024-03-02 14:00 abandon_rate = %v, want 50", got)
+	}
+	if got := rate.Rows[0].Values[10]; got != 0 {
+		t.Errorf("2024-03-01 10:00 abandon_rate = %v, want 0", got)
+	}
+}
+
+func TestGetAbandonWaits(t *testing.T) {
+	source := newFakeSource(testCalls, testChats, testRequests)
+	source.Queues = QueueRegistry{
+		{ID: "short", CallQueues: []string{"m10", "m10-shikayet"}, ShortAbandonSeconds: 35},
+	}
+	app := &App{repos: source}
+
+	// Порог коротких брошенных добавляется к границам интервалов
+	wantBins := []string{"0-5s", "5-10s", "10-20s", "20-30s", "30-35s", "35-60s", "60-120s", "120-300s", "300s+"}
+	bins := func(counts map[int]int) []int {
+		values := make([]int, len(wantBins))
+		for bin, count := range counts {
+			values[bin] = count
+		}
+		return values
+	}
+
+	days, err := app.GetAbandonWaits("", "2024-03-01", "2024-03-02", "short", "day")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(days.Bins, wantBins) {
+		t.Errorf("bins = %v", days.Bins)
+	}
+	wantRows := []WaitHistogramRow{
+		{Period: "2024-03-01", Bins: bins(map[int]int{4: 1}), Short: 1},
+		{Period: "2024-03-02", Bins: bins(map[int]int{5: 1}), Abandoned: 1},
+	}
+	if !reflect.DeepEqual(days.Rows, wantRows) {
+		t.Errorf("days:\n got %+v\nwant %+v", days.Rows, wantRows)
+	}
+	if want := (WaitHistogramRow{Bins: bins(map[int]int{4: 1, 5: 1}), Abandoned: 1, Short: 1}); !reflect.DeepEqual(days.Total, want) {
+		t.Errorf("total = %+v", days.Total)
+	}
+
+	hours, err := app.GetAbandonWaits("", "2024-03-01", "2024-03-02", "short", "hour")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hours.Rows) != 2 || hours.Rows[0].Period != "10:00" || hours.Rows[1].Period != "14:00" {
+		t.Errorf("hours = %+v", hours.Rows)
+	}
+
+	months, err := newTestApp().GetAbandonWaits("", "2024-03-01", "2024-03-02", "all", "month")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(months.Rows) != 1 || months.Rows[0].Period != "2024-03" || months.Rows[0].Abandoned != 2 || len(months.Bins) != 8 {
+		t.Errorf("months = %+v", months)
+	}
+
+	if _, err := app.GetAbandonWaits("", "2024-03-01", "2024-03-02", "short", "week"); err == nil {
+		t.Error("expected error for unsupported granularity")
+	}
+
+	// Ожидание на границе относится к интервалу, который она открывает, как
+	// в гистограмме длительности: 30 с — в 30-35s, 35 с на пороге — не короткий
+	edge := newFakeSource([]fakeCall{
+		{EnterQueue: at("2024-03-01 10:05:00"), Type: "abandon", Queue: "m10", Wait: 30},
+		{EnterQueue: at("2024-03-01 10:10:00"), Type: "abandon", Queue: "m10", Wait: 35},
+	}, nil, nil)
+	edge.Queues = source.Queues
+	onEdge, err := (&App{repos: edge}).GetAbandonWaits("", "2024-03-01", "2024-03-01", "short", "day")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (WaitHistogramRow{Bins: bins(map[int]int{4: 1, 5: 1}), Abandoned: 1, Short: 1}); !reflect.DeepEqual(onEdge.Total, want) {
+		t.Errorf("edge total = %+v, want %+v", onEdge.Total, want)
+	}
+}
+
+func TestGetDurationDistribution(t *testing.T) {
+	app := newTestApp()
+
+	// Длительности отвеченных звонков: 61, 120, 240, 300
+	aht, err := app.GetDurationDistribution("", "2024-03-01", "2024-03-02", "all", "aht", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aht.Metric != "aht" || aht.Count != 4 || aht.Median != 180 || aht.Max != 300 || aht.Mean != 180 {
+		t.Errorf("aht = %+v", aht)
+	}
+
+	trimmed, err := app.GetDurationDistribution("", "2024-03-01", "2024-03-02", "all", "aht", 250)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trimmed.Count != 3 || trimmed.Trimmed != 1 || trimmed.Max != 240 || trimmed.Median != 120 {
+		t.Errorf("trimmed = %+v", trimmed)
+	}
+
+	rt, err := app.GetDurationDistribution("", "2024-03-01", "2024-03-03", "all", "rt", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt.Count != 3 || rt.Median != 600 || rt.Max != 1200 {
+		t.Errorf("rt = %+v", rt)
+	}
+
+	if _, err := app.GetDurationDistribution("", "2024-03-01", "2024-03-02", "all", "sl", 0); err == nil {
+		t.Error("expected error for unsupported metric")
+	}
+	if _, err := app.GetDurationDistribution("", "2024-03-01", "2024-03-02", "all", "aht", -1); err == nil {
+		t.Error("expected error for negative cap")
+	}
+}
+
+func TestGetAgentPerformance(t *testing.T) {
+	app := newTestApp()
+
+	report, err := app.GetAgentPerformance("", "2024-03-01", "2024-03-02", "all", "handled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a1: два звонка и чат в том же интервале 09:00, что и звонок, — два
+	// активных интервала; a2, a3 и a6 делят второе место
+	var order []string
+	var ranks []int
+	for _, agent := range report.Agents {
+		order = append(order, agent.UserID)
+		ranks = append(ranks, agent.Rank)
+	}
+	if !reflect.DeepEqual(order, []string{"a1", "a2", "a3", "a6"}) || !reflect.DeepEqual(ranks, []int{1, 2, 2, 2}) {
+		t.Fatalf("order = %v, ranks = %v", order, ranks)
+	}
+	first := report.Agents[0]
+	want := AgentMetrics{Calls: 2, Chats: 1, Handled: 3, AHT: 91, FRT: 20, RT: 600, ActiveHours: 0.5}
+	if first.Name != "Aysel Mammadova" || first.Metrics != want {
+		t.Errorf("a1 = %+v", first)
+	}
+	if first.VsMedian != 200 || !first.BetterThanMedian || report.Agents[1].BetterThanMedian {
+		t.Errorf("vs median: a1 = %v/%v, a2 = %v", first.VsMedian, first.BetterThanMedian, report.Agents[1].BetterThanMedian)
+	}
+	// Агенты без имени в справочнике показываются по user_id
+	if report.Agents[2].Name != "a3" {
+		t.Errorf("a3 name = %q", report.Agents[2].Name)
+	}
+	if report.Median.Handled != 1 || report.Median.Chats != 0.5 || report.Median.AHT != 240 || report.Median.FRT != 13 {
+		t.Errorf("median = %+v", report.Median)
+	}
+
+	// Длительности: меньше — лучше; агент без звонков без места и в конце
+	aht, err := app.GetAgentPerformance("", "2024-03-01", "2024-03-02", "all", "aht")
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, ranks = nil, nil
+	for _, agent := range aht.Agents {
+		order = append(order, agent.UserID)
+		ranks = append(ranks, agent.Rank)
+	}
+	if !reflect.DeepEqual(order, []string{"a1", "a2", "a3", "a6"}) || !reflect.DeepEqual(ranks, []int{1, 2, 3, 0}) {
+		t.Errorf("aht order = %v, ranks = %v", order, ranks)
+	}
+	if got := aht.Agents[0]; got.VsMedian != -62.08 || !got.BetterThanMedian {
+		t.Errorf("a1 aht vs median = %v/%v", got.VsMedian, got.BetterThanMedian)
+	}
+
+	if _, err := app.GetAgentPerformance("", "2024-03-01", "2024-03-02", "all", "sl"); err == nil {
+		t.Error("expected error for unsupported sort")
+	}
+}
+
+func TestGetAgentDetail(t *testing.T) {
+	app := newTestApp()
+
+	detail, err := app.GetAgentDetail("", "2024-03-01", "2024-03-02", "all", "a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Те же показатели, что у a1 в рейтинге агентов
+	want := AgentMetrics{Calls: 2, Chats: 1, Handled: 3, AHT: 91, FRT: 20, RT: 600, ActiveHours: 0.5}
+	if detail.Name != "Aysel Mammadova" || detail.Metrics != want {
+		t.Errorf("a1 = %q %+v", detail.Name, detail.Metrics)
+	}
+	if !reflect.DeepEqual(detail.Daily, []AgentDay{{"2024-03-01", 1, 1}, {"2024-03-02", 1, 0}}) {
+		t.Errorf("daily = %+v", detail.Daily)
+	}
+	if len(detail.Hourly) != 24 || detail.Hourly[9] != (AgentHour{9, 1, 1, 17.08}) || detail.Hourly[14] != (AgentHour{14, 1, 0, 1.69}) {
+		t.Errorf("hourly = %+v", detail.Hourly)
+	}
+	if detail.AHT.Count != 2 || detail.AHT.Median != 91 || detail.AHT.Max != 120 || detail.FRT.Median != 20 || detail.RT.Count != 1 {
+		t.Errorf("distributions: aht %+v, frt %+v, rt %+v", detail.AHT, detail.FRT, detail.RT)
+	}
+	// Звонок 09:10:15-09:12:15 внутри чата 09:02-09:12 продлевает занятость до 09:12:15
+	if detail.Occupancy != 9.39 {
+		t.Errorf("occupancy = %v", detail.Occupancy)
+	}
+	wantConcurrency := AgentConcurrency{Chats: 1, Max: 1, PeakAt: "2024-03-01 09:02:00", Average: 1}
+	if detail.Concurrency != wantConcurrency {
+		t.Errorf("concurrency = %+v", detail.Concurrency)
+	}
+	wantTopics := []AgentTopic{{"Billing", 2, 66.67}, {"Account", 1, 33.33}}
+	if !reflect.DeepEqual(detail.Topics, wantTopics) {
+		t.Errorf("topics = %+v", detail.Topics)
+	}
+	var queries []string
+	for _, timing := range detail.Timings {
+		queries = append(queries, timing.Query)
+	}
+	if !reflect.DeepEqual(queries, []string{"calls", "chats", "topics", "names"}) {
+		t.Errorf("timings = %v", queries)
+	}
+
+	// Без MongoDB топики пустые, остальное считается
+	source := newFakeSource(testCalls, testChats, testRequests)
+	source.Classifiers = nil
+	detail, err = (&App{repos: source}).GetAgentDetail("", "2024-03-01", "2024-03-02", "all", "a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(detail.Topics) != 0 || detail.Metrics.Handled != 3 {
+		t.Errorf("without MongoDB: topics = %+v, handled = %d", detail.Topics, detail.Metrics.Handled)
+	}
+
+	if _, err := app.GetAgentDetail("", "2024-03-01", "2024-03-02", "all", ""); err == nil {
+		t.Error("expected error for empty user")
+	}
+}
+
+func TestGetMonthlyData(t *testing.T) {
+	report, err := newTestApp().GetMonthlyData("", "2024-03-01", "2024-03-31", "all")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantCalls := []MonthlyCallDay{
+		{Month: "2024-03", Day: 1, TotalCalls: 4, AvgCallDuration: 220, SL: 66.67, TotalAbandoned: 1, AbandonRate: 25,
+			ASA: 28, MaxWait: 60, WaitP50: 15, WaitP90: 51, WaitP95: 56, DistinctAgents: 3},
+		{Month: "2024-03", Day: 2, TotalCalls: 2, AvgCallDuration: 61, SL: 100, TotalAbandoned: 1, AbandonRate: 50,
+			ASA: 5, MaxWait: 5, WaitP50: 5, WaitP90: 5, WaitP95: 5, DistinctAgents: 2},
+	}
+	if !reflect.DeepEqual(report.Calls, wantCalls) {
+		t.Errorf("calls:\n got %+v\nwant %+v", report.Calls, wantCalls)
+	}
+	wantChats := []MonthlyChatDay{
+		{Month: "2024-03", Day: 1, TotalChats: 2, AvgChatFRT: 60, ResolutionTimeAvg: 900},
+		{Month: "2024-03", Day: 3, TotalChats: 1, AvgChatFRT: 10, ResolutionTimeAvg: 100},
+	}
+	if !reflect.DeepEqual(report.Chats, wantChats) {
+		t.Errorf("chats:\n got %+v\nwant %+v", report.Chats, wantChats)
+	}
+}
+
+func TestGetQueueStats(t *testing.T) {
+	stats, err := newTestApp().GetQueueStats("", "2024-03-01", "2024-03-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []QueueInfo{
+		{Name: "aml-chat", Chats: 1, FirstSeen: "2024-03-02", LastSeen: "2024-03-02", Groups: []string{}, Unassigned: true},
+		{Name: "other", Calls: 1, FirstSeen: "2024-03-02", LastSeen: "2024-03-02", Groups: []string{}, Unassigned: true},
+		{Name: "m10", Calls: 4, Requests: 3, FirstSeen: "2024-03-01", LastSeen: "2024-03-02", Groups: []string{"all", "m10"}},
+		{Name: "WHATSAPP", Chats: 2, Requests: 1, FirstSeen: "2024-03-01", LastSeen: "2024-03-02", Groups: []string{"all", "m10"}},
+		{Name: "m10-shikayet", Calls: 2, Requests: 1, FirstSeen: "2024-03-01", LastSeen: "2024-03-02", Groups: []string{"all", "aml"}},
+		{Name: "m10 Instagram", Chats: 1, FirstSeen: "2024-03-02", LastSeen: "2024-03-02", Groups: []string{"all", "m10"}},
+		{Name: "telegram", Chats: 1, FirstSeen: "2024-03-01", LastSeen: "2024-03-01", Groups: []string{"all", "m10"}},
+	}
+	if !reflect.DeepEqual(stats.Queues, want) {
+		t.Errorf("queues:\n got %+v\nwant %+v", stats.Queues, want)
+	}
+	if stats.Unassigned != 2 {
+		t.Errorf("unassigned = %d, want 2", stats.Unassigned)
+	}
+	if !reflect.DeepEqual(stats.Sources, []string{"call_report", "chat_report", "request"}) {
+		t.Errorf("sources = %v", stats.Sources)
+	}
+
+	// Канал, указанный только в call_queues, для чатов остается неразмеченным
+	source := newFakeSource(testCalls, testChats, testRequests)
+	source.Queues = QueueRegistry{{ID: "mixed", CallQueues: []string{"m10", "m10-shikayet", "other", "aml-chat"}}}
+	source.Classifiers = nil
+	stats, err = (&App{repos: source}).GetQueueStats("", "2024-03-01", "2024-03-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(stats.Sources, []string{"call_report", "chat_report"}) {
+		t.Errorf("sources without MongoDB = %v", stats.Sources)
+	}
+	for _, queue := range stats.Queues {
+		wantUnassigned := queue.Chats > 0
+		if queue.Unassigned != wantUnassigned || queue.Requests != 0 {
+			t.Errorf("queue %+v: unassigned = %v, want %v", queue, queue.Unassigned, wantUnassigned)
+		}
+	}
+}
+
+func TestClassifiers(t *testing.T) {
+	app := newTestApp()
+	const start, end = "2024-03-01", "2024-03-02"
+
+	classifiers := func(t *testing.T, result *ClassifierReport, err error) []ClassifierResult {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result.Data
+	}
+
+	t.Run("calls use the call queues of the group", func(t *testing.T) {
+		result, err := app.GetCallClassifiers("", start, end, "all")
+		want := []ClassifierResult{
+			{ReportDate: "2024-03-01", Topic: "Billing", Subtopic: "Refund/Card", Total: 1},
+			{ReportDate: "2024-03-01", Topic: "Complaint", Subtopic: "", Total: 1},
+			{ReportDate: "2024-03-02", Topic: "Cards", Subtopic: "Limit", Total: 1},
+		}
+		if got := classifiers(t, result, err); !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("group id is case-insensitive", func(t *testing.T) {
+		want := []ClassifierResult{{ReportDate: "2024-03-01", Topic: "Complaint", Subtopic: "", Total: 1}}
+		for _, queueName := range []string{"aml", "AML"} {
+			result, err := app.GetCallClassifiers("", start, end, queueName)
+			if got := classifiers(t, result, err); !reflect.DeepEqual(got, want) {
+				t.Fatalf("%s: got %+v, want %+v", queueName, got, want)
+			}
+		}
+	})
+
+	t.Run("chats are empty for aml", func(t *testing.T) {
+		result, err := app.GetChatClassifiers("", start, end, "aml")
+		if got := classifiers(t, result, err); len(got) != 0 {
+			t.Fatalf("expected no chat classifiers, got %+v", got)
+		}
+	})
+
+	t.Run("overall merges calls and chats", func(t *testing.T) {
+		result, err := app.GetOverallClassifiers("", start, end, "all")
+		want := []ClassifierResult{
+			{ReportDate: "2024-03-01", Topic: "Account", Subtopic: "", Total: 1},
+			{ReportDate: "2024-03-01", Topic: "Billing", Subtopic: "Refund/Card", Total: 2},
+			{ReportDate: "2024-03-01", Topic: "Complaint", Subtopic: "", Total: 1},
+			{ReportDate: "2024-03-02", Topic: "Cards", Subtopic: "Limit", Total: 1},
+		}
+		if got := classifiers(t, result, err); !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("topics", func(t *testing.T) {
+		result, err := app.GetTopics("", start, end, "all")
+		if err != nil {
+			t.Fatal(err)
+		}
+		topics := result.Data
+		want := map[string]TopicResult{
+			"2024-03-01|Billing":   {ReportDate: "2024-03-01", Topic: "Billing", Total: 2, Ratio: 50},
+			"2024-03-01|Account":   {ReportDate: "2024-03-01", Topic: "Account", Total: 1, Ratio: 25},
+			"2024-03-01|Complaint": {ReportDate: "2024-03-01", Topic: "Complaint", Total: 1, Ratio: 25},
+			"2024-03-02|Cards":     {ReportDate: "2024-03-02", Topic: "Cards", Total: 1, Ratio: 100},
+		}
+		if len(topics) != len(want) {
+			t.Fatalf("got %+v", topics)
+		}
+		for _, topic := range topics {
+			if w := want[topic.ReportDate+"|"+topic.Topic]; topic != w {
+				t.Errorf("got %+v, want %+v", topic, w)
+			}
+		}
+		if topics[0].Topic != "Billing" {
+			t.Errorf("topics of a day must be sorted by total, got %+v", topics)
+		}
+	})
+
+	t.Run("available topics", func(t *testing.T) {
+		result, err := app.GetAvailableTopics("", start, end, "m10")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"Account", "Billing", "Cards"}; !reflect.DeepEqual(result, want) {
+			t.Fatalf("got %v, want %v", result, want)
+		}
+	})
+
+	t.Run("subtopics of a topic", func(t *testing.T) {
+		result, err := app.GetSubtopicsDaily("", start, end, "all", "Billing")
+		want := []ClassifierResult{
+			{ReportDate: "2024-03-01", Topic: "Billing", Subtopic: "Refund/Card", Total: 2},
+		}
+		if got := classifiers(t, result, err); !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+	})
+}
+
+func TestConfiguredQueueGroups(t *testing.T) {
+	source := newFakeSource(testCalls, testChats, testRequests)
+	source.Queues = QueueRegistry{
+		{ID: "complaints", Label: "Жалобы", CallQueues: []string{"m10-shikayet"}},
+		{ID: "whatsapp", Label: "WhatsApp", ChatChannels: []string{"WHATSAPP"}},
+	}
+	app := &App{repos: source}
+
+	groups := app.GetQueueGroups()
+	if len(groups) != 2 || groups[0].ID != "complaints" {
+		t.Fatalf("GetQueueGroups = %+v", groups)
+	}
+
+	// Один и тот же id группы задает очереди и для SQL, и для MongoDB
+	result, err := app.GetDailyData("", "2024-03-01", "2024-03-02", "Complaints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPoints(t, "complaints calls", result.Calls, []DailyPoint{
+		{"2024-03-01", 1},
+		{"2024-03-02", 1},
+	})
+
+	topics, err := app.GetTopics("", "2024-03-01", "2024-03-02", "whatsapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics.Data) != 2 {
+		t.Fatalf("whatsapp topics = %+v", topics.Data)
+	}
+}
+
+func TestDataRequiresConnection(t *testing.T) {
+	app := &App{repos: fakeSource{}}
+
+	if _, err := app.GetDailyData("", "2024-03-01", "2024-03-02", "all"); err == nil || !strings.Contains(err.Error(), "MySQL") {
+		t.Fatalf("expected MySQL error, got %v", err)
+	}
+	if _, err := app.GetOverallClassifiers("", "2024-03-01", "2024-03-02", "all"); err == nil || !strings.Contains(err.Error(), "MongoDB") {
+		t.Fatalf("expected MongoDB error, got %v", err)
+	}
+}
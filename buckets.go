@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Движок интервалов: репозиторий группирует записи по дню и номеру интервала
+// (GROUP BY DATE, интервал), а сводная таблица «день × интервалы» строится
+// здесь. Метрика описывается один раз и работает с любой шириной интервала.
+
+// bucketWidths допустимая ширина интервала в минутах
+var bucketWidths = []int{15, 30, 60}
+
+// BucketValue значение метрики в интервале дня. Bucket — номер интервала
+// от полуночи: при ширине 15 минут 09:40 попадает в интервал 38.
+type BucketValue struct {
+	Date   string
+	Bucket int
+	Value  float64
+}
+
+// BucketRow значения метрики за день по интервалам; nil — в интервале нет данных
+type BucketRow struct {
+	Date   string
+	Values []*float64
+}
+
+// checkBucketWidth проверяет ширину интервала
+func checkBucketWidth(width int) error {
+	if !slices.Contains(bucketWidths, width) {
+		return fmt.Errorf("неподдерживаемая ширина интервала: %d мин (допустимо %v)", width, bucketWidths)
+	}
+	return nil
+}
+
+// bucketCount число интервалов в сутках
+func bucketCount(width int) int {
+	return 24 * 60 / width
+}
+
+// pivotBuckets разворачивает значения (день, интервал) в строки по дням
+func pivotBuckets(values []BucketValue, width int) []BucketRow {
+	slots := bucketCount(width)
+	byDate := make(map[string][]*float64)
+	for _, value := range values {
+		if value.Bucket < 0 || value.Bucket >= slots {
+			continue
+		}
+		row := byDate[value.Date]
+		if row == nil {
+			row = make([]*float64, slots)
+			byDate[value.Date] = row
+		}
+		v := value.Value
+		row[value.Bucket] = &v
+	}
+
+	rows := make([]BucketRow, 0, len(byDate))
+	for _, date := range sortedKeys(byDate) {
+		rows = append(rows, BucketRow{Date: date, Values: byDate[date]})
+	}
+	return rows
+}
+
+// intervalMetric метрика по интервалам: источник данных, округление для ответа
+// и способ усреднения по дням
+type intervalMetric struct {
+	chats  bool // метрика chat_report, иначе call_report
+	volume bool // объем: средний день делит сумму на все дни периода
+	round  func(float64) float64
+}
+
+// intervalMetrics метрики отчетов по интервалам. agents и total собираются
+// из активности агентов и из calls и chats, occupancy — из обработок агентов,
+// остальные считают репозитории.
+var intervalMetrics = map[string]intervalMetric{
+	"calls":        {volume: true, round: asCount},
+	"aht":          {round: asSeconds},
+	"sl":           {round: asPercent},
+	"abandoned":    {volume: true, round: asCount},
+	"abandon_rate": {round: asPercent},
+	"chats":        {chats: true, volume: true, round: asCount},
+	"frt":          {chats: true, round: asSeconds},
+	"rt":           {chats: true, round: asSeconds},
+	"asa":          {round: asSeconds},
+	"max_wait":     {round: asSeconds},
+	"wait_p50":     {round: asSeconds},
+	"wait_p90":     {round: asSeconds},
+	"wait_p95":     {round: asSeconds},
+	"agents":       {volume: true, round: asCount},
+	"total":        {volume: true, round: asCount},
+	"occupancy":    {round: asPercent},
+}
+
+// metricBuckets значения метрики группы очередей по интервалам дня
+func metricBuckets(ctx context.Context, repos Repositories, group QueueGroup, metric, startDate, endDate string, width int) ([]BucketRow, error) {
+	definition, ok := intervalMetrics[metric]
+	if !ok {
+		return nil, fmt.Errorf("неподдерживаемая метрика: %s", metric)
+	}
+	if err := checkBucketWidth(width); err != nil {
+		return nil, err
+	}
+
+	var values []BucketValue
+	var err error
+	if definition.chats {
+		values, err = repos.Chats.Buckets(ctx, metric, startDate, endDate, width, group.ChatChannels)
+	} else {
+		// SL и брошенные считаются с порогами группы очередей
+		values, err = repos.Calls.Buckets(ctx, metric, startDate, endDate, width, group.CallThresholds(), group.CallQueues)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pivotBuckets(values, width), nil
+}
+
+// metricIntervals значения любой метрики отчетов по дням и интервалам дня
+func metricIntervals(ctx context.Context, repos Repositories, group QueueGroup, metric, startDate, endDate string, width int) ([]BucketRow, error) {
+	if err := checkBucketWidth(width); err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(waitMetrics, metric) {
+		// Перцентили ожидания считаются в Go из количеств по минутам
+		counts, err := repos.Calls.AnswerWaits(ctx, startDate, endDate, group.CallQueues)
+		if err != nil {
+			return nil, err
+		}
+		return waitBuckets(metric, width, counts), nil
+	}
+
+	switch metric {
+	case "agents":
+		// Уникальные агенты за интервал по звонкам и чатам вместе
+		callActivity, chatActivity, err := agentActivity(ctx, repos, startDate, endDate, group)
+		if err != nil {
+			return nil, err
+		}
+		return agentBuckets(width, callActivity, chatActivity), nil
+
+	case "occupancy":
+		// Занятость агентов, работавших в интервале, по разговорам и чатам
+		calls, chats, err := handleSpans(ctx, repos, startDate, endDate, group)
+		if err != nil {
+			return nil, err
+		}
+		return occupancyBuckets(width, busySeconds(width, startDate, endDate, calls, chats)), nil
+
+	case "total":
+		// Звонки и чаты вместе
+		calls, err := metricBuckets(ctx, repos, group, "calls", startDate, endDate, width)
+		if err != nil {
+			return nil, err
+		}
+		chats, err := metricBuckets(ctx, repos, group, "chats", startDate, endDate, width)
+		if err != nil {
+			return nil, err
+		}
+		return sumBuckets(width, calls, chats), nil
+	}
+
+	return metricBuckets(ctx, repos, group, metric, startDate, endDate, width)
+}
+
+// agentBuckets уникальные агенты по дням и интервалам шириной width минут
+func agentBuckets(width int, sources ...[]AgentActivity) []BucketRow {
+	type dayBucket struct {
+		date   string
+		bucket int
+	}
+	counts := countAgents(func(e AgentActivity) dayBucket {
+		return dayBucket{e.Date, e.Minute / width}
+	}, sources...)
+
+	values := make([]BucketValue, 0, len(counts))
+	for key, count := range counts {
+		values = append(values, BucketValue{Date: key.date, Bucket: key.bucket, Value: float64(count)})
+	}
+	return pivotBuckets(values, width)
+}
+
+// sumBuckets складывает строки нескольких метрик по дням и интервалам
+func sumBuckets(width int, sources ...[]BucketRow) []BucketRow {
+	type dayBucket struct {
+		date   string
+		bucket int
+	}
+	sums := make(map[dayBucket]float64)
+	for _, rows := range sources {
+		for _, row := range rows {
+			for bucket, value := range row.Values {
+				if value != nil {
+					sums[dayBucket{row.Date, bucket}] += *value
+				}
+			}
+		}
+	}
+
+	values := make([]BucketValue, 0, len(sums))
+	for key, sum := range sums {
+		values = append(values, BucketValue{Date: key.date, Bucket: key.bucket, Value: sum})
+	}
+	return pivotBuckets(values, width)
+}
+
+// intervalLabels подписи интервалов: начало интервала ЧЧ:ММ местного времени
+func intervalLabels(width int) []string {
+	labels := make([]string, bucketCount(width))
+	for i := range labels {
+		minute := i * width
+		labels[i] = fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+	}
+	return labels
+}
+
+// averageDay средний день периода из days дней. Объемы делятся на все дни
+// периода, включая дни без данных; средние и проценты усредняются по дням,
+// где в интервале были данные.
+func averageDay(rows []BucketRow, width, days int, volume bool) []float64 {
+	sums := make([]float64, bucketCount(width))
+	counts := make([]int, len(sums))
+	for _, row := range rows {
+		for i, value := range row.Values {
+			if value != nil {
+				sums[i] += *value
+				counts[i]++
+			}
+		}
+	}
+
+	for i := range sums {
+		switch {
+		case volume && days > 0:
+			sums[i] /= float64(days)
+		case !volume && counts[i] > 0:
+			sums[i] /= float64(counts[i])
+		}
+		sums[i] = roundTo(sums[i], 2)
+	}
+	return sums
+}
@@ -3,177 +3,717 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // Конфигурация базы данных
 type DatabaseConfig struct {
-	MySQL   MySQLConfig   `json:"mysql"`
-	MongoDB MongoDBConfig `json:"mongodb"`
+	MySQL   MySQLConfig   `json:"mysql" yaml:"mysql"`
+	MongoDB MongoDBConfig `json:"mongodb" yaml:"mongodb"`
+	// SSH необязательный jump-host, через который пробрасываются порты обеих баз
+	SSH SSHConfig `json:"ssh" yaml:"ssh,omitempty"`
+	// QueueGroups группы очередей для фильтра дашборда; пусто — группы по умолчанию
+	QueueGroups []QueueGroup `json:"queue_groups,omitempty" yaml:"queue_groups,omitempty"`
 }
 
 // Конфигурация MySQL
 type MySQLConfig struct {
-	Host     string `json:"host"`
-	Port     string `json:"port"`
-	User     string `json:"user"`
-	Password string `json:"password"`
-	Database string `json:"database"`
+	Host     string    `json:"host" yaml:"host"`
+	Port     string    `json:"port" yaml:"port"`
+	User     string    `json:"user" yaml:"user"`
+	Password string    `json:"password,omitempty" yaml:"password,omitempty"`
+	Database string    `json:"database" yaml:"database"`
+	TLS      TLSConfig `json:"tls" yaml:"tls,omitempty"`
 }
 
 // Конфигурация MongoDB
 type MongoDBConfig struct {
-	Host     string `json:"host"`
-	Port     string `json:"port"`
-	User     string `json:"user"`
-	Password string `json:"password"`
-	Database string `json:"database"`
+	// URI полная строка подключения mongodb:// или mongodb+srv:// (несколько
+	// хостов, replicaSet, readPreference, maxStalenessSeconds). Если задана,
+	// Host и Port не используются.
+	URI      string `json:"uri,omitempty" yaml:"uri,omitempty"`
+	Host     string `json:"host" yaml:"host"`
+	Port     string `json:"port" yaml:"port"`
+	User     string `json:"user" yaml:"user"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+	Database string `json:"database" yaml:"database"`
+	// AuthSource база аутентификации: по умолчанию admin, для X.509 - $external
+	AuthSource string `json:"auth_source,omitempty" yaml:"auth_source,omitempty"`
+	// AuthMechanism SCRAM-SHA-1, SCRAM-SHA-256 или MONGODB-X509;
+	// пустое значение - механизм согласуется с сервером
+	AuthMechanism string    `json:"auth_mechanism,omitempty" yaml:"auth_mechanism,omitempty"`
+	TLS           TLSConfig `json:"tls" yaml:"tls,omitempty"`
+}
+
+// Механизмы аутентификации MongoDB
+const (
+	mongoAuthSCRAMSHA1   = "SCRAM-SHA-1"
+	mongoAuthSCRAMSHA256 = "SCRAM-SHA-256"
+	mongoAuthX509        = "MONGODB-X509"
+)
+
+// String скрывает пароль, чтобы конфигурацию можно было безопасно логировать
+func (c MySQLConfig) String() string {
+	return fmt.Sprintf("%s@%s:%s/%s (%s)", c.User, c.Host, c.Port, c.Database, c.TLS)
+}
+
+// String скрывает пароль, чтобы конфигурацию можно было безопасно логировать
+func (c MongoDBConfig) String() string {
+	if c.URI != "" {
+		return fmt.Sprintf("%s (%s)", withoutURIPassword(c.URI), c.TLS)
+	}
+
+	credential := c.credential("")
+	mechanism := credential.AuthMechanism
+	if mechanism == "" {
+		mechanism = "SCRAM"
+	}
+	return fmt.Sprintf("%s@%s:%s/%s (%s, authSource %s, %s)", c.User, c.Host, c.Port, c.Database,
+		mechanism, credential.AuthSource, c.TLS)
+}
+
+// mongoURIParts части строки подключения MongoDB, которые нужны приложению
+type mongoURIParts struct {
+	scheme   string
+	userinfo string
+	user     string
+	password string
+	hosts    []string
+	database string
+	rest     string
+}
+
+// parseMongoURI разбирает строку подключения без обращения к DNS
+// (записи mongodb+srv:// раскрывает драйвер при подключении)
+func parseMongoURI(uri string) (mongoURIParts, error) {
+	var parts mongoURIParts
+
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok || (scheme != "mongodb" && scheme != "mongodb+srv") {
+		return parts, fmt.Errorf("строка подключения должна начинаться с mongodb:// или mongodb+srv://")
+	}
+	parts.scheme = scheme
+
+	hosts := rest
+	if i := strings.IndexAny(rest, "/?"); i >= 0 {
+		hosts, parts.rest = rest[:i], rest[i:]
+	}
+	if at := strings.LastIndex(hosts, "@"); at >= 0 {
+		parts.userinfo = hosts[:at]
+		user, password, _ := strings.Cut(parts.userinfo, ":")
+		var err error
+		if parts.user, err = url.PathUnescape(user); err != nil {
+			return parts, fmt.Errorf("некорректное имя пользователя в строке подключения")
+		}
+		if parts.password, err = url.PathUnescape(password); err != nil {
+			return parts, fmt.Errorf("некорректный пароль в строке подключения")
+		}
+		hosts = hosts[at+1:]
+	}
+	for _, host := range strings.Split(hosts, ",") {
+		if host == "" {
+			return parts, fmt.Errorf("в строке подключения не указаны хосты")
+		}
+		parts.hosts = append(parts.hosts, host)
+	}
+
+	if strings.HasPrefix(parts.rest, "/") {
+		parts.database, _, _ = strings.Cut(parts.rest[1:], "?")
+	}
+	return parts, nil
+}
+
+// withoutURIPassword убирает пароль из строки подключения, оставляя пользователя
+func withoutURIPassword(uri string) string {
+	parts, err := parseMongoURI(uri)
+	if err != nil {
+		return "<некорректная строка подключения>"
+	}
+	if parts.password == "" {
+		return uri
+	}
+	user, _, _ := strings.Cut(parts.userinfo, ":")
+	return parts.scheme + "://" + user + "@" + strings.Join(parts.hosts, ",") + parts.rest
+}
+
+// uriPassword возвращает пароль, указанный прямо в строке подключения
+func (c MongoDBConfig) uriPassword() string {
+	if c.URI == "" {
+		return ""
+	}
+	parts, _ := parseMongoURI(c.URI)
+	return parts.password
+}
+
+// configuredPassword возвращает пароль, заданный в конфигурации явно:
+// полем password (окружение, флаги) или в строке подключения
+func (c MongoDBConfig) configuredPassword() string {
+	if c.Password != "" {
+		return c.Password
+	}
+	return c.uriPassword()
+}
+
+// database возвращает имя базы приложения: явно заданное или из строки подключения
+func (c MongoDBConfig) database() string {
+	if c.Database != "" || c.URI == "" {
+		return c.Database
+	}
+	parts, _ := parseMongoURI(c.URI)
+	return parts.database
+}
+
+// withURIAuth дополняет незаданные поля аутентификации значениями
+// из строки подключения; явно заданные поля имеют приоритет
+func (c MongoDBConfig) withURIAuth(auth *options.Credential) MongoDBConfig {
+	if auth == nil {
+		return c
+	}
+	if c.User == "" {
+		c.User = auth.Username
+	}
+	if c.AuthMechanism == "" {
+		c.AuthMechanism = auth.AuthMechanism
+	}
+	if c.AuthSource == "" {
+		c.AuthSource = auth.AuthSource
+	}
+	return c
+}
+
+// needsPassword сообщает, нужен ли пароль: при X.509 аутентификация идет по сертификату
+func (c MongoDBConfig) needsPassword() bool {
+	return c.AuthMechanism != mongoAuthX509
+}
+
+// credential возвращает учетные данные MongoDB с учетом явно заданных
+// authSource и механизма аутентификации
+func (c MongoDBConfig) credential(password string) options.Credential {
+	if c.AuthMechanism == mongoAuthX509 {
+		source := c.AuthSource
+		if source == "" {
+			source = "$external"
+		}
+		// Имя пользователя можно не указывать: сервер возьмет его из сертификата
+		return options.Credential{AuthMechanism: c.AuthMechanism, AuthSource: source, Username: c.User}
+	}
+
+	source := c.AuthSource
+	if source == "" {
+		source = "admin"
+	}
+	return options.Credential{
+		AuthMechanism: c.AuthMechanism,
+		AuthSource:    source,
+		Username:      c.User,
+		Password:      password,
+		PasswordSet:   true,
+	}
+}
+
+// Сколько ждать отключения замененного клиента MongoDB
+const retireTimeout = time.Minute
+
+// mysqlConn соединение MySQL со счетчиком запросов, которые его используют,
+// и SSH-туннелем, если база доступна только через jump-host
+type mysqlConn struct {
+	db      *sql.DB
+	forward *sshForward
+	refs    sync.WaitGroup
+}
+
+// mongoConn соединение MongoDB со счетчиком запросов, которые его используют,
+// и SSH-туннелем, если база доступна только через jump-host
+type mongoConn struct {
+	db      *mongo.Database
+	forward *sshForward
+	refs    sync.WaitGroup
 }
 
 // Сервис для работы с базами данных
 type DatabaseService struct {
-	mysqlDB *sql.DB
-	mongoDB *mongo.Database
-	config  DatabaseConfig
+	mu         sync.RWMutex
+	mysql      *mysqlConn
+	mongo      *mongoConn
+	profiles   ConnectionProfiles
+	configPath string
+	secrets    SecretStore
+	closed     bool
+	// open открывает соединения профиля; в тестах подменяется
+	open func(profile string, config DatabaseConfig) (*mysqlConn, *mongoConn, error, error)
+}
+
+// Создание нового сервиса баз данных с профилями подключения и хранилищем секретов
+func NewDatabaseService(profiles ConnectionProfiles, configPath string, secrets SecretStore) *DatabaseService {
+	ds := &DatabaseService{
+		profiles:   profiles,
+		configPath: configPath,
+		secrets:    secrets,
+	}
+	ds.open = ds.openConfig
+	return ds
+}
+
+// Session набор соединений, закрепленный за одним запросом frontend.
+// Пока сессия не закрыта, замененные при переподключении соединения
+// не закрываются, поэтому начатые запросы завершаются корректно.
+// Оба соединения сессии всегда принадлежат одному профилю.
+type Session struct {
+	Profile string
+	MySQL   *sql.DB
+	MongoDB *mongo.Database
+	Queues  QueueRegistry
+	release func()
 }
 
-// Создание нового сервиса баз данных
-func NewDatabaseService() *DatabaseService {
-	config := DatabaseConfig{
-		MySQL: MySQLConfig{
-			Host:     "192.168.46.4",
-			Port:     "3306",
-			User:     "pashapay",
-			Password: "Q1w2e3r4!@#",
-			Database: "report",
-		},
-		MongoDB: MongoDBConfig{
-			Host:     "192.168.46.4",
-			Port:     "27017",
-			User:     "readonly",
-			Password: "q1w2e3r4!@#",
-			Database: "request",
-		},
+// Close освобождает соединения сессии
+func (s *Session) Close() {
+	s.release()
+}
+
+// Session закрепляет текущие соединения за запросом
+func (ds *DatabaseService) Session() *Session {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	session := &Session{
+		Profile: ds.profiles.Active,
+		Queues:  QueueRegistry(ds.profiles.Config().QueueGroups),
+	}
+	mysqlConn, mongoConn := ds.mysql, ds.mongo
+	if mysqlConn != nil {
+		mysqlConn.refs.Add(1)
+		session.MySQL = mysqlConn.db
+	}
+	if mongoConn != nil {
+		mongoConn.refs.Add(1)
+		session.MongoDB = mongoConn.db
 	}
 
-	return &DatabaseService{
-		config: config,
+	var once sync.Once
+	session.release = func() {
+		once.Do(func() {
+			if mysqlConn != nil {
+				mysqlConn.refs.Done()
+			}
+			if mongoConn != nil {
+				mongoConn.refs.Done()
+			}
+		})
 	}
+	return session
+}
+
+// Config возвращает конфигурацию активного профиля
+func (ds *DatabaseService) Config() DatabaseConfig {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return ds.profiles.Config()
+}
+
+// ActiveProfile возвращает имя активного профиля
+func (ds *DatabaseService) ActiveProfile() string {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return ds.profiles.Active
+}
+
+// ConfigPath возвращает путь к файлу конфигурации
+func (ds *DatabaseService) ConfigPath() string {
+	return ds.configPath
+}
+
+// profileSecretKey возвращает ключ секрета профиля. Для профиля "default"
+// ключ не меняется, чтобы ранее сохраненные пароли продолжали работать.
+func profileSecretKey(profile, key string) string {
+	if profile == defaultProfileName || profile == "" {
+		return key
+	}
+	return "profiles/" + profile + "/" + key
+}
+
+// resolvePassword возвращает пароль для подключения профиля. Пароль, явно
+// переданный через переменную окружения, флаг или форму профиля, имеет
+// приоритет, иначе он берется из хранилища секретов. Пароли из файла
+// конфигурации сюда не доходят: LoadDatabaseConfig их отбрасывает.
+func (ds *DatabaseService) resolvePassword(profile, secretKey, configured string) (string, error) {
+	if configured != "" {
+		return configured, nil
+	}
+	if ds.secrets == nil {
+		return "", fmt.Errorf("пароль %s не задан и хранилище секретов не настроено", secretKey)
+	}
+
+	password, err := ds.secrets.Get(profileSecretKey(profile, secretKey))
+	if err != nil {
+		return "", fmt.Errorf("не удалось получить пароль %s профиля %s (%s): %v", secretKey, profile,
+			configField{key: secretKey}.envName(), err)
+	}
+	return password, nil
+}
+
+// hasPassword сообщает, известен ли пароль, не раскрывая его
+func (ds *DatabaseService) hasPassword(profile, secretKey, configured string) bool {
+	_, err := ds.resolvePassword(profile, secretKey, configured)
+	return err == nil
+}
+
+// SetPassword сохраняет пароль профиля в хранилище секретов
+func (ds *DatabaseService) SetPassword(profile, secretKey, password string) error {
+	if ds.secrets == nil {
+		return fmt.Errorf("хранилище секретов не настроено")
+	}
+	return ds.secrets.Set(profileSecretKey(profile, secretKey), password)
+}
+
+// UnlockSecrets разблокирует хранилище секретов мастер-фразой
+func (ds *DatabaseService) UnlockSecrets(passphrase string) error {
+	store, ok := ds.secrets.(*EncryptedFileStore)
+	if !ok {
+		return fmt.Errorf("хранилище секретов не требует мастер-фразы")
+	}
+	return store.Unlock(passphrase)
 }
 
 // Подключение к MySQL
 func (ds *DatabaseService) ConnectMySQL() error {
-	dsn := ds.config.MySQL.User + ":" + ds.config.MySQL.Password + "@tcp(" +
-		ds.config.MySQL.Host + ":" + ds.config.MySQL.Port + ")/" + ds.config.MySQL.Database + "?parseTime=true"
+	ds.mu.RLock()
+	profile, config := ds.profiles.Active, ds.profiles.Config()
+	ds.mu.RUnlock()
 
-	db, err := sql.Open("mysql", dsn)
+	password, err := ds.resolvePassword(profile, secretMySQLPassword, config.MySQL.Password)
 	if err != nil {
-		log.Printf("Ошибка подключения к MySQL: %v", err)
 		return err
 	}
 
-	// Настройка пула соединений
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
-	db.SetConnMaxLifetime(5 * time.Minute)
-
-	// Проверка соединения
-	if err := db.Ping(); err != nil {
-		log.Printf("Ошибка пинга MySQL: %v", err)
+	conn, err := openMySQL(config.MySQL, config.SSH, password)
+	if err != nil {
 		return err
 	}
 
-	ds.mysqlDB = db
-	log.Println("Успешное подключение к MySQL")
+	ds.mu.Lock()
+	if ds.profiles.Active != profile {
+		// Пока мы подключались, профиль переключили
+		ds.mu.Unlock()
+		conn.close()
+		return fmt.Errorf("профиль подключения изменился во время подключения к MySQL")
+	}
+	if ds.closed {
+		// Приложение завершается, соединение уже не нужно
+		ds.mu.Unlock()
+		conn.close()
+		return fmt.Errorf("сервис баз данных закрыт")
+	}
+	old := ds.mysql
+	ds.mysql = conn
+	ds.mu.Unlock()
+
+	old.retire()
 	return nil
 }
 
 // Подключение к MongoDB
 func (ds *DatabaseService) ConnectMongoDB() error {
-	// Список возможных источников аутентификации для пробы
-	authSources := []string{"admin", ds.config.MongoDB.Database, ""}
+	ds.mu.RLock()
+	profile, config := ds.profiles.Active, ds.profiles.Config()
+	ds.mu.RUnlock()
 
-	for i, authSource := range authSources {
-		log.Printf("Попытка подключения к MongoDB #%d с authSource: %s", i+1, authSource)
+	var password string
+	if config.MongoDB.needsPassword() {
+		var err error
+		if password, err = ds.resolvePassword(profile, secretMongoDBPassword, config.MongoDB.configuredPassword()); err != nil {
+			return err
+		}
+	}
 
-		// Формируем URI с правильным URL-кодированием пароля
-		uri := "mongodb://" + ds.config.MongoDB.User + ":" + url.QueryEscape(ds.config.MongoDB.Password) +
-			"@" + ds.config.MongoDB.Host + ":" + ds.config.MongoDB.Port + "/" + ds.config.MongoDB.Database
+	conn, err := openMongoDB(config.MongoDB, config.SSH, password)
+	if err != nil {
+		return err
+	}
 
-		// Добавляем authSource если он указан
-		if authSource != "" {
-			uri += "?authSource=" + authSource + "&authMechanism=SCRAM-SHA-1"
-		} else {
-			uri += "?authMechanism=SCRAM-SHA-1"
-		}
+	ds.mu.Lock()
+	if ds.profiles.Active != profile {
+		// Пока мы подключались, профиль переключили
+		ds.mu.Unlock()
+		conn.close(context.Background())
+		return fmt.Errorf("профиль подключения изменился во время подключения к MongoDB")
+	}
+	if ds.closed {
+		// Приложение завершается, соединение уже не нужно
+		ds.mu.Unlock()
+		conn.close(context.Background())
+		return fmt.Errorf("сервис баз данных закрыт")
+	}
+	old := ds.mongo
+	ds.mongo = conn
+	ds.mu.Unlock()
 
-		log.Printf("URI: mongodb://%s:***@%s:%s/%s (authSource: %s)",
-			ds.config.MongoDB.User, ds.config.MongoDB.Host, ds.config.MongoDB.Port, ds.config.MongoDB.Database, authSource)
+	old.retire()
+	return nil
+}
 
-		// Настройки клиента с таймаутами
-		clientOptions := options.Client().ApplyURI(uri)
-		clientOptions.SetConnectTimeout(10 * time.Second)
-		clientOptions.SetServerSelectionTimeout(10 * time.Second)
-		clientOptions.SetMaxPoolSize(10)
+// mysqlDriverConfig собирает настройки драйвера MySQL: адрес, учетные данные и TLS
+func mysqlDriverConfig(config MySQLConfig, password string) (*mysql.Config, error) {
+	tlsConfig, err := config.TLS.clientConfig(config.Host)
+	if err != nil {
+		return nil, err
+	}
 
-		client, err := mongo.Connect(context.Background(), clientOptions)
-		if err != nil {
-			log.Printf("Ошибка создания клиента MongoDB (попытка %d): %v", i+1, err)
-			continue
+	cfg := mysql.NewConfig()
+	cfg.User = config.User
+	cfg.Passwd = password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(config.Host, config.Port)
+	cfg.DBName = config.Database
+	cfg.ParseTime = true
+	cfg.TLS = tlsConfig
+	return cfg, nil
+}
+
+// openMySQL открывает и проверяет пул соединений MySQL для конфигурации.
+// Если задан SSH jump-host, драйвер подключается через локальный проброс порта.
+func openMySQL(config MySQLConfig, tunnel SSHConfig, password string) (*mysqlConn, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	cfg, err := mysqlDriverConfig(config, password)
+	if err != nil {
+		log.Printf("Ошибка настройки MySQL: %v", err)
+		return nil, err
+	}
+
+	conn := &mysqlConn{}
+	if tunnel.Enabled() {
+		// Имя сервера для проверки TLS остается прежним, меняется только адрес
+		if conn.forward, err = openSSHForward(tunnel, cfg.Addr); err != nil {
+			log.Printf("Ошибка SSH-туннеля к MySQL: %v", err)
+			return nil, err
 		}
+		cfg.Addr = conn.forward.Addr()
+	}
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		conn.forward.Close()
+		err = errors.New(redactSecrets(err.Error(), password))
+		log.Printf("Ошибка подключения к MySQL: %v", err)
+		return nil, err
+	}
+	conn.db = sql.OpenDB(connector)
+
+	// Настройка пула соединений
+	conn.db.SetMaxOpenConns(25)
+	conn.db.SetMaxIdleConns(25)
+	conn.db.SetConnMaxLifetime(5 * time.Minute)
 
-		// Проверка соединения
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	// Проверка соединения
+	if err := conn.db.Ping(); err != nil {
+		conn.close()
+		err = errors.New(redactSecrets(err.Error(), password))
+		log.Printf("Ошибка пинга MySQL: %v", err)
+		return nil, err
+	}
 
-		if err := client.Ping(ctx, nil); err != nil {
-			log.Printf("Ошибка пинга MongoDB (попытка %d): %v", i+1, err)
-			client.Disconnect(context.Background())
-			cancel()
-			continue
+	log.Printf("Успешное подключение к MySQL: %s", config)
+	return conn, nil
+}
+
+// mongoClientOptions собирает настройки клиента MongoDB: адрес или строку
+// подключения, учетные данные и TLS
+func mongoClientOptions(config MongoDBConfig, password string) (*options.ClientOptions, error) {
+	serverName := config.Host
+	if config.URI != "" {
+		// Хостов может быть несколько: имя для SNI драйвер берет из адреса
+		serverName = ""
+	}
+	tlsConfig, err := config.TLS.clientConfig(serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Настройки клиента с таймаутами; параметры из строки подключения
+	// применяются поверх них
+	clientOptions := options.Client().
+		SetConnectTimeout(10 * time.Second).
+		SetServerSelectionTimeout(10 * time.Second).
+		SetMaxPoolSize(10)
+
+	if config.URI != "" {
+		clientOptions.ApplyURI(config.URI)
+		if err := clientOptions.Validate(); err != nil {
+			return nil, fmt.Errorf("некорректная строка подключения MongoDB %s: %s",
+				withoutURIPassword(config.URI), redactSecrets(err.Error(), password, config.uriPassword()))
 		}
+		config = config.withURIAuth(clientOptions.Auth)
+	} else {
+		clientOptions.SetHosts([]string{net.JoinHostPort(config.Host, config.Port)})
+	}
 
-		// Успешное подключение!
-		ds.mongoDB = client.Database(ds.config.MongoDB.Database)
-		log.Printf("Успешное подключение к MongoDB с authSource: %s", authSource)
+	// Без пользователя (строка подключения без учетных данных) подключаемся
+	// без аутентификации; для X.509 пользователь берется из сертификата
+	if config.User != "" || !config.needsPassword() {
+		clientOptions.SetAuth(config.credential(password))
+	}
+	if tlsConfig != nil {
+		clientOptions.SetTLSConfig(tlsConfig)
+	}
+	return clientOptions, nil
+}
+
+// openMongoDB подключается к MongoDB с явно заданными authSource и механизмом.
+// Если задан SSH jump-host, драйвер подключается через локальный проброс порта.
+func openMongoDB(config MongoDBConfig, tunnel SSHConfig, password string) (*mongoConn, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	// ConnectMongoDB и TestConfig не проверяют конфигурацию целиком,
+	// поэтому совместимость с туннелем проверяем до его открытия
+	if err := configProblems(config.tunnelProblems(tunnel)); err != nil {
+		return nil, err
+	}
+
+	clientOptions, err := mongoClientOptions(config, password)
+	if err != nil {
+		log.Printf("Ошибка настройки MongoDB: %v", err)
+		return nil, err
+	}
+	log.Printf("Подключение к MongoDB: %s", config)
 
-		// Проверим доступность коллекций
-		collections, err := ds.mongoDB.ListCollectionNames(ctx, map[string]interface{}{})
-		if err != nil {
-			log.Printf("Предупреждение: не удалось получить список коллекций: %v", err)
-		} else {
-			log.Printf("Доступные коллекции: %v", collections)
+	conn := &mongoConn{}
+	if tunnel.Enabled() {
+		remote := net.JoinHostPort(config.Host, config.Port)
+		if conn.forward, err = openSSHForward(tunnel, remote); err != nil {
+			log.Printf("Ошибка SSH-туннеля к MongoDB: %v", err)
+			return nil, err
 		}
+		// Через проброс доступен только один узел, поэтому подключаемся
+		// к нему напрямую, без обнаружения остальных членов набора реплик
+		clientOptions.SetHosts([]string{conn.forward.Addr()}).SetDirect(true)
+	}
 
-		cancel()
-		return nil
+	client, err := mongo.Connect(context.Background(), clientOptions)
+	if err != nil {
+		conn.forward.Close()
+		err = errors.New(redactSecrets(err.Error(), password))
+		log.Printf("Ошибка создания клиента MongoDB: %v", err)
+		return nil, err
+	}
+
+	// Проверка соединения
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		conn.forward.Close()
+		err = errors.New(redactSecrets(err.Error(), password))
+		log.Printf("Ошибка пинга MongoDB: %v", err)
+		return nil, err
+	}
+
+	db := client.Database(config.database())
+	conn.db = db
+	log.Printf("Успешное подключение к MongoDB: %s", config)
+
+	// Проверим доступность коллекций
+	collections, err := db.ListCollectionNames(ctx, map[string]interface{}{})
+	if err != nil {
+		log.Printf("Предупреждение: не удалось получить список коллекций: %v", err)
+	} else {
+		log.Printf("Доступные коллекции: %v", collections)
+	}
+
+	return conn, nil
+}
+
+// close закрывает пул соединений MySQL и его SSH-туннель
+func (c *mysqlConn) close() {
+	if c == nil {
+		return
+	}
+	if c.db != nil {
+		c.db.Close()
+	}
+	c.forward.Close()
+}
+
+// close отключает клиент MongoDB и его SSH-туннель
+func (c *mongoConn) close(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	if c.db != nil {
+		c.db.Client().Disconnect(ctx)
+	}
+	c.forward.Close()
+}
+
+// retire закрывает замененное соединение после завершения всех запросов,
+// которые его используют
+func (c *mysqlConn) retire() {
+	if c == nil {
+		return
 	}
+	go func() {
+		c.refs.Wait()
+		c.close()
+	}()
+}
 
-	return fmt.Errorf("не удалось подключиться к MongoDB после %d попыток", len(authSources))
+// retire отключает замененный клиент MongoDB после завершения всех запросов,
+// которые его используют
+func (c *mongoConn) retire() {
+	if c == nil {
+		return
+	}
+	go func() {
+		c.refs.Wait()
+		ctx, cancel := context.WithTimeout(context.Background(), retireTimeout)
+		defer cancel()
+		c.close(ctx)
+	}()
 }
 
 // Получение MySQL соединения
 func (ds *DatabaseService) GetMySQLDB() *sql.DB {
-	return ds.mysqlDB
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	if ds.mysql == nil {
+		return nil
+	}
+	return ds.mysql.db
 }
 
 // Получение MongoDB соединения
 func (ds *DatabaseService) GetMongoDB() *mongo.Database {
-	return ds.mongoDB
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	if ds.mongo == nil {
+		return nil
+	}
+	return ds.mongo.db
 }
 
-// Закрытие всех соединений
+// Закрытие всех соединений и SSH-туннелей
 func (ds *DatabaseService) Close() {
-	if ds.mysqlDB != nil {
-		ds.mysqlDB.Close()
-	}
-	if ds.mongoDB != nil {
-		ds.mongoDB.Client().Disconnect(context.Background())
-	}
+	ds.mu.Lock()
+	mysqlConn, mongoConn := ds.mysql, ds.mongo
+	ds.mysql, ds.mongo = nil, nil
+	ds.closed = true
+	ds.mu.Unlock()
+
+	mysqlConn.close()
+	mongoConn.close(context.Background())
 }
@@ -0,0 +1,509 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Имя каталога приложения внутри пользовательского каталога конфигурации
+const configDirName = "cc-dashboard"
+
+// Префикс переменных окружения с настройками
+const envPrefix = "CCDASH_"
+
+// Имена файлов конфигурации в порядке поиска
+var configFileNames = []string{"config.json", "config.yaml", "config.yml"}
+
+// configField описывает строковое поле конфигурации и способ до него добраться
+type configField struct {
+	key string
+	ptr func(*DatabaseConfig) *string
+}
+
+// Все поля, которые можно переопределить переменными окружения и флагами
+var configFields = []configField{
+	{"mysql.host", func(c *DatabaseConfig) *string { return &c.MySQL.Host }},
+	{"mysql.port", func(c *DatabaseConfig) *string { return &c.MySQL.Port }},
+	{"mysql.user", func(c *DatabaseConfig) *string { return &c.MySQL.User }},
+	{"mysql.password", func(c *DatabaseConfig) *string { return &c.MySQL.Password }},
+	{"mysql.database", func(c *DatabaseConfig) *string { return &c.MySQL.Database }},
+	{"mongodb.uri", func(c *DatabaseConfig) *string { return &c.MongoDB.URI }},
+	{"mongodb.host", func(c *DatabaseConfig) *string { return &c.MongoDB.Host }},
+	{"mongodb.port", func(c *DatabaseConfig) *string { return &c.MongoDB.Port }},
+	{"mongodb.user", func(c *DatabaseConfig) *string { return &c.MongoDB.User }},
+	{"mongodb.password", func(c *DatabaseConfig) *string { return &c.MongoDB.Password }},
+	{"mongodb.database", func(c *DatabaseConfig) *string { return &c.MongoDB.Database }},
+	{"mongodb.auth_source", func(c *DatabaseConfig) *string { return &c.MongoDB.AuthSource }},
+	{"mongodb.auth_mechanism", func(c *DatabaseConfig) *string { return &c.MongoDB.AuthMechanism }},
+	{"ssh.host", func(c *DatabaseConfig) *string { return &c.SSH.Host }},
+	{"ssh.port", func(c *DatabaseConfig) *string { return &c.SSH.Port }},
+	{"ssh.user", func(c *DatabaseConfig) *string { return &c.SSH.User }},
+	{"ssh.key_file", func(c *DatabaseConfig) *string { return &c.SSH.KeyFile }},
+	{"ssh.known_hosts", func(c *DatabaseConfig) *string { return &c.SSH.KnownHostsFile }},
+	{"mysql.tls.mode", func(c *DatabaseConfig) *string { return &c.MySQL.TLS.Mode }},
+	{"mysql.tls.ca_file", func(c *DatabaseConfig) *string { return &c.MySQL.TLS.CAFile }},
+	{"mysql.tls.cert_file", func(c *DatabaseConfig) *string { return &c.MySQL.TLS.CertFile }},
+	{"mysql.tls.key_file", func(c *DatabaseConfig) *string { return &c.MySQL.TLS.KeyFile }},
+	{"mongodb.tls.mode", func(c *DatabaseConfig) *string { return &c.MongoDB.TLS.Mode }},
+	{"mongodb.tls.ca_file", func(c *DatabaseConfig) *string { return &c.MongoDB.TLS.CAFile }},
+	{"mongodb.tls.cert_file", func(c *DatabaseConfig) *string { return &c.MongoDB.TLS.CertFile }},
+	{"mongodb.tls.key_file", func(c *DatabaseConfig) *string { return &c.MongoDB.TLS.KeyFile }},
+}
+
+// envName возвращает имя переменной окружения, например CCDASH_MYSQL_HOST
+func (f configField) envName() string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(f.key, ".", "_"))
+}
+
+// flagName возвращает имя флага командной строки, например -mysql-host
+// или -mysql-tls-ca-file
+func (f configField) flagName() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(f.key)
+}
+
+// DefaultDatabaseConfig возвращает значения по умолчанию без адресов и учетных данных
+func DefaultDatabaseConfig() DatabaseConfig {
+	return DatabaseConfig{
+		MySQL: MySQLConfig{
+			Port:     "3306",
+			Database: "report",
+		},
+		MongoDB: MongoDBConfig{
+			Port:     "27017",
+			Database: "request",
+		},
+	}
+}
+
+// DefaultConfigDir возвращает каталог конфигурации приложения
+func DefaultConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("не удалось определить каталог конфигурации: %v", err)
+	}
+	return filepath.Join(dir, configDirName), nil
+}
+
+// Имя профиля, который описывают ключи mysql/mongodb верхнего уровня файла
+const defaultProfileName = "default"
+
+// configFile содержимое файла конфигурации. Ключи mysql/mongodb верхнего
+// уровня задают профиль "default" и общую основу для именованных профилей:
+// в профиле достаточно указать только отличающиеся поля.
+type configFile struct {
+	DatabaseConfig `yaml:",inline"`
+	ActiveProfile  string                    `json:"active_profile,omitempty" yaml:"active_profile,omitempty"`
+	Profiles       map[string]DatabaseConfig `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+}
+
+// ConnectionProfiles набор именованных профилей подключения (production,
+// replica, test) и имя активного профиля
+type ConnectionProfiles struct {
+	Active   string
+	Profiles map[string]DatabaseConfig
+}
+
+// Config возвращает конфигурацию активного профиля
+func (p ConnectionProfiles) Config() DatabaseConfig {
+	return p.Profiles[p.Active]
+}
+
+// Names возвращает имена профилей по алфавиту, "default" первым
+func (p ConnectionProfiles) Names() []string {
+	names := make([]string, 0, len(p.Profiles))
+	for name := range p.Profiles {
+		if name != defaultProfileName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := p.Profiles[defaultProfileName]; ok {
+		names = append([]string{defaultProfileName}, names...)
+	}
+	return names
+}
+
+// overlayConfig накладывает непустые поля профиля на основу.
+// Группы очередей профиля заменяют группы основы целиком.
+func overlayConfig(base, profile DatabaseConfig) DatabaseConfig {
+	for _, field := range configFields {
+		if value := *field.ptr(&profile); value != "" {
+			*field.ptr(&base) = value
+		}
+	}
+	if len(profile.QueueGroups) > 0 {
+		base.QueueGroups = profile.QueueGroups
+	}
+	return base
+}
+
+// LoadDatabaseConfig собирает конфигурацию слоями: значения по умолчанию,
+// файл JSON/YAML в каталоге конфигурации пользователя, переменные окружения
+// CCDASH_* и флаги командной строки. Каждый следующий слой перекрывает
+// предыдущий. Переменные окружения и флаги относятся к профилю, выбранному
+// при запуске (CCDASH_PROFILE, -profile, либо active_profile из файла).
+// Пароли из файла не используются и попадают в ошибку как проблемы.
+// Возвращает профили и путь к файлу конфигурации, даже если проверка
+// не прошла, чтобы приложение могло запуститься и сообщить об ошибке.
+func LoadDatabaseConfig(args []string) (ConnectionProfiles, string, error) {
+	base := DefaultDatabaseConfig()
+	profiles := ConnectionProfiles{
+		Active:   defaultProfileName,
+		Profiles: map[string]DatabaseConfig{defaultProfileName: base},
+	}
+
+	// Флаги разбираем первыми, чтобы узнать явно указанный путь к файлу
+	fs := flag.NewFlagSet(configDirName, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configPath := fs.String("config", "", "путь к файлу конфигурации (JSON или YAML)")
+	profileName := fs.String("profile", "", "имя профиля подключения")
+	flagValues := make(map[string]*string, len(configFields))
+	for _, field := range configFields {
+		flagValues[field.flagName()] = fs.String(field.flagName(), "", field.key)
+	}
+	if err := fs.Parse(args); err != nil {
+		return profiles, "", fmt.Errorf("ошибка разбора аргументов командной строки: %v", err)
+	}
+
+	// Слой 2: файл конфигурации
+	path, explicit := *configPath, *configPath != ""
+	if !explicit {
+		if envPath := os.Getenv(envPrefix + "CONFIG"); envPath != "" {
+			path, explicit = envPath, true
+		}
+	}
+	if !explicit {
+		dir, err := DefaultConfigDir()
+		if err != nil {
+			return profiles, "", err
+		}
+		path = findConfigFile(dir)
+	}
+	file := configFile{DatabaseConfig: base}
+	if err := loadConfigFile(path, &file); err != nil {
+		if explicit || !errors.Is(err, os.ErrNotExist) {
+			return profiles, path, err
+		}
+	}
+	filePasswords := file.stripPasswords()
+	profiles.Profiles[defaultProfileName] = file.DatabaseConfig
+	for name, profile := range file.Profiles {
+		profiles.Profiles[name] = overlayConfig(file.DatabaseConfig, profile)
+	}
+
+	// Выбор активного профиля: флаг, окружение, файл
+	switch {
+	case *profileName != "":
+		profiles.Active = *profileName
+	case os.Getenv(envPrefix+"PROFILE") != "":
+		profiles.Active = os.Getenv(envPrefix + "PROFILE")
+	case file.ActiveProfile != "":
+		profiles.Active = file.ActiveProfile
+	}
+	config, ok := profiles.Profiles[profiles.Active]
+	if !ok {
+		err := fmt.Errorf("профиль подключения %q не найден, доступны: %s",
+			profiles.Active, strings.Join(profiles.Names(), ", "))
+		profiles.Active = defaultProfileName
+		return profiles, path, err
+	}
+
+	// Слой 3: переменные окружения
+	for _, field := range configFields {
+		if value := os.Getenv(field.envName()); value != "" {
+			*field.ptr(&config) = value
+		}
+	}
+
+	// Слой 4: флаги командной строки (только явно переданные)
+	fs.Visit(func(f *flag.Flag) {
+		for _, field := range configFields {
+			if field.flagName() == f.Name {
+				*field.ptr(&config) = *flagValues[f.Name]
+			}
+		}
+	})
+
+	profiles.Profiles[profiles.Active] = config
+	err := config.Validate()
+	if len(filePasswords) > 0 {
+		var configErr *ConfigError
+		if errors.As(err, &configErr) {
+			filePasswords = append(configErr.Problems, filePasswords...)
+		}
+		err = configProblems(filePasswords)
+	}
+	return profiles, path, err
+}
+
+// stripPasswords убирает из файла пароли, в том числе из mongodb.uri, и
+// возвращает по проблеме на каждый. Пароли открытым текстом в файле не
+// используются: их нужно перенести в хранилище секретов.
+func (f *configFile) stripPasswords() []string {
+	var problems []string
+	strip := func(name string, config *DatabaseConfig) {
+		found := func(key string) {
+			problems = append(problems, fmt.Sprintf("пароль %s профиля %s в файле конфигурации не используется: "+
+				"перенесите его в хранилище секретов (сохраните профиль в приложении) или задайте через %s",
+				key, name, configField{key: key}.envName()))
+		}
+		if config.MySQL.Password != "" {
+			config.MySQL.Password = ""
+			found("mysql.password")
+		}
+		if config.MongoDB.Password != "" {
+			config.MongoDB.Password = ""
+			found("mongodb.password")
+		}
+		if config.MongoDB.uriPassword() != "" {
+			config.MongoDB.URI = withoutURIPassword(config.MongoDB.URI)
+			found("mongodb.uri")
+		}
+	}
+
+	strip(defaultProfileName, &f.DatabaseConfig)
+	for _, name := range sortedKeys(f.Profiles) {
+		profile := f.Profiles[name]
+		strip(name, &profile)
+		f.Profiles[name] = profile
+	}
+	return problems
+}
+
+// findConfigFile ищет существующий файл конфигурации в каталоге.
+// Если файла нет, возвращает путь к config.json, куда его можно создать.
+func findConfigFile(dir string) string {
+	for _, name := range configFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dir, configFileNames[0])
+}
+
+// loadConfigFile читает файл конфигурации поверх уже заполненных значений
+func loadConfigFile(path string, config *configFile) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать файл конфигурации %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("ошибка разбора YAML в %s: %v", path, err)
+		}
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(config); err != nil {
+			return fmt.Errorf("ошибка разбора JSON в %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// updateConfigFile перечитывает файл конфигурации, применяет к нему update
+// и записывает обратно в формате по расширению. Меняется только то, что
+// лежит в файле: значения по умолчанию, переменные окружения и флаги
+// в файл не попадают, именованные профили остаются наложениями на основу.
+// Пароли в файл не попадают: они хранятся в хранилище секретов.
+func updateConfigFile(path string, update func(file *configFile)) error {
+	if path == "" {
+		return fmt.Errorf("путь к файлу конфигурации не определен")
+	}
+
+	var file configFile
+	if err := loadConfigFile(path, &file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	update(&file)
+
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(file)
+	default:
+		data, err = json.MarshalIndent(file, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации конфигурации: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("не удалось создать каталог конфигурации: %v", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("не удалось записать файл конфигурации: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("не удалось записать файл конфигурации: %v", err)
+	}
+	return nil
+}
+
+// setProfile записывает конфигурацию профиля в слой файла без паролей.
+// Профиль "default" занимает ключи верхнего уровня целиком, в именованный
+// профиль попадают только поля, отличающиеся от основы, и поля, уже заданные
+// в нем явно, чтобы остальное он по-прежнему наследовал от основы.
+func (f *configFile) setProfile(name string, config DatabaseConfig) {
+	config = withoutPasswords(config)
+	if name == defaultProfileName {
+		f.DatabaseConfig = config
+		return
+	}
+
+	base := overlayConfig(DefaultDatabaseConfig(), f.DatabaseConfig)
+	current := f.Profiles[name]
+	var layer DatabaseConfig
+	for _, field := range configFields {
+		value := *field.ptr(&config)
+		if value != *field.ptr(&base) || *field.ptr(&current) != "" {
+			*field.ptr(&layer) = value
+		}
+	}
+	if len(current.QueueGroups) > 0 || !reflect.DeepEqual(config.QueueGroups, base.QueueGroups) {
+		layer.QueueGroups = config.QueueGroups
+	}
+
+	if f.Profiles == nil {
+		f.Profiles = make(map[string]DatabaseConfig)
+	}
+	f.Profiles[name] = layer
+}
+
+// withoutPasswords возвращает копию конфигурации без паролей
+func withoutPasswords(config DatabaseConfig) DatabaseConfig {
+	config.MySQL.Password = ""
+	config.MongoDB.Password = ""
+	return config
+}
+
+// ConfigError перечисляет все проблемы конфигурации сразу,
+// чтобы не исправлять их по одной
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return "некорректная конфигурация баз данных: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate проверяет, что заданы все поля подключения к обеим базам
+func (c DatabaseConfig) Validate() error {
+	var problems []string
+	for _, err := range []error{c.MySQL.Validate(), c.MongoDB.Validate()} {
+		var configErr *ConfigError
+		if errors.As(err, &configErr) {
+			problems = append(problems, configErr.Problems...)
+		}
+	}
+	problems = append(problems, c.SSH.problems()...)
+	problems = append(problems, c.MongoDB.tunnelProblems(c.SSH)...)
+	problems = append(problems, queueGroupProblems(c.QueueGroups)...)
+	return configProblems(problems)
+}
+
+// Validate проверяет настройки подключения к MySQL
+func (c MySQLConfig) Validate() error {
+	problems := validateConnectionFields("mysql", c.Host, c.Port, c.Database)
+	if strings.TrimSpace(c.User) == "" {
+		problems = append(problems, missingField("mysql.user"))
+	}
+	problems = append(problems, c.TLS.problems("mysql")...)
+	return configProblems(problems)
+}
+
+// Validate проверяет настройки подключения к MongoDB
+func (c MongoDBConfig) Validate() error {
+	var problems []string
+	if c.URI != "" {
+		// Хосты, пользователь и база могут быть указаны в строке подключения
+		if _, err := parseMongoURI(c.URI); err != nil {
+			problems = append(problems, fmt.Sprintf("некорректный mongodb.uri: %v", err))
+		} else if c.database() == "" {
+			problems = append(problems, missingField("mongodb.database"))
+		}
+	} else {
+		problems = validateConnectionFields("mongodb", c.Host, c.Port, c.Database)
+		// При X.509 имя пользователя можно не указывать: оно берется из сертификата
+		if strings.TrimSpace(c.User) == "" && c.needsPassword() {
+			problems = append(problems, missingField("mongodb.user"))
+		}
+	}
+	problems = append(problems, c.TLS.problems("mongodb")...)
+
+	switch c.AuthMechanism {
+	case "", mongoAuthSCRAMSHA1, mongoAuthSCRAMSHA256:
+	case mongoAuthX509:
+		if !c.TLS.Enabled() || c.TLS.CertFile == "" {
+			problems = append(problems, "для mongodb.auth_mechanism MONGODB-X509 нужны TLS и клиентский сертификат (mongodb.tls.cert_file)")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("неподдерживаемый mongodb.auth_mechanism: %q (допустимо %s, %s, %s)",
+			c.AuthMechanism, mongoAuthSCRAMSHA1, mongoAuthSCRAMSHA256, mongoAuthX509))
+	}
+	return configProblems(problems)
+}
+
+// tunnelProblems проверяет, что MongoDB доступна через SSH-туннель:
+// туннель пробрасывает один адрес mongodb.host:mongodb.port
+func (c MongoDBConfig) tunnelProblems(tunnel SSHConfig) []string {
+	if tunnel.Enabled() && c.URI != "" {
+		return []string{"SSH-туннель не поддерживает mongodb.uri: укажите mongodb.host и mongodb.port"}
+	}
+	return nil
+}
+
+// missingField описывает незаполненное обязательное поле
+func missingField(key string) string {
+	return fmt.Sprintf("не задано поле %s (%s)", key, configField{key: key}.envName())
+}
+
+// configProblems превращает список проблем в ConfigError
+func configProblems(problems []string) error {
+	if len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+	return nil
+}
+
+// validateConnectionFields проверяет общий для обеих баз набор полей.
+// Пароль не обязателен: он может храниться в хранилище секретов.
+func validateConnectionFields(section, host, port, database string) []string {
+	var problems []string
+	required := []struct{ name, value string }{
+		{"host", host},
+		{"port", port},
+		{"database", database},
+	}
+	for _, field := range required {
+		if strings.TrimSpace(field.value) == "" {
+			problems = append(problems, missingField(section+"."+field.name))
+		}
+	}
+	if port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			problems = append(problems, fmt.Sprintf("некорректный порт %s.port: %q", section, port))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Распределения длительностей: перцентили и гистограммы считаются в Go по
+// количествам записей с одинаковым значением, потому что перцентильных
+// и оконных функций нет во всех версиях MySQL.
+
+// countedValues значения длительности с количествами по возрастанию
+type countedValues struct {
+	values []ValueCount
+	total  int
+	sum    float64
+}
+
+// newCountedValues упорядочивает значения и отбрасывает пустые количества
+func newCountedValues(values []ValueCount) countedValues {
+	sorted := slices.DeleteFunc(slices.Clone(values), func(v ValueCount) bool { return v.Count <= 0 })
+	slices.SortFunc(sorted, func(a, b ValueCount) int {
+		switch {
+		case a.Value < b.Value:
+			return -1
+		case a.Value > b.Value:
+			return 1
+		}
+		return 0
+	})
+
+	c := countedValues{values: sorted}
+	for _, v := range sorted {
+		c.total += v.Count
+		c.sum += v.Value * float64(v.Count)
+	}
+	return c
+}
+
+// mean среднее значение
+func (c countedValues) mean() float64 {
+	return ratio(c.sum, c.total)
+}
+
+// max наибольшее значение; ноль без записей
+func (c countedValues) max() float64 {
+	if len(c.values) == 0 {
+		return 0
+	}
+	return c.values[len(c.values)-1].Value
+}
+
+// at значение k-й по возрастанию записи, с нуля
+func (c countedValues) at(k int) float64 {
+	for _, v := range c.values {
+		if k < v.Count {
+			return v.Value
+		}
+		k -= v.Count
+	}
+	return c.max()
+}
+
+// percentile линейная интерполяция между соседними рангами, как PERCENTILE_CONT;
+// ранг считается в целых сотых, чтобы не копить ошибку округления
+func (c countedValues) percentile(percent int) float64 {
+	if c.total == 0 {
+		return 0
+	}
+	rank := percent * (c.total - 1)
+	lower, fraction := rank/100, rank%100
+	low, high := c.at(lower), c.at(min(lower+1, c.total-1))
+	return low + (high-low)*float64(fraction)/100
+}
+
+// trim отбрасывает значения больше capSeconds; возвращает число отброшенных
+func (c countedValues) trim(capSeconds int) (countedValues, int) {
+	if capSeconds <= 0 {
+		return c, 0
+	}
+	i, _ := slices.BinarySearchFunc(c.values, float64(capSeconds), func(v ValueCount, limit float64) int {
+		if v.Value <= limit {
+			return -1
+		}
+		return 1
+	})
+	kept := newCountedValues(c.values[:i])
+	return kept, c.total - kept.total
+}
+
+// durationEdges границы интервалов гистограммы длительностей, сек
+var durationEdges = map[string][]int{
+	"aht": {30, 60, 120, 180, 300, 600, 900, 1800},
+	"frt": {10, 30, 60, 120, 300, 600, 1800},
+	"rt":  {60, 300, 900, 1800, 3600, 7200, 14400},
+}
+
+// durationLabel подпись длительности: 45s, 5m, 2h
+func durationLabel(seconds int) string {
+	switch {
+	case seconds > 0 && seconds%3600 == 0:
+		return fmt.Sprintf("%dh", seconds/3600)
+	case seconds > 0 && seconds%60 == 0:
+		return fmt.Sprintf("%dm", seconds/60)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
+// histogramBin номер интервала для value: интервал i от edges[i-1]
+// включительно до edges[i], значение на границе относится к следующему
+// интервалу. То же правило в гистограмме ожидания брошенных звонков.
+func histogramBin(edges []int, value float64) int {
+	i, _ := slices.BinarySearchFunc(edges, value, func(edge int, value float64) int {
+		if float64(edge) <= value {
+			return -1
+		}
+		return 1
+	})
+	return i
+}
+
+// durationHistogram количества записей по интервалам edges (см. histogramBin)
+func durationHistogram(c countedValues, edges []int) []DurationBin {
+	bins := make([]DurationBin, len(edges)+1)
+	from := 0
+	for i := range bins {
+		bins[i].From = from
+		if i < len(edges) {
+			bins[i].To = edges[i]
+			bins[i].Label = fmt.Sprintf("%s-%s", durationLabel(from), durationLabel(edges[i]))
+			from = edges[i]
+		} else {
+			bins[i].Label = durationLabel(from) + "+"
+		}
+	}
+
+	for _, v := range c.values {
+		bins[histogramBin(edges, v.Value)].Count += v.Count
+	}
+	return bins
+}
+
+// durationDistribution перцентили и гистограмма длительности metric;
+// capSeconds > 0 отсекает выбросы длиннее порога
+func durationDistribution(metric string, counts []ValueCount, capSeconds int) DurationDistribution {
+	kept, trimmed := newCountedValues(counts).trim(capSeconds)
+	return DurationDistribution{
+		Metric:     metric,
+		CapSeconds: capSeconds,
+		Count:      kept.total,
+		Trimmed:    trimmed,
+		Mean:       asSeconds(kept.mean()),
+		Median:     asSeconds(kept.percentile(50)),
+		P90:        asSeconds(kept.percentile(90)),
+		P95:        asSeconds(kept.percentile(95)),
+		Max:        asSeconds(kept.max()),
+		Bins:       durationHistogram(kept, durationEdges[metric]),
+	}
+}
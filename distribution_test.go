@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestDurationDistribution(t *testing.T) {
+	counts := []ValueCount{{Value: 7200, Count: 1}, {Value: 30, Count: 2}, {Value: 10, Count: 1}, {Value: 45, Count: 1}}
+
+	// Порог 1 ч отсекает двухчасовую запись из перцентилей и гистограммы
+	got := durationDistribution("aht", counts, 3600)
+	if got.Count != 4 || got.Trimmed != 1 || got.CapSeconds != 3600 {
+		t.Errorf("count = %d, trimmed = %d, cap = %d", got.Count, got.Trimmed, got.CapSeconds)
+	}
+	// 10, 30, 30, 45: медиана между 30 и 30, p90 на 0.7 от 30 до 45
+	if got.Mean != 29 || got.Median != 30 || got.P90 != 41 || got.P95 != 43 || got.Max != 45 {
+		t.Errorf("mean = %v, median = %v, p90 = %v, p95 = %v, max = %v", got.Mean, got.Median, got.P90, got.P95, got.Max)
+	}
+	if len(got.Bins) != 9 || got.Bins[0].Label != "0s-30s" || got.Bins[1].Label != "30s-1m" || got.Bins[8].Label != "30m+" {
+		t.Fatalf("bins = %+v", got.Bins)
+	}
+	// Значение на границе относится к интервалу, который она открывает:
+	// 30 с попадает в 30s-1m, как ожидание брошенных звонков
+	if got.Bins[0].Count != 1 || got.Bins[1].Count != 3 || got.Bins[8].Count != 0 {
+		t.Errorf("bins = %+v", got.Bins)
+	}
+
+	untrimmed := durationDistribution("aht", counts, 0)
+	if untrimmed.Count != 5 || untrimmed.Trimmed != 0 || untrimmed.Max != 7200 || untrimmed.Bins[8].Count != 1 {
+		t.Errorf("untrimmed = %+v", untrimmed)
+	}
+
+	empty := durationDistribution("frt", nil, 0)
+	if empty.Count != 0 || empty.Median != 0 || len(empty.Bins) != 8 {
+		t.Errorf("empty = %+v", empty)
+	}
+}
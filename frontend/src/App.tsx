@@ -1,12 +1,13 @@
-import React, { useState, useEffect } from 'react';
+import React, { useState, useEffect, useRef } from 'react';
 import './App.css';
 import Sidebar from './components/Sidebar';
 import Dashboard from './components/Dashboard';
+import { EventsOn } from '../wailsjs/runtime/runtime';
 
 // Типы для состояния
-type QueueFilter = 'all' | 'm10' | 'aml';
-type DashboardView = 'daily' | 'hourly' | 'monthly' | 'classifiers' | 'online';
-type StandardMetric = 'calls' | 'aht' | 'sl' | 'chats' | 'frt' | 'rt' | 'abandoned' | 'total' | 'detailed_daily';
+type QueueFilter = string;
+type DashboardView = 'daily' | 'hourly' | 'intervals' | 'monthly' | 'agents' | 'classifiers' | 'queues' | 'online';
+type StandardMetric = 'calls' | 'aht' | 'sl' | 'chats' | 'frt' | 'rt' | 'abandoned' | 'abandon_rate' | 'asa' | 'occupancy' | 'total' | 'detailed_daily';
 type ClassifierMetric = 'call' | 'chat' | 'overall' | 'topics' | 'subtopics_daily';
 
 // Получение текущей даты в формате YYYY-MM-DD
@@ -45,6 +46,7 @@ function App() {
   const [selectedMonth, setSelectedMonth] = useState<string>(getCurrentMonth());
   const [shouldLoadData, setShouldLoadData] = useState(false);
   const [isLoading, setIsLoading] = useState(false);
+  const wasConnected = useRef(true);
 
   // Эффект для установки класса темы на корневой элемент при изменении режима
   useEffect(() => {
@@ -79,6 +81,24 @@ function App() {
     }
   }, [activeQueue, activeView]);
 
+  // После смены профиля подключения перечитываем данные, чтобы на экране
+  // не остались цифры предыдущего профиля
+  useEffect(() => {
+    return EventsOn('db:profile', () => handleApplyFilters());
+  }, []);
+
+  // Когда супервизор восстановил соединения, перечитываем данные,
+  // загрузка которых могла упасть, пока базы были недоступны
+  useEffect(() => {
+    return EventsOn('db:status', (status) => {
+      const connected = status.mysql.state === 'Подключено' && status.mongodb.state === 'Подключено';
+      if (connected && !wasConnected.current) {
+        handleApplyFilters();
+      }
+      wasConnected.current = connected;
+    });
+  }, []);
+
   // Отладка selectedMonth
   useEffect(() => {
     console.log('=== APP selectedMonth изменился ===');
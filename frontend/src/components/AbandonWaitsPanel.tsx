@@ -0,0 +1,140 @@
+import React, { useState, useEffect } from 'react';
+import { Loader2 } from 'lucide-react';
+import clsx from 'clsx';
+import { GetAbandonWaits } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { useRequestScope } from '../utils/requests';
+
+interface AbandonWaitsPanelProps {
+  queueName: string;
+  startDate: string;
+  endDate: string;
+  reloadKey: unknown; // новые данные Daily — перечитать распределение
+}
+
+// Детализация распределения ожидания брошенных звонков
+const granularityOptions = [
+  { value: 'day', label: 'Day' },
+  { value: 'hour', label: 'Hour' },
+  { value: 'month', label: 'Month' },
+];
+
+// Распределение ожидания брошенных звонков по интервалам ожидания
+const AbandonWaitsPanel: React.FC<AbandonWaitsPanelProps> = ({ queueName, startDate, endDate, reloadKey }) => {
+  const [granularity, setGranularity] = useState('day');
+  const [report, setReport] = useState<main.AbandonWaitReport | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string>('');
+  const beginLoad = useRequestScope('abandon-waits');
+
+  const loadData = async () => {
+    if (!startDate || !endDate) return;
+
+    const load = beginLoad();
+    setLoading(true);
+    setError('');
+
+    try {
+      const result = await GetAbandonWaits(load.id(), startDate, endDate, queueName, granularity);
+      if (load.stale()) return;
+      setReport(result);
+    } catch (error) {
+      if (load.stale()) return; // запрос отменен новой загрузкой
+      console.error('Ошибка загрузки ожидания брошенных звонков:', error);
+      setError(`Ошибка загрузки данных: ${error}`);
+    } finally {
+      if (!load.stale()) setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    loadData();
+  }, [reloadKey, granularity]);
+
+  const renderTable = () => {
+    if (!report || report.rows.length === 0) {
+      return <p className="text-sm text-gray-600 dark:text-gray-400">За выбранный период брошенных звонков нет</p>;
+    }
+
+    const cell = 'px-3 py-2 text-center text-xs border-r border-gray-200 dark:border-dark-600 whitespace-nowrap';
+    const rows = [...report.rows, report.total];
+
+    return (
+      <div className="overflow-x-auto">
+        <table className="w-full">
+          <thead>
+            <tr className="bg-gray-100 dark:bg-dark-700">
+              <th className={clsx(cell, 'text-left font-medium text-gray-900 dark:text-white')}>Period</th>
+              {report.bins.map(bin => (
+                <th key={bin} className={clsx(cell, 'font-medium text-gray-900 dark:text-white')}>{bin}</th>
+              ))}
+              <th className={clsx(cell, 'font-medium text-gray-900 dark:text-white')}>Abandoned</th>
+              {report.short_abandon_seconds > 0 && (
+                <th className={clsx(cell, 'font-medium text-gray-900 dark:text-white')}>Short</th>
+              )}
+            </tr>
+          </thead>
+          <tbody className="divide-y divide-gray-200 dark:divide-dark-600">
+            {rows.map(row => (
+              <tr key={row.period || 'total'} className={clsx(!row.period && 'font-semibold bg-gray-100 dark:bg-dark-700')}>
+                <td className={clsx(cell, 'text-left text-gray-900 dark:text-white')}>{row.period || 'Total'}</td>
+                {row.bins.map((count, i) => (
+                  <td key={i} className={clsx(cell, count ? 'text-gray-900 dark:text-white' : 'text-gray-400 dark:text-gray-500')}>{count}</td>
+                ))}
+                <td className={clsx(cell, 'text-red-600 dark:text-red-400')}>{row.abandoned}</td>
+                {report.short_abandon_seconds > 0 && (
+                  <td className={clsx(cell, 'text-gray-600 dark:text-gray-400')}>{row.short}</td>
+                )}
+              </tr>
+            ))}
+          </tbody>
+        </table>
+      </div>
+    );
+  };
+
+  return (
+    <div className="bg-gray-50 dark:bg-dark-800 p-6 rounded-lg border border-gray-200 dark:border-dark-700 mb-6">
+      <div className="flex justify-between items-center mb-4">
+        <div>
+          <h2 className="text-xl font-semibold text-gray-900 dark:text-white">Abandoned wait time</h2>
+          {report && report.short_abandon_seconds > 0 && (
+            <p className="text-sm text-gray-600 dark:text-gray-400 mt-1">
+              Short abandons (under {report.short_abandon_seconds}s) are excluded from abandoned and abandon rate
+            </p>
+          )}
+        </div>
+        <div className="flex space-x-1">
+          {granularityOptions.map(option => (
+            <button
+              key={option.value}
+              onClick={() => setGranularity(option.value)}
+              disabled={loading}
+              className={clsx(
+                'px-3 py-1.5 text-sm rounded-md transition-colors',
+                granularity === option.value
+                  ? 'bg-primary-600 text-white'
+                  : 'bg-gray-100 dark:bg-dark-700 text-gray-700 dark:text-gray-300 hover:text-gray-900 dark:hover:text-white'
+              )}
+            >
+              {option.label}
+            </button>
+          ))}
+        </div>
+      </div>
+
+      {loading && (
+        <div className="flex items-center space-x-2 text-gray-600 dark:text-gray-400">
+          <Loader2 className="w-4 h-4 animate-spin" />
+          <span className="text-sm">Загрузка...</span>
+        </div>
+      )}
+
+      {error && <div className="text-red-600 dark:text-red-400 text-sm">{error}</div>}
+
+      {!loading && !error && renderTable()}
+    </div>
+  );
+};
+
+export default AbandonWaitsPanel;
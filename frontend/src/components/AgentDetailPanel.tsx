@@ -0,0 +1,220 @@
+import React, { useState, useEffect } from 'react';
+import { Loader2, X } from 'lucide-react';
+import { GetAgentDetail } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { useRequestScope } from '../utils/requests';
+import { formatDuration } from '../utils/duration';
+
+interface AgentDetailPanelProps {
+  queueName: string;
+  startDate: string;
+  endDate: string;
+  userId: string;
+  reloadKey: unknown; // новый рейтинг — перечитать детализацию
+  onClose: () => void;
+}
+
+// Детализация агента: объемы по дням и часам, длительности, одновременные чаты и топики
+const AgentDetailPanel: React.FC<AgentDetailPanelProps> = ({ queueName, startDate, endDate, userId, reloadKey, onClose }) => {
+  const [detail, setDetail] = useState<main.AgentDetail | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string>('');
+  const beginLoad = useRequestScope('agent-detail');
+
+  const loadData = async () => {
+    if (!startDate || !endDate) return;
+
+    const load = beginLoad();
+    setLoading(true);
+    setError('');
+
+    try {
+      const result = await GetAgentDetail(load.id(), startDate, endDate, queueName, userId);
+      if (load.stale()) return;
+      console.debug('Agent detail timings:', result.timings);
+      setDetail(result);
+    } catch (error) {
+      if (load.stale()) return; // запрос отменен новой загрузкой
+      console.error('Ошибка загрузки детализации агента:', error);
+      setError(`Ошибка загрузки данных: ${error}`);
+    } finally {
+      if (!load.stale()) setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    loadData();
+  }, [reloadKey, userId]);
+
+  const card = 'bg-dark-700 rounded-md p-3 text-center';
+  const cell = 'px-3 py-2 text-center text-white border-r border-dark-600 whitespace-nowrap';
+
+  const renderDetail = (detail: main.AgentDetail) => {
+    const metrics = [
+      { label: 'Handled', value: detail.metrics.handled.toLocaleString() },
+      { label: 'Calls', value: detail.metrics.calls.toLocaleString() },
+      { label: 'Chats', value: detail.metrics.chats.toLocaleString() },
+      { label: 'Active hours', value: detail.metrics.active_hours.toFixed(2) },
+      { label: 'Occupancy', value: `${detail.occupancy.toFixed(1)}%` },
+    ];
+    const distributions = [detail.aht, detail.frt, detail.rt];
+    const busiest = Math.max(...detail.hourly.map(hour => hour.calls + hour.chats), 1);
+    const concurrency = detail.concurrency;
+
+    return (
+      <>
+        <div className="grid grid-cols-5 gap-3 mb-6">
+          {metrics.map(metric => (
+            <div key={metric.label} className={card}>
+              <div className="text-xs text-gray-400">{metric.label}</div>
+              <div className="text-lg font-semibold text-white">{metric.value}</div>
+            </div>
+          ))}
+        </div>
+
+        <div className="grid grid-cols-1 lg:grid-cols-2 gap-6 mb-6">
+          <div>
+            <h3 className="text-sm font-medium text-gray-300 mb-2">By day</h3>
+            {detail.daily.length === 0 ? (
+              <p className="text-sm text-gray-400">За выбранный период записей нет</p>
+            ) : (
+              <table className="w-full">
+                <thead>
+                  <tr className="bg-dark-700">
+                    <th className={cell}>Date</th>
+                    <th className={cell}>Calls</th>
+                    <th className={cell}>Chats</th>
+                  </tr>
+                </thead>
+                <tbody className="divide-y divide-dark-600">
+                  {detail.daily.map(day => (
+                    <tr key={day.date}>
+                      <td className={cell}>{day.date}</td>
+                      <td className={cell}>{day.calls.toLocaleString()}</td>
+                      <td className={cell}>{day.chats.toLocaleString()}</td>
+                    </tr>
+                  ))}
+                </tbody>
+              </table>
+            )}
+          </div>
+
+          <div>
+            <h3 className="text-sm font-medium text-gray-300 mb-2">By hour (calls / chats, occupancy)</h3>
+            <div className="space-y-1">
+              {detail.hourly.map(hour => (
+                <div key={hour.hour} className="flex items-center text-xs">
+                  <span className="w-12 text-gray-300">{hour.hour.toString().padStart(2, '0')}:00</span>
+                  <div className="flex-1 flex bg-dark-700 rounded h-3 mx-2 overflow-hidden">
+                    <div className="bg-primary-600 h-3" style={{ width: `${(hour.calls / busiest) * 100}%` }} />
+                    <div className="bg-green-500 h-3" style={{ width: `${(hour.chats / busiest) * 100}%` }} />
+                  </div>
+                  <span className="w-16 text-right text-white">{hour.calls} / {hour.chats}</span>
+                  <span className="w-14 text-right text-gray-400">{hour.occupancy ? `${hour.occupancy.toFixed(1)}%` : ''}</span>
+                </div>
+              ))}
+            </div>
+          </div>
+        </div>
+
+        <h3 className="text-sm font-medium text-gray-300 mb-2">Durations</h3>
+        <table className="w-full mb-6">
+          <thead>
+            <tr className="bg-dark-700">
+              <th className={cell}></th>
+              <th className={cell}>Records</th>
+              <th className={cell}>Mean</th>
+              <th className={cell}>Median</th>
+              <th className={cell}>p90</th>
+              <th className={cell}>p95</th>
+              <th className={cell}>Max</th>
+            </tr>
+          </thead>
+          <tbody className="divide-y divide-dark-600">
+            {distributions.map(distribution => (
+              <tr key={distribution.metric}>
+                <td className={cell}>{distribution.metric.toUpperCase()}</td>
+                <td className={cell}>{distribution.count.toLocaleString()}</td>
+                <td className={cell}>{formatDuration(distribution.mean)}</td>
+                <td className={cell}>{formatDuration(distribution.median)}</td>
+                <td className={cell}>{formatDuration(distribution.p90)}</td>
+                <td className={cell}>{formatDuration(distribution.p95)}</td>
+                <td className={cell}>{formatDuration(distribution.max)}</td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+
+        <div className="grid grid-cols-1 lg:grid-cols-2 gap-6">
+          <div>
+            <h3 className="text-sm font-medium text-gray-300 mb-2">Concurrent chats</h3>
+            <div className="grid grid-cols-3 gap-3">
+              <div className={card}>
+                <div className="text-xs text-gray-400">Max</div>
+                <div className="text-lg font-semibold text-white">{concurrency.max}</div>
+                {concurrency.peak_at && <div className="text-xs text-gray-400">{concurrency.peak_at}</div>}
+              </div>
+              <div className={card}>
+                <div className="text-xs text-gray-400">Average</div>
+                <div className="text-lg font-semibold text-white">{concurrency.average.toFixed(2)}</div>
+              </div>
+              <div className={card}>
+                <div className="text-xs text-gray-400">Overlapping</div>
+                <div className="text-lg font-semibold text-white">{concurrency.overlap_share.toFixed(1)}%</div>
+              </div>
+            </div>
+            <p className="text-xs text-gray-400 mt-2">
+              Chats with resolution time: {concurrency.chats.toLocaleString()}
+            </p>
+          </div>
+
+          <div>
+            <h3 className="text-sm font-medium text-gray-300 mb-2">Top topics</h3>
+            {detail.topics.length === 0 ? (
+              <p className="text-sm text-gray-400">Нет обращений с классификаторами</p>
+            ) : (
+              <div className="space-y-1">
+                {detail.topics.map(topic => (
+                  <div key={topic.topic} className="flex items-center text-xs">
+                    <span className="w-40 truncate text-gray-300" title={topic.topic}>{topic.topic}</span>
+                    <div className="flex-1 bg-dark-700 rounded h-3 mx-2">
+                      <div className="bg-primary-600 h-3 rounded" style={{ width: `${topic.ratio}%` }} />
+                    </div>
+                    <span className="w-24 text-right text-white">{topic.total.toLocaleString()} ({topic.ratio.toFixed(1)}%)</span>
+                  </div>
+                ))}
+              </div>
+            )}
+          </div>
+        </div>
+      </>
+    );
+  };
+
+  return (
+    <div className="bg-dark-800 p-6 rounded-lg border border-dark-700 mt-6">
+      <div className="flex justify-between items-start mb-4">
+        <div>
+          <h2 className="text-xl font-semibold text-white">{detail?.name || userId}</h2>
+          {detail && detail.name !== detail.user_id && <p className="text-xs text-gray-400">{detail.user_id}</p>}
+        </div>
+        <button onClick={onClose} className="text-gray-400 hover:text-white" title="Close">
+          <X className="w-5 h-5" />
+        </button>
+      </div>
+
+      {loading && (
+        <div className="flex items-center space-x-2 text-gray-400">
+          <Loader2 className="w-4 h-4 animate-spin" />
+          <span className="text-sm">Загрузка...</span>
+        </div>
+      )}
+
+      {error && <div className="text-red-400 text-sm">{error}</div>}
+
+      {!loading && !error && detail && renderDetail(detail)}
+    </div>
+  );
+};
+
+export default AgentDetailPanel;
@@ -0,0 +1,195 @@
+import React, { useState, useEffect } from 'react';
+import { Loader2 } from 'lucide-react';
+import clsx from 'clsx';
+import { GetAgentPerformance } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { useRequestScope } from '../utils/requests';
+import { formatDuration } from '../utils/duration';
+import AgentDetailPanel from './AgentDetailPanel';
+
+interface AgentsViewProps {
+  queueName: string;
+  startDate: string;
+  endDate: string;
+  shouldLoadData: boolean;
+  onDataLoaded: () => void;
+}
+
+// Колонки рейтинга; по каждой можно отсортировать рейтинг
+const columns: { id: keyof main.AgentMetrics; label: string; duration?: boolean }[] = [
+  { id: 'handled', label: 'Handled' },
+  { id: 'calls', label: 'Calls' },
+  { id: 'chats', label: 'Chats' },
+  { id: 'aht', label: 'AHT', duration: true },
+  { id: 'frt', label: 'FRT', duration: true },
+  { id: 'rt', label: 'RT', duration: true },
+  { id: 'active_hours', label: 'Active hours' },
+];
+
+// Рейтинг агентов группы очередей со сравнением с медианой команды
+const AgentsView: React.FC<AgentsViewProps> = ({
+  queueName,
+  startDate,
+  endDate,
+  shouldLoadData,
+  onDataLoaded
+}) => {
+  const [sortBy, setSortBy] = useState<string>('handled');
+  const [report, setReport] = useState<main.AgentPerformanceReport | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string>('');
+  const [selectedAgent, setSelectedAgent] = useState<string>('');
+  const beginLoad = useRequestScope('agents');
+
+  const loadData = async () => {
+    if (!startDate || !endDate) return;
+
+    const load = beginLoad();
+    setLoading(true);
+    setError('');
+
+    try {
+      const result = await GetAgentPerformance(load.id(), startDate, endDate, queueName, sortBy);
+      if (load.stale()) return;
+      console.debug('Agent performance timings:', result.timings);
+      setReport(result);
+      onDataLoaded();
+    } catch (error) {
+      if (load.stale()) return; // запрос отменен новой загрузкой
+      console.error('Ошибка загрузки рейтинга агентов:', error);
+      setError(`Ошибка загрузки данных: ${error}`);
+      onDataLoaded();
+    } finally {
+      if (!load.stale()) setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    if (shouldLoadData) {
+      loadData();
+    }
+  }, [shouldLoadData, queueName, startDate, endDate]);
+
+  // Смена сортировки сразу перезагружает рейтинг
+  useEffect(() => {
+    if (report) {
+      loadData();
+    }
+  }, [sortBy]);
+
+  const formatValue = (column: typeof columns[number], value: number) => {
+    if (column.duration) return formatDuration(value);
+    if (column.id === 'active_hours') return value.toFixed(2);
+    return Number.isInteger(value) ? value.toLocaleString() : value.toFixed(1);
+  };
+
+  const renderTable = () => {
+    if (!report || report.agents.length === 0) {
+      return (
+        <div className="bg-dark-800 p-6 rounded-lg border border-dark-700 text-center">
+          <p className="text-gray-400">За выбранный период агентов не найдено</p>
+        </div>
+      );
+    }
+
+    const cell = 'px-4 py-3 text-center text-white border-r border-dark-600 whitespace-nowrap';
+
+    return (
+      <div className="bg-dark-800 rounded-lg border border-dark-700 overflow-hidden">
+        <div className="overflow-x-auto">
+          <table className="w-full">
+            <thead>
+              <tr className="bg-dark-700">
+                <th className={clsx(cell, 'font-medium')}>#</th>
+                <th className={clsx(cell, 'text-left font-medium')}>Agent</th>
+                {columns.map(column => (
+                  <th
+                    key={column.id}
+                    onClick={() => setSortBy(column.id)}
+                    className={clsx(cell, 'font-medium cursor-pointer hover:bg-dark-600', sortBy === column.id && 'bg-primary-600/30')}
+                  >
+                    {column.label}
+                  </th>
+                ))}
+                <th className={clsx(cell, 'font-medium')}>vs median</th>
+              </tr>
+            </thead>
+            <tbody className="divide-y divide-dark-600">
+              {report.agents.map(agent => (
+                <tr
+                  key={agent.user_id}
+                  onClick={() => setSelectedAgent(agent.user_id)}
+                  className={clsx('cursor-pointer hover:bg-dark-700', selectedAgent === agent.user_id && 'bg-dark-700')}
+                >
+                  <td className={cell}>{agent.rank || '—'}</td>
+                  <td className={clsx(cell, 'text-left')}>
+                    <div>{agent.name}</div>
+                    {agent.name !== agent.user_id && <div className="text-xs text-gray-400">{agent.user_id}</div>}
+                  </td>
+                  {columns.map(column => (
+                    <td key={column.id} className={clsx(cell, sortBy === column.id && 'font-semibold')}>
+                      {formatValue(column, agent.metrics[column.id])}
+                    </td>
+                  ))}
+                  <td className={clsx(cell, agent.rank ? (agent.better_than_median ? 'text-green-400' : 'text-red-400') : 'text-gray-500')}>
+                    {agent.rank ? `${agent.vs_median > 0 ? '+' : ''}${agent.vs_median.toFixed(1)}%` : '—'}
+                  </td>
+                </tr>
+              ))}
+              <tr className="bg-dark-700 font-semibold">
+                <td className={cell}></td>
+                <td className={clsx(cell, 'text-left')}>Team median</td>
+                {columns.map(column => (
+                  <td key={column.id} className={cell}>{formatValue(column, report.median[column.id])}</td>
+                ))}
+                <td className={cell}></td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    );
+  };
+
+  return (
+    <div className="flex-1 p-8 bg-white dark:bg-dark-900 overflow-y-auto h-screen transition-colors duration-300">
+      <div className="max-w-full">
+        {/* Заголовок */}
+        <div className="bg-dark-800 p-6 rounded-lg border border-dark-700 mb-6">
+          <h2 className="text-xl font-semibold text-white">Agents - {queueName.toUpperCase()}</h2>
+          <p className="text-sm text-gray-400 mt-2">
+            Period: {startDate} - {endDate}. Click a column to rank by it; durations rank lower first. Click an agent for details.
+          </p>
+
+          {loading && (
+            <div className="flex items-center space-x-2 text-gray-400 mt-3">
+              <Loader2 className="w-4 h-4 animate-spin" />
+              <span className="text-sm">Загрузка...</span>
+            </div>
+          )}
+
+          {error && (
+            <div className="mt-3 text-red-400 text-sm">
+              {error}
+            </div>
+          )}
+        </div>
+
+        {!loading && !error && renderTable()}
+
+        {selectedAgent && (
+          <AgentDetailPanel
+            queueName={queueName}
+            startDate={startDate}
+            endDate={endDate}
+            userId={selectedAgent}
+            reloadKey={report}
+            onClose={() => setSelectedAgent('')}
+          />
+        )}
+      </div>
+    </div>
+  );
+};
+
+export default AgentsView;
@@ -2,6 +2,7 @@ import React, { useState, useEffect } from 'react';
 import { Download, FileSpreadsheet, Loader2, AlertCircle, ChevronUp, ChevronDown, ChevronsUpDown, Search, X, Copy, Check, CheckSquare, Square } from 'lucide-react';
 import { GetCallClassifiers, GetChatClassifiers, GetOverallClassifiers, GetTopics, GetAvailableTopics, GetSubtopicsDaily } from '../../wailsjs/go/main/App';
 import { exportClassifiersToExcel } from '../utils/excelExport';
+import { useRequestScope } from '../utils/requests';
 
 // Component types
 type ClassifierMetric = 'call' | 'chat' | 'overall' | 'topics' | 'subtopics_daily';
@@ -64,6 +65,7 @@ const ClassifiersView: React.FC<ClassifiersViewProps> = ({
   const [availableTopics, setAvailableTopics] = useState<Array<{topic: string}>>([]);
   const [selectedTopic, setSelectedTopic] = useState<string>('');
   const [allSubtopicsData, setAllSubtopicsData] = useState<ClassifierData[]>([]);  // Все данные субтопиков
+  const beginLoad = useRequestScope('classifiers');
 
   // Функция извлечения доступных топиков из загруженных данных
   const extractAvailableTopics = (data: ClassifierData[]) => {
@@ -87,6 +89,7 @@ const ClassifiersView: React.FC<ClassifiersViewProps> = ({
       selectedTopic: activeMetric === 'subtopics_daily' ? selectedTopic : 'N/A' 
     });
     
+    const load = beginLoad();
     setLoading(true);
     setError(null);
     
@@ -95,16 +98,16 @@ const ClassifiersView: React.FC<ClassifiersViewProps> = ({
       
       switch (activeMetric) {
         case 'call':
-          response = await GetCallClassifiers(startDate, endDate, queueName);
+          response = await GetCallClassifiers(load.id(), startDate, endDate, queueName);
           break;
         case 'chat':
-          response = await GetChatClassifiers(startDate, endDate, queueName);
+          response = await GetChatClassifiers(load.id(), startDate, endDate, queueName);
           break;
         case 'overall':
-          response = await GetOverallClassifiers(startDate, endDate, queueName);
+          response = await GetOverallClassifiers(load.id(), startDate, endDate, queueName);
           break;
         case 'topics':
-          response = await GetTopics(startDate, endDate, queueName);
+          response = await GetTopics(load.id(), startDate, endDate, queueName);
           break;
         case 'subtopics_daily':
           // Загружаем ВСЕ данные классификаторов сразу для быстрой фильтрации
@@ -112,9 +115,10 @@ const ClassifiersView: React.FC<ClassifiersViewProps> = ({
           
           // Загружаем данные звонков и чатов параллельно
           const [callDataResponse, chatDataResponse] = await Promise.all([
-            GetCallClassifiers(startDate, endDate, queueName),
-            GetChatClassifiers(startDate, endDate, queueName)
+            GetCallClassifiers(load.id(), startDate, endDate, queueName),
+            GetChatClassifiers(load.id(), startDate, endDate, queueName)
           ]);
+          if (load.stale()) return;
           
           // Объединяем данные
           const combinedData = [
@@ -138,6 +142,7 @@ const ClassifiersView: React.FC<ClassifiersViewProps> = ({
           throw new Error('Unknown classifier metric');
       }
 
+      if (load.stale()) return;
       const results = response.data || [];
       setData(results);
       
@@ -158,11 +163,14 @@ const ClassifiersView: React.FC<ClassifiersViewProps> = ({
       console.log(`Loaded ${results.length} classifier records for ${activeMetric} metric`);
       
     } catch (err) {
+      if (load.stale()) return; // запрос отменен новой загрузкой
       console.error('Error loading classifier data:', err);
       setError(err instanceof Error ? err.message : 'Unknown error');
     } finally {
-      setLoading(false);
-      onDataLoaded();
+      if (!load.stale()) {
+        setLoading(false);
+        onDataLoaded();
+      }
     }
   };
 
@@ -0,0 +1,241 @@
+import React, { useState, useEffect } from 'react';
+import { X, Loader2, CheckCircle, XCircle } from 'lucide-react';
+import {
+  GetConnectionSettings,
+  TestConnectionSettings,
+  SaveConnectionSettings,
+  UnlockSecretStore
+} from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+
+interface ConnectionSettingsProps {
+  isOpen: boolean;
+  onClose: () => void;
+  onSaved: () => void;
+}
+
+type Section = 'mysql' | 'mongodb' | 'ssh';
+
+const databases: Section[] = ['mysql', 'mongodb'];
+
+interface Field {
+  key: string;
+  label: string;
+  sections?: Section[];
+  options?: string[];
+}
+
+const fields: Field[] = [
+  { key: 'uri', label: 'URI', sections: ['mongodb'] },
+  { key: 'host', label: 'Хост' },
+  { key: 'port', label: 'Порт' },
+  { key: 'user', label: 'Пользователь' },
+  { key: 'password', label: 'Пароль', sections: databases },
+  { key: 'database', label: 'База данных', sections: databases },
+  { key: 'auth_source', label: 'authSource', sections: ['mongodb'] },
+  { key: 'auth_mechanism', label: 'Механизм', sections: ['mongodb'], options: ['', 'SCRAM-SHA-1', 'SCRAM-SHA-256', 'MONGODB-X509'] },
+  { key: 'tls.mode', label: 'TLS', sections: databases, options: ['disabled', 'verify', 'skip-verify'] },
+  { key: 'tls.ca_file', label: 'CA (PEM)', sections: databases },
+  { key: 'tls.cert_file', label: 'Сертификат', sections: databases },
+  { key: 'tls.key_file', label: 'Ключ', sections: databases },
+  { key: 'key_file', label: 'Ключ SSH', sections: ['ssh'] },
+  { key: 'known_hosts', label: 'known_hosts', sections: ['ssh'] },
+];
+
+// Чтение и запись вложенных полей вида "tls.mode"
+const getField = (target: any, path: string): string =>
+  path.split('.').reduce((value, key) => (value ? value[key] : undefined), target) || '';
+
+const setField = (target: any, path: string, value: string) => {
+  const keys = path.split('.');
+  const last = keys.pop() as string;
+  const parent = keys.reduce((obj, key) => (obj[key] = obj[key] || {}), target);
+  parent[last] = value;
+};
+
+// Редактор настроек подключения: проверка, сохранение и переподключение без перезапуска
+const ConnectionSettings: React.FC<ConnectionSettingsProps> = ({ isOpen, onClose, onSaved }) => {
+  const [config, setConfig] = useState<main.DatabaseConfig | null>(null);
+  const [settings, setSettings] = useState<main.ConnectionSettings | null>(null);
+  const [passphrase, setPassphrase] = useState('');
+  const [testResult, setTestResult] = useState<main.ConnectionTestResult | null>(null);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  const loadSettings = async () => {
+    try {
+      const current = await GetConnectionSettings();
+      setSettings(current);
+      setConfig(main.DatabaseConfig.createFrom(current.config));
+      setError(null);
+    } catch (err) {
+      setError(String(err));
+    }
+  };
+
+  useEffect(() => {
+    if (isOpen) {
+      setTestResult(null);
+      loadSettings();
+    }
+  }, [isOpen]);
+
+  if (!isOpen || !config) {
+    return null;
+  }
+
+  const updateField = (section: Section, key: string, value: string) => {
+    const next = main.DatabaseConfig.createFrom(config);
+    setField(next, `${section}.${key}`, value);
+    setConfig(next);
+    setTestResult(null);
+  };
+
+  const handleUnlock = async () => {
+    setBusy(true);
+    try {
+      await UnlockSecretStore(passphrase);
+      setPassphrase('');
+      await loadSettings();
+    } catch (err) {
+      setError(String(err));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleTest = async () => {
+    setBusy(true);
+    setError(null);
+    try {
+      setTestResult(await TestConnectionSettings(config));
+    } catch (err) {
+      setError(String(err));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleSave = async () => {
+    setBusy(true);
+    setError(null);
+    try {
+      await SaveConnectionSettings(config);
+      onSaved();
+      onClose();
+    } catch (err) {
+      setError(String(err));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const passwordSet = (section: Section) =>
+    section === 'mysql' ? settings?.mysql_password_set : settings?.mongodb_password_set;
+
+  const sectionResult = (section: Section) =>
+    testResult && section !== 'ssh' ? testResult[section] : undefined;
+
+  const renderSection = (section: Section, title: string) => (
+    <div className="space-y-2">
+      <div className="flex items-center justify-between">
+        <h4 className="text-sm font-semibold text-gray-700 dark:text-gray-300 uppercase">{title}</h4>
+        {sectionResult(section) && (
+          <span className="flex items-center space-x-1 text-xs">
+            {sectionResult(section) === 'Подключено'
+              ? <CheckCircle className="w-4 h-4 text-green-500" />
+              : <XCircle className="w-4 h-4 text-red-500" />}
+            <span className="text-gray-700 dark:text-gray-300">{sectionResult(section)}</span>
+          </span>
+        )}
+      </div>
+      {fields.filter(field => !field.sections || field.sections.includes(section)).map(field => (
+        <label key={field.key} className="flex items-center justify-between text-sm">
+          <span className="w-32 text-gray-600 dark:text-gray-400">{field.label}</span>
+          {field.options ? (
+            <select
+              value={getField(config[section], field.key)}
+              onChange={e => updateField(section, field.key, e.target.value)}
+              className="flex-1 px-2 py-1 text-sm rounded border border-gray-300 dark:border-dark-600 bg-white dark:bg-dark-700 text-gray-900 dark:text-white"
+            >
+              {field.options.map(option => (
+                <option key={option} value={option}>{option || 'по умолчанию'}</option>
+              ))}
+            </select>
+          ) : (
+            <input
+              type={field.key === 'password' ? 'password' : 'text'}
+              value={getField(config[section], field.key)}
+              placeholder={field.key === 'password' && passwordSet(section) ? 'сохранен, оставьте пустым' : ''}
+              onChange={e => updateField(section, field.key, e.target.value)}
+              className="flex-1 px-2 py-1 text-sm rounded border border-gray-300 dark:border-dark-600 bg-white dark:bg-dark-700 text-gray-900 dark:text-white"
+            />
+          )}
+        </label>
+      ))}
+    </div>
+  );
+
+  return (
+    <div className="fixed inset-0 z-50 flex items-center justify-center bg-black/50">
+      <div className="w-[560px] max-h-[90vh] overflow-y-auto rounded-lg bg-white dark:bg-dark-800 p-6 space-y-4 shadow-xl">
+        <div className="flex items-center justify-between">
+          <h3 className="text-lg font-bold text-gray-900 dark:text-white">
+            Настройки подключения{settings?.profile ? `: ${settings.profile}` : ''}
+          </h3>
+          <button onClick={onClose} className="text-gray-500 hover:text-gray-900 dark:hover:text-white">
+            <X className="w-5 h-5" />
+          </button>
+        </div>
+
+        {settings?.secret_store_locked && (
+          <div className="flex items-center space-x-2">
+            <input
+              type="password"
+              value={passphrase}
+              placeholder="Мастер-фраза хранилища паролей"
+              onChange={e => setPassphrase(e.target.value)}
+              className="flex-1 px-2 py-1 text-sm rounded border border-gray-300 dark:border-dark-600 bg-white dark:bg-dark-700 text-gray-900 dark:text-white"
+            />
+            <button
+              onClick={handleUnlock}
+              disabled={busy || !passphrase}
+              className="px-3 py-1 text-sm rounded-lg bg-primary-600 text-white disabled:opacity-50"
+            >
+              Разблокировать
+            </button>
+          </div>
+        )}
+
+        {renderSection('mysql', 'MySQL')}
+        {renderSection('mongodb', 'MongoDB')}
+        {renderSection('ssh', 'SSH jump-host (необязательно)')}
+
+        {settings?.config_path && (
+          <p className="text-xs text-gray-500 dark:text-gray-400">Файл: {settings.config_path}</p>
+        )}
+        {error && <p className="text-sm text-red-500">{error}</p>}
+
+        <div className="flex justify-end space-x-2">
+          {busy && <Loader2 className="w-5 h-5 animate-spin text-gray-500 self-center" />}
+          <button
+            onClick={handleTest}
+            disabled={busy}
+            className="px-4 py-2 text-sm rounded-lg border border-gray-300 dark:border-dark-600 text-gray-700 dark:text-gray-300 disabled:opacity-50"
+          >
+            Проверить
+          </button>
+          <button
+            onClick={handleSave}
+            disabled={busy}
+            className="px-4 py-2 text-sm rounded-lg bg-primary-600 text-white disabled:opacity-50"
+          >
+            Сохранить и переподключить
+          </button>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ConnectionSettings;
@@ -11,7 +11,13 @@ import {
 } from 'chart.js';
 import { Bar } from 'react-chartjs-2';
 import { Calendar, Filter, Loader2, Play, Download, FileSpreadsheet } from 'lucide-react';
-import { GetDailyData } from '../../wailsjs/go/main/App';
+import { GetDailyData, GetPeriodData } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { formatDuration } from '../utils/duration';
+import { useRequestScope } from '../utils/requests';
+import ServiceLevelPanel from './ServiceLevelPanel';
+import AbandonWaitsPanel from './AbandonWaitsPanel';
+import DurationDistributionPanel from './DurationDistributionPanel';
 import { exportMetricToExcel, exportAllDataToExcel, exportHourlyDetailedToExcel } from '../utils/excelExport';
 
 // Регистрируем компоненты Chart.js
@@ -32,10 +38,17 @@ interface DailyData {
   avg_call_duration_minutes: number;
   sl: number;
   total_abandoned: number;
+  abandon_rate: number;
+  asa: number; // ожидание отвеченных звонков, сек
+  max_wait: number;
+  wait_p50: number;
+  wait_p90: number;
+  wait_p95: number;
   total_chats: number;
   avg_chat_frt: string;
   resolution_time_avg: string;
   distinct_agents: number;
+  occupancy: number; // занятость агентов, %
   total_inquiries: number;
 }
 
@@ -69,6 +82,16 @@ interface HourlyDetailedData {
   hour_23: number | string;
 }
 
+// Детализация: по дням или по периодам, пересчитанным на сервере из сумм
+const granularityOptions = [
+  { value: 'day', label: 'Day' },
+  { value: 'week', label: 'Week' },
+  { value: 'month', label: 'Month' },
+  { value: 'quarter', label: 'Quarter' },
+  { value: 'year', label: 'Year' },
+  { value: 'period', label: 'Period' },
+];
+
 interface DailyViewProps {
   queueName: string;
   startDate: string;
@@ -86,6 +109,8 @@ const DailyView: React.FC<DailyViewProps> = ({ queueName, startDate, endDate, ac
   const [chartData, setChartData] = useState<any[]>([]);
   const [hourlyDetailedData, setHourlyDetailedData] = useState<HourlyDetailedData[]>([]);
     const [isDarkMode, setIsDarkMode] = useState(document.documentElement.classList.contains('dark'));
+  const [granularity, setGranularity] = useState('day');
+  const beginLoad = useRequestScope('daily');
 
   // Отслеживаем изменения темы
   useEffect(() => {
@@ -108,6 +133,13 @@ const DailyView: React.FC<DailyViewProps> = ({ queueName, startDate, endDate, ac
     }
   }, [shouldLoadData, queueName, startDate, endDate]);
 
+  // Смена детализации сразу перезагружает данные
+  useEffect(() => {
+    if (data) {
+      loadData();
+    }
+  }, [granularity]);
+
   // Обновление данных графика при смене активной метрики или данных
   useEffect(() => {
     if (tableData.length > 0) {
@@ -165,6 +197,27 @@ const DailyView: React.FC<DailyViewProps> = ({ queueName, startDate, endDate, ac
           name: 'Abandoned'
         }));
         break;
+      case 'abandon_rate':
+        chartRows = tableData.map(row => ({
+          date: formatDate(row.date),
+          value: row.abandon_rate,
+          name: 'Abandon rate (%)'
+        }));
+        break;
+      case 'asa':
+        chartRows = tableData.map(row => ({
+          date: formatDate(row.date),
+          value: row.asa,
+          name: 'ASA (sec.)'
+        }));
+        break;
+      case 'occupancy':
+        chartRows = tableData.map(row => ({
+          date: formatDate(row.date),
+          value: row.occupancy,
+          name: 'Occupancy (%)'
+        }));
+        break;
       case 'chats':
         chartRows = tableData.map(row => ({
           date: formatDate(row.date),
@@ -497,65 +550,110 @@ const DailyView: React.FC<DailyViewProps> = ({ queueName, startDate, endDate, ac
   // Загрузка данных
   const loadData = async () => {
     // Убрана проверка очереди - теперь загружаем данные для всех очередей
+    const load = beginLoad();
     setLoading(true);
     setError(null);
 
     try {
-      const result = await GetDailyData(startDate, endDate, queueName);
+      const result = granularity === 'day'
+        ? await GetDailyData(load.id(), startDate, endDate, queueName)
+        : periodSeries(await GetPeriodData(load.id(), startDate, endDate, queueName, granularity));
+      if (load.stale()) return;
+      console.debug('Daily query timings:', result.timings);
       setData(result);
       processData(result);
       onDataLoaded();
     } catch (err) {
+      if (load.stale()) return; // запрос отменен новой загрузкой
       setError(`Data loading error: ${err}`);
       console.error('Data loading error:', err);
       onDataLoaded(); // Вызываем колбэк даже при ошибке
     } finally {
-      setLoading(false);
+      if (!load.stale()) setLoading(false);
     }
   };
 
-  // Обработка данных
-  const processData = (rawData: any) => {
-    if (!rawData) return;
+  // Периоды приводятся к рядам метрик; вместо даты — подпись периода
+  const periodSeries = (report: main.PeriodReport): main.DailySeries => {
+    const periods = report.periods || [];
+    const points = (value: (period: main.PeriodMetrics) => number) =>
+      periods.map(period => ({ date: period.period, value: value(period) }));
+
+    return main.DailySeries.createFrom({
+      calls: points(p => p.calls),
+      aht: points(p => p.aht),
+      sl: points(p => p.sl),
+      abandoned: points(p => p.abandoned),
+      abandon_rate: points(p => p.abandon_rate),
+      asa: points(p => p.asa),
+      max_wait: points(p => p.max_wait),
+      wait_p50: points(p => p.wait_p50),
+      wait_p90: points(p => p.wait_p90),
+      wait_p95: points(p => p.wait_p95),
+      chats: points(p => p.chats),
+      frt: points(p => p.frt),
+      rt: points(p => p.rt),
+      agents: points(p => p.agents),
+      occupancy: points(p => p.occupancy),
+      sl_target: report.sl_target,
+      timings: report.timings || [],
+    });
+  };
+
+  // Обработка данных: ряды метрик сводятся в строки таблицы по датам
+  const processData = (series: main.DailySeries) => {
+    if (!series) return;
+
+    const byDate = (points: main.DailyPoint[]) =>
+      new Map((points || []).map(point => [point.date, point.value]));
+    const calls = byDate(series.calls);
+    const aht = byDate(series.aht);
+    const sl = byDate(series.sl);
+    const abandoned = byDate(series.abandoned);
+    const abandonRate = byDate(series.abandon_rate);
+    const asa = byDate(series.asa);
+    const maxWait = byDate(series.max_wait);
+    const waitP50 = byDate(series.wait_p50);
+    const waitP90 = byDate(series.wait_p90);
+    const waitP95 = byDate(series.wait_p95);
+    const chats = byDate(series.chats);
+    const frt = byDate(series.frt);
+    const rt = byDate(series.rt);
+    const agents = byDate(series.agents);
+    const occupancy = byDate(series.occupancy);
 
     // Собираем все даты
     const allDates = new Set<string>();
-    Object.values(rawData).forEach((metricData: any) => {
-      if (Array.isArray(metricData)) {
-        metricData.forEach((item: any) => {
-          if (item.date) allDates.add(item.date);
-        });
-      }
+    [calls, aht, sl, abandoned, chats, frt, rt, agents].forEach(values => {
+      values.forEach((_, date) => allDates.add(date));
     });
 
     const sortedDates = Array.from(allDates).sort();
 
-    // Создаем данные для таблицы
+    // Создаем данные для таблицы; длительности приходят в секундах
     const processedData: DailyData[] = sortedDates.map(date => {
-      const callsItem = rawData.calls?.find((item: any) => item.date === date);
-      const ahtItem = rawData.aht?.find((item: any) => item.date === date);
-      const slItem = rawData.sl?.find((item: any) => item.date === date);
-      const abandonedItem = rawData.abandoned?.find((item: any) => item.date === date);
-      const chatsItem = rawData.chats?.find((item: any) => item.date === date);
-      const frtItem = rawData.frt?.find((item: any) => item.date === date);
-      const rtItem = rawData.rt?.find((item: any) => item.date === date);
-      const agentsItem = rawData.agents?.find((item: any) => item.date === date);
-
-      const ahtString = ahtItem?.avg_call_duration || '00:00:00';
-      const totalCalls = callsItem?.total_calls || 0;
-      const totalChats = chatsItem?.total_chats || 0;
+      const ahtSeconds = aht.get(date) || 0;
+      const totalCalls = calls.get(date) || 0;
+      const totalChats = chats.get(date) || 0;
 
       return {
         date,
         total_calls: totalCalls,
-        avg_call_duration: ahtString,
-        avg_call_duration_minutes: timeToMinutes(ahtString),
-        sl: slItem?.sl || 0,
-        total_abandoned: abandonedItem?.total_abandoned || 0,
+        avg_call_duration: formatDuration(ahtSeconds),
+        avg_call_duration_minutes: ahtSeconds / 60,
+        sl: sl.get(date) || 0,
+        total_abandoned: abandoned.get(date) || 0,
+        abandon_rate: abandonRate.get(date) || 0,
+        asa: asa.get(date) || 0,
+        max_wait: maxWait.get(date) || 0,
+        wait_p50: waitP50.get(date) || 0,
+        wait_p90: waitP90.get(date) || 0,
+        wait_p95: waitP95.get(date) || 0,
         total_chats: totalChats,
-        avg_chat_frt: frtItem?.avg_chat_frt || '00:00:00',
-        resolution_time_avg: rtItem?.resolution_time_avg || '00:00:00',
-        distinct_agents: agentsItem?.distinct_agents || 0,
+        avg_chat_frt: formatDuration(frt.get(date) || 0),
+        resolution_time_avg: formatDuration(rt.get(date) || 0),
+        distinct_agents: agents.get(date) || 0,
+        occupancy: occupancy.get(date) || 0,
         total_inquiries: totalCalls + totalChats
       };
     });
@@ -571,6 +669,8 @@ const DailyView: React.FC<DailyViewProps> = ({ queueName, startDate, endDate, ac
 
   // Форматирование даты
   const formatDate = (dateStr: string) => {
+    // Подписи недель, месяцев и кварталов показываем как есть
+    if (!/^\d{4}-\d{2}-\d{2}$/.test(dateStr)) return dateStr;
     return new Date(dateStr).toLocaleDateString('ru-RU', { 
       month: '2-digit', 
       day: '2-digit' 
@@ -785,7 +885,12 @@ const DailyView: React.FC<DailyViewProps> = ({ queueName, startDate, endDate, ac
   const getMetricTarget = (metric: string) => {
     switch (metric) {
       case 'aht': return { value: 3, label: 'target - 3 min.' };
-      case 'sl': return { value: 95, label: 'target - 95%' };
+      case 'sl': {
+        // Цель SL группы очередей из конфигурации
+        const target = data?.sl_target;
+        if (!target) return null;
+        return { value: target.target_percent, label: `target - ${target.target_percent}% in ${target.threshold_seconds}s` };
+      }
       default: return null;
     }
   };
@@ -989,6 +1094,73 @@ const DailyView: React.FC<DailyViewProps> = ({ queueName, startDate, endDate, ac
            </table>
          );
 
+       case 'abandon_rate':
+         return (
+           <table className="w-full">
+             <thead>
+               <tr className="bg-dark-700">
+                 <th className="px-4 py-3 text-left font-medium text-white border-r border-dark-600">
+                   Metric / {new Date().getFullYear()}
+                 </th>
+                 {dates.map((date, index) => (
+                   <th key={index} className="px-4 py-3 text-center font-medium text-white border-r border-dark-600">
+                     {date}
+                   </th>
+                 ))}
+               </tr>
+             </thead>
+             <tbody>
+               {renderTableForMetric('Abandon rate (%)', row => row.abandon_rate, val => `${val.toFixed(1)}%`)}
+             </tbody>
+           </table>
+         );
+
+       case 'asa':
+         return (
+           <table className="w-full">
+             <thead>
+               <tr className="bg-dark-700">
+                 <th className="px-4 py-3 text-left font-medium text-white border-r border-dark-600">
+                   Metric / {new Date().getFullYear()}
+                 </th>
+                 {dates.map((date, index) => (
+                   <th key={index} className="px-4 py-3 text-center font-medium text-white border-r border-dark-600">
+                     {date}
+                   </th>
+                 ))}
+               </tr>
+             </thead>
+             <tbody>
+               {renderTableForMetric('ASA', row => row.asa, formatDuration)}
+               {renderTableForMetric('Max wait', row => row.max_wait, formatDuration)}
+               {renderTableForMetric('Wait p50', row => row.wait_p50, formatDuration)}
+               {renderTableForMetric('Wait p90', row => row.wait_p90, formatDuration)}
+               {renderTableForMetric('Wait p95', row => row.wait_p95, formatDuration)}
+             </tbody>
+           </table>
+         );
+
+       case 'occupancy':
+         return (
+           <table className="w-full">
+             <thead>
+               <tr className="bg-dark-700">
+                 <th className="px-4 py-3 text-left font-medium text-white border-r border-dark-600">
+                   Metric / {new Date().getFullYear()}
+                 </th>
+                 {dates.map((date, index) => (
+                   <th key={index} className="px-4 py-3 text-center font-medium text-white border-r border-dark-600">
+                     {date}
+                   </th>
+                 ))}
+               </tr>
+             </thead>
+             <tbody>
+               {renderTableForMetric('Occupancy (%)', row => row.occupancy, val => `${val.toFixed(1)}%`)}
+             </tbody>
+           </table>
+         );
+
       case 'total':
         return (
           <table className="w-full">
@@ -1164,6 +1336,24 @@ const DailyView: React.FC<DailyViewProps> = ({ queueName, startDate, endDate, ac
         </div>
       </div>
 
+      {/* Детализация */}
+      <div className="flex space-x-1 mb-6">
+        {granularityOptions.map(option => (
+          <button
+            key={option.value}
+            onClick={() => setGranularity(option.value)}
+            disabled={loading}
+            className={`px-3 py-1.5 text-sm rounded-md transition-colors ${
+              granularity === option.value
+                ? 'bg-primary-600 text-white'
+                : 'bg-gray-100 dark:bg-dark-700 text-gray-700 dark:text-gray-300 hover:text-gray-900 dark:hover:text-white'
+            }`}
+          >
+            {option.label}
+          </button>
+        ))}
+      </div>
+
       {/* Ошибка */}
       {error && (
         <div className="bg-red-50 dark:bg-red-900/20 border border-red-200 dark:border-red-500 p-6 rounded-lg mb-6">
@@ -1252,6 +1442,12 @@ const DailyView: React.FC<DailyViewProps> = ({ queueName, startDate, endDate, ac
                case 'abandoned':
                  display = `Total for period: ${totals.totalAbandoned?.toLocaleString()}`;
                  break;
+               case 'abandon_rate':
+                 display = `Average for period: ${(tableData.reduce((sum, row) => sum + row.abandon_rate, 0) / tableData.length).toFixed(2)}%`;
+                 break;
+               case 'occupancy':
+                 display = `Average for period: ${(tableData.reduce((sum, row) => sum + row.occupancy, 0) / tableData.length).toFixed(2)}%`;
+                 break;
                case 'frt':
                case 'rt':
                  // Для FRT и RT нужно вычислить средние значения
@@ -1283,6 +1479,20 @@ const DailyView: React.FC<DailyViewProps> = ({ queueName, startDate, endDate, ac
         </div>
       )}
 
+      {/* SL по порогам */}
+      {!loading && !error && data && activeMetric === 'sl' && (
+        <ServiceLevelPanel queueName={queueName} startDate={startDate} endDate={endDate} reloadKey={data} />
+      )}
+
+      {/* Ожидание брошенных звонков */}
+      {!loading && !error && data && (activeMetric === 'abandoned' || activeMetric === 'abandon_rate') && (
+        <AbandonWaitsPanel queueName={queueName} startDate={startDate} endDate={endDate} reloadKey={data} />
+      )}
+
+      {!loading && !error && data && (activeMetric === 'aht' || activeMetric === 'frt' || activeMetric === 'rt') && (
+        <DurationDistributionPanel queueName={queueName} startDate={startDate} endDate={endDate} metric={activeMetric} reloadKey={data} />
+      )}
+
       {/* Горизонтальная таблица */}
       {!loading && !error && tableData.length > 0 && (
         <div className="bg-gray-50 dark:bg-dark-800 rounded-lg border border-gray-200 dark:border-dark-700">
@@ -2,15 +2,18 @@ import React, { useState, useEffect } from 'react';
 import { Phone, TrendingUp, Users, Clock, Calendar, BarChart3, Download, FileSpreadsheet, Database, RefreshCw } from 'lucide-react';
 import DailyView from './DailyView';
 import HourlyView from './HourlyView';
+import IntervalsView from './IntervalsView';
+import AgentsView from './AgentsView';
 import MonthlyView from './MonthlyView';
 import ClassifiersView from './ClassifiersView';
+import QueuesView from './QueuesView';
 import { exportAllDataToExcel } from '../utils/excelExport';
 import { TestMongoDBConnectionDetailed } from '../../wailsjs/go/main/App';
 
 // Типы для состояния
-type QueueFilter = 'all' | 'm10' | 'aml';
-type DashboardView = 'daily' | 'hourly' | 'monthly' | 'classifiers' | 'online';
-type StandardMetric = 'calls' | 'aht' | 'sl' | 'chats' | 'frt' | 'rt' | 'abandoned' | 'total' | 'detailed_daily';
+type QueueFilter = string;
+type DashboardView = 'daily' | 'hourly' | 'intervals' | 'monthly' | 'agents' | 'classifiers' | 'queues' | 'online';
+type StandardMetric = 'calls' | 'aht' | 'sl' | 'chats' | 'frt' | 'rt' | 'abandoned' | 'abandon_rate' | 'asa' | 'occupancy' | 'total' | 'detailed_daily';
 type ClassifierMetric = 'call' | 'chat' | 'overall' | 'topics' | 'subtopics_daily';
 
 interface DashboardProps {
@@ -118,6 +121,17 @@ const Dashboard: React.FC<DashboardProps> = ({
             onDataLoaded={onDataLoaded}
           />
         );
+      case 'intervals':
+        return (
+          <IntervalsView
+            queueName={activeQueue}
+            startDate={startDate}
+            endDate={endDate}
+            activeMetric={activeStandardMetric}
+            shouldLoadData={shouldLoadData}
+            onDataLoaded={onDataLoaded}
+          />
+        );
       case 'monthly':
         return (
           <MonthlyView
@@ -127,6 +141,16 @@ const Dashboard: React.FC<DashboardProps> = ({
             onDataLoaded={onDataLoaded}
           />
         );
+      case 'agents':
+        return (
+          <AgentsView
+            queueName={activeQueue}
+            startDate={startDate}
+            endDate={endDate}
+            shouldLoadData={shouldLoadData}
+            onDataLoaded={onDataLoaded}
+          />
+        );
       case 'classifiers':
         return (
                       <ClassifiersView
@@ -138,6 +162,15 @@ const Dashboard: React.FC<DashboardProps> = ({
               onDataLoaded={onDataLoaded}
             />
         );
+      case 'queues':
+        return (
+          <QueuesView
+            startDate={startDate}
+            endDate={endDate}
+            shouldLoadData={shouldLoadData}
+            onDataLoaded={onDataLoaded}
+          />
+        );
       case 'online':
         return (
           <div className="flex-1 p-8 bg-white dark:bg-dark-900 overflow-y-auto h-screen transition-colors duration-300">
@@ -0,0 +1,140 @@
+import React, { useState, useEffect } from 'react';
+import { Loader2 } from 'lucide-react';
+import clsx from 'clsx';
+import { GetDurationDistribution } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { useRequestScope } from '../utils/requests';
+import { formatDuration } from '../utils/duration';
+
+interface DurationDistributionPanelProps {
+  queueName: string;
+  startDate: string;
+  endDate: string;
+  metric: string; // aht, frt или rt
+  reloadKey: unknown; // новые данные Daily — перечитать распределение
+}
+
+// Пороги отсечения выбросов, сек; 0 — без отсечения
+const capOptions = [
+  { value: 0, label: 'No cap' },
+  { value: 1800, label: '30m' },
+  { value: 3600, label: '1h' },
+  { value: 7200, label: '2h' },
+  { value: 14400, label: '4h' },
+];
+
+// Распределение длительности за период: медиана, перцентили и гистограмма
+const DurationDistributionPanel: React.FC<DurationDistributionPanelProps> = ({ queueName, startDate, endDate, metric, reloadKey }) => {
+  const [capSeconds, setCapSeconds] = useState(0);
+  const [report, setReport] = useState<main.DurationDistribution | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string>('');
+  const beginLoad = useRequestScope('duration-distribution');
+
+  const loadData = async () => {
+    if (!startDate || !endDate) return;
+
+    const load = beginLoad();
+    setLoading(true);
+    setError('');
+
+    try {
+      const result = await GetDurationDistribution(load.id(), startDate, endDate, queueName, metric, capSeconds);
+      if (load.stale()) return;
+      setReport(result);
+    } catch (error) {
+      if (load.stale()) return; // запрос отменен новой загрузкой
+      console.error('Ошибка загрузки распределения длительности:', error);
+      setError(`Ошибка загрузки данных: ${error}`);
+    } finally {
+      if (!load.stale()) setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    loadData();
+  }, [reloadKey, metric, capSeconds]);
+
+  const renderDistribution = () => {
+    if (!report || report.count === 0) {
+      return <p className="text-sm text-gray-600 dark:text-gray-400">За выбранный период записей нет</p>;
+    }
+
+    const stats = [
+      { label: 'Mean', value: report.mean },
+      { label: 'Median', value: report.median },
+      { label: 'p90', value: report.p90 },
+      { label: 'p95', value: report.p95 },
+      { label: 'Max', value: report.max },
+    ];
+    const largest = Math.max(...report.bins.map(bin => bin.count), 1);
+
+    return (
+      <>
+        <div className="grid grid-cols-5 gap-3 mb-4">
+          {stats.map(stat => (
+            <div key={stat.label} className="bg-gray-100 dark:bg-dark-700 rounded-md p-3 text-center">
+              <div className="text-xs text-gray-600 dark:text-gray-400">{stat.label}</div>
+              <div className="text-lg font-semibold text-gray-900 dark:text-white">{formatDuration(stat.value)}</div>
+            </div>
+          ))}
+        </div>
+
+        <div className="space-y-1">
+          {report.bins.map(bin => (
+            <div key={bin.label} className="flex items-center text-xs">
+              <span className="w-24 text-gray-700 dark:text-gray-300">{bin.label}</span>
+              <div className="flex-1 bg-gray-100 dark:bg-dark-700 rounded h-4 mx-2">
+                <div className="bg-primary-600 h-4 rounded" style={{ width: `${(bin.count / largest) * 100}%` }} />
+              </div>
+              <span className="w-16 text-right text-gray-900 dark:text-white">{bin.count.toLocaleString()}</span>
+            </div>
+          ))}
+        </div>
+
+        <p className="text-xs text-gray-600 dark:text-gray-400 mt-3">
+          Records: {report.count.toLocaleString()}
+          {report.cap_seconds > 0 && `, trimmed above ${formatDuration(report.cap_seconds)}: ${report.trimmed.toLocaleString()}`}
+        </p>
+      </>
+    );
+  };
+
+  return (
+    <div className="bg-gray-50 dark:bg-dark-800 p-6 rounded-lg border border-gray-200 dark:border-dark-700 mb-6">
+      <div className="flex justify-between items-center mb-4">
+        <h2 className="text-xl font-semibold text-gray-900 dark:text-white">{metric.toUpperCase()} distribution</h2>
+        <div className="flex space-x-1">
+          {capOptions.map(option => (
+            <button
+              key={option.value}
+              onClick={() => setCapSeconds(option.value)}
+              disabled={loading}
+              className={clsx(
+                'px-3 py-1.5 text-sm rounded-md transition-colors',
+                capSeconds === option.value
+                  ? 'bg-primary-600 text-white'
+                  : 'bg-gray-100 dark:bg-dark-700 text-gray-700 dark:text-gray-300 hover:text-gray-900 dark:hover:text-white'
+              )}
+            >
+              {option.label}
+            </button>
+          ))}
+        </div>
+      </div>
+
+      {loading && (
+        <div className="flex items-center space-x-2 text-gray-600 dark:text-gray-400">
+          <Loader2 className="w-4 h-4 animate-spin" />
+          <span className="text-sm">Загрузка...</span>
+        </div>
+      )}
+
+      {error && <div className="text-red-600 dark:text-red-400 text-sm">{error}</div>}
+
+      {!loading && !error && renderDistribution()}
+    </div>
+  );
+};
+
+export default DurationDistributionPanel;
@@ -12,6 +12,8 @@ import {
 import { Bar } from 'react-chartjs-2';
 import { Calendar, Filter, Loader2, Download } from 'lucide-react';
 import { GetHourlyData } from '../../wailsjs/go/main/App';
+import { useRequestScope } from '../utils/requests';
+import { main } from '../../wailsjs/go/models';
 import { exportHourlyToExcel } from '../utils/excelExport';
 
 // Регистрируем компоненты Chart.js
@@ -53,6 +55,16 @@ interface HourlyData {
   hour_23: number;
 }
 
+// Строки матрицы backend (hours[0..23]) в формате таблицы hour_0..hour_23
+const toHourlyData = (matrix: main.HourlyMatrix | null): HourlyData[] =>
+  (matrix?.rows || []).map(row => {
+    const item: Record<string, number | string> = { date: row.date };
+    for (let hour = 0; hour < 24; hour++) {
+      item[`hour_${hour}`] = row.hours?.[hour] || 0;
+    }
+    return item as unknown as HourlyData;
+  });
+
 interface HourlyViewProps {
   queueName: string;
   startDate: string;
@@ -62,6 +74,15 @@ interface HourlyViewProps {
   onDataLoaded: () => void;
 }
 
+// Показатели ожидания отвеченных звонков для метрики ASA & wait
+const waitOptions = [
+  { value: 'asa', label: 'ASA' },
+  { value: 'wait_p50', label: 'p50' },
+  { value: 'wait_p90', label: 'p90' },
+  { value: 'wait_p95', label: 'p95' },
+  { value: 'max_wait', label: 'Max' },
+];
+
 const HourlyView: React.FC<HourlyViewProps> = ({ 
   queueName, 
   startDate, 
@@ -81,6 +102,11 @@ const HourlyView: React.FC<HourlyViewProps> = ({
     chats: HourlyData[];
     total: HourlyData[];
   }>({ calls: [], chats: [], total: [] });
+  const beginLoad = useRequestScope('hourly');
+
+  // ASA & wait: показатель ожидания выбирается внутри метрики
+  const [waitMetric, setWaitMetric] = useState('asa');
+  const metric = activeMetric === 'asa' ? waitMetric : activeMetric;
 
   // Автоматическая загрузка при изменении флага, очереди или дат
   useEffect(() => {
@@ -103,6 +129,13 @@ const HourlyView: React.FC<HourlyViewProps> = ({
     }
   }, [activeMetric]);
 
+  // Смена показателя ожидания перечитывает данные
+  useEffect(() => {
+    if (activeMetric === 'asa' && tableData.length > 0) {
+      loadData();
+    }
+  }, [waitMetric]);
+
   // Обновление данных графика при смене активной метрики
   useEffect(() => {
     if (activeMetric === 'total') {
@@ -139,7 +172,7 @@ const HourlyView: React.FC<HourlyViewProps> = ({
       
       return {
         date: hour,
-        name: getMetricLabel(activeMetric),
+        name: getMetricLabel(metric),
         value: average
       };
     });
@@ -206,6 +239,7 @@ const HourlyView: React.FC<HourlyViewProps> = ({
 
   // Загрузка данных из API
   const loadData = async () => {
+    const load = beginLoad();
     setLoading(true);
     setError(null);
 
@@ -213,36 +247,35 @@ const HourlyView: React.FC<HourlyViewProps> = ({
       if (activeMetric === 'total') {
         // Для метрики "total" загружаем calls, chats и total
         const [callsResponse, chatsResponse, totalResponse] = await Promise.all([
-          GetHourlyData(startDate, endDate, queueName, 'calls'),
-          GetHourlyData(startDate, endDate, queueName, 'chats'),
-          GetHourlyData(startDate, endDate, queueName, 'total')
+          GetHourlyData(load.id(), startDate, endDate, queueName, 'calls'),
+          GetHourlyData(load.id(), startDate, endDate, queueName, 'chats'),
+          GetHourlyData(load.id(), startDate, endDate, queueName, 'total')
         ]);
+        if (load.stale()) return;
 
         setTotalData({
-          calls: callsResponse?.data || [],
-          chats: chatsResponse?.data || [],
-          total: totalResponse?.data || []
+          calls: toHourlyData(callsResponse),
+          chats: toHourlyData(chatsResponse),
+          total: toHourlyData(totalResponse)
         });
         setTableData([]); // Очищаем обычные данные
       } else {
         // Для обычных метрик загружаем только одну метрику
-        const response = await GetHourlyData(startDate, endDate, queueName, activeMetric);
+        const response = await GetHourlyData(load.id(), startDate, endDate, queueName, metric);
+        if (load.stale()) return;
         
-        if (response && response.data) {
-          setTableData(response.data as HourlyData[]);
-        } else {
-          setTableData([]);
-        }
+        setTableData(toHourlyData(response));
         setTotalData({ calls: [], chats: [], total: [] }); // Очищаем total данные
       }
       
       onDataLoaded();
     } catch (err) {
+      if (load.stale()) return; // запрос отменен новой загрузкой
       console.error('Ошибка загрузки почасовых данных:', err);
       setError(`Ошибка загрузки данных: ${err}`);
       onDataLoaded();
     } finally {
-      setLoading(false);
+      if (!load.stale()) setLoading(false);
     }
   };
 
@@ -261,10 +294,17 @@ const HourlyView: React.FC<HourlyViewProps> = ({
       case 'aht': return 'AHT (sec)';
       case 'sl': return 'SL (%)';
       case 'abandoned': return 'Abandoned';
+      case 'abandon_rate': return 'Abandon rate (%)';
+      case 'asa': return 'ASA (sec)';
+      case 'max_wait': return 'Max wait (sec)';
+      case 'wait_p50': return 'Wait p50 (sec)';
+      case 'wait_p90': return 'Wait p90 (sec)';
+      case 'wait_p95': return 'Wait p95 (sec)';
       case 'chats': return 'Chats';
       case 'frt': return 'FRT (sec)';
       case 'rt': return 'RT (sec)';
       case 'agents': return 'Agents';
+      case 'occupancy': return 'Occupancy (%)';
       case 'total': return 'Total';
       default: return metric;
     }
@@ -277,10 +317,17 @@ const HourlyView: React.FC<HourlyViewProps> = ({
       case 'aht': return '#8B5CF6';
       case 'sl': return '#10B981';
       case 'abandoned': return '#EF4444';
+      case 'abandon_rate': return '#F43F5E';
+      case 'asa':
+      case 'max_wait':
+      case 'wait_p50':
+      case 'wait_p90':
+      case 'wait_p95': return '#F59E0B';
       case 'chats': return '#8B5CF6';
       case 'frt': return '#EC4899';
       case 'rt': return '#6366F1';
       case 'agents': return '#F97316';
+      case 'occupancy': return '#14B8A6';
       case 'total': return '#10B981';
       default: return '#3B82F6';
     }
@@ -345,7 +392,7 @@ const HourlyView: React.FC<HourlyViewProps> = ({
           {
             label: chartData[0]?.name || 'Value',
             data: chartData.map(item => Math.round(item.value)),
-            backgroundColor: getMetricColor(activeMetric),
+            backgroundColor: getMetricColor(metric),
             borderColor: '#0284C7',
             borderWidth: 1,
             borderRadius: 4,
@@ -709,7 +756,7 @@ const HourlyView: React.FC<HourlyViewProps> = ({
       }
     } else {
       if (tableData.length > 0) {
-        exportHourlyToExcel(tableData, activeMetric, queueName, startDate, endDate);
+        exportHourlyToExcel(tableData, metric, queueName, startDate, endDate);
       }
     }
   };
@@ -751,9 +798,22 @@ const HourlyView: React.FC<HourlyViewProps> = ({
         <div className="bg-dark-800 p-6 rounded-lg border border-dark-700 mb-6">
           <div className="flex justify-between items-center mb-4">
             <h2 className="text-xl font-semibold text-white">
-              {activeMetric.toUpperCase()}
+              {activeMetric === 'asa' ? getMetricLabel(metric) : activeMetric.toUpperCase()}
             </h2>
             <div className="flex space-x-2">
+              {activeMetric === 'asa' && waitOptions.map(option => (
+                <button
+                  key={option.value}
+                  onClick={() => setWaitMetric(option.value)}
+                  className={`px-3 py-1.5 text-sm rounded-md transition-colors ${
+                    waitMetric === option.value
+                      ? 'bg-primary-600 text-white'
+                      : 'bg-dark-700 text-gray-300 hover:text-white'
+                  }`}
+                >
+                  {option.label}
+                </button>
+              ))}
               <button
                 onClick={handleExport}
                 className="flex items-center space-x-1 px-3 py-1.5 bg-green-600 text-white text-sm rounded-md hover:bg-green-700 transition-colors"
@@ -791,8 +851,10 @@ const HourlyView: React.FC<HourlyViewProps> = ({
                 </div>
               );
             } else {
-              const display = activeMetric === 'aht' || activeMetric === 'frt' || activeMetric === 'rt' || activeMetric === 'sl'
-                ? `Average for period: ${totals.average?.toFixed(2)} ${activeMetric === 'sl' ? '%' : activeMetric.includes('ht') || activeMetric.includes('rt') ? 'sec' : ''}`
+              const percent = ['sl', 'abandon_rate', 'occupancy'].includes(activeMetric);
+              const seconds = ['aht', 'frt', 'rt', 'asa'].includes(activeMetric);
+              const display = seconds || percent
+                ? `Average for period: ${totals.average?.toFixed(2)} ${percent ? '%' : 'sec'}`
                 : `Total for period: ${totals.total?.toLocaleString()}`;
 
               return (
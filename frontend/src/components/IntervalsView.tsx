@@ -0,0 +1,179 @@
+import React, { useState, useEffect } from 'react';
+import { Loader2 } from 'lucide-react';
+import clsx from 'clsx';
+import { GetIntervalData } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { formatDuration } from '../utils/duration';
+import { useRequestScope } from '../utils/requests';
+
+interface IntervalsViewProps {
+  queueName: string;
+  startDate: string;
+  endDate: string;
+  activeMetric: string;
+  shouldLoadData: boolean;
+  onDataLoaded: () => void;
+}
+
+// Ширина интервала в минутах
+const intervalOptions = [15, 30, 60];
+
+// Метрики-длительности показываются в ЧЧ:ММ:СС
+const durationMetrics = ['aht', 'frt', 'rt', 'asa'];
+
+const IntervalsView: React.FC<IntervalsViewProps> = ({
+  queueName,
+  startDate,
+  endDate,
+  activeMetric,
+  shouldLoadData,
+  onDataLoaded
+}) => {
+  const [intervalMinutes, setIntervalMinutes] = useState(30);
+  const [matrix, setMatrix] = useState<main.IntervalMatrix | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string>('');
+  const beginLoad = useRequestScope('intervals');
+
+  // detailed_daily есть только в Daily, для интервалов показываем звонки
+  const metric = activeMetric === 'detailed_daily' ? 'calls' : activeMetric;
+
+  // Загрузка метрики по интервалам
+  const loadData = async () => {
+    if (!startDate || !endDate) return;
+
+    const load = beginLoad();
+    setLoading(true);
+    setError('');
+
+    try {
+      const result = await GetIntervalData(load.id(), startDate, endDate, queueName, metric, intervalMinutes);
+      if (load.stale()) return;
+      setMatrix(result);
+      onDataLoaded();
+    } catch (error) {
+      if (load.stale()) return; // запрос отменен новой загрузкой
+      console.error('Ошибка загрузки интервалов:', error);
+      setError(`Ошибка загрузки данных: ${error}`);
+      onDataLoaded();
+    } finally {
+      if (!load.stale()) setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    if (shouldLoadData) {
+      loadData();
+    }
+  }, [shouldLoadData, queueName, startDate, endDate, metric]);
+
+  // Смена ширины интервала сразу перезагружает данные
+  useEffect(() => {
+    if (matrix) {
+      loadData();
+    }
+  }, [intervalMinutes]);
+
+  const formatValue = (value: number) => {
+    if (durationMetrics.includes(metric)) return formatDuration(value);
+    if (metric === 'sl' || metric === 'abandon_rate' || metric === 'occupancy') return `${value.toFixed(2)}%`;
+    return Number.isInteger(value) ? value.toString() : value.toFixed(2);
+  };
+
+  const renderTable = () => {
+    if (!matrix || matrix.rows.length === 0) {
+      return (
+        <div className="bg-dark-800 p-6 rounded-lg border border-dark-700 text-center">
+          <p className="text-gray-400">За выбранный период данных нет</p>
+        </div>
+      );
+    }
+
+    const cell = 'px-2 py-2 text-center text-xs border-r border-dark-600 whitespace-nowrap';
+
+    return (
+      <div className="bg-dark-800 rounded-lg border border-dark-700 overflow-hidden">
+        <div className="overflow-x-auto">
+          <table className="w-full">
+            <thead>
+              <tr className="bg-dark-700">
+                <th className="px-3 py-2 text-left text-xs font-medium text-white border-r border-dark-600 sticky left-0 bg-dark-700">Date</th>
+                {matrix.labels.map((label) => (
+                  <th key={label} className={clsx(cell, 'font-medium text-white')}>{label}</th>
+                ))}
+              </tr>
+            </thead>
+            <tbody className="divide-y divide-dark-600">
+              {matrix.rows.map((row) => (
+                <tr key={row.date}>
+                  <td className="px-3 py-2 text-left text-xs text-white border-r border-dark-600 sticky left-0 bg-dark-800">{row.date}</td>
+                  {row.values.map((value, i) => (
+                    <td key={i} className={clsx(cell, value ? 'text-white' : 'text-gray-500')}>{formatValue(value)}</td>
+                  ))}
+                </tr>
+              ))}
+              <tr className="bg-dark-700">
+                <td className="px-3 py-2 text-left text-xs font-semibold text-white border-r border-dark-600 sticky left-0 bg-dark-700">Average day</td>
+                {matrix.average.map((value, i) => (
+                  <td key={i} className={clsx(cell, 'font-semibold text-white')}>{formatValue(value)}</td>
+                ))}
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    );
+  };
+
+  return (
+    <div className="flex-1 p-8 bg-white dark:bg-dark-900 overflow-y-auto h-screen transition-colors duration-300">
+      <div className="max-w-full">
+        {/* Заголовок */}
+        <div className="bg-dark-800 p-6 rounded-lg border border-dark-700 mb-6">
+          <div className="flex justify-between items-center">
+            <h2 className="text-xl font-semibold text-white">Intervals: {metric.toUpperCase()}</h2>
+            <div className="flex space-x-1">
+              {intervalOptions.map((minutes) => (
+                <button
+                  key={minutes}
+                  onClick={() => setIntervalMinutes(minutes)}
+                  disabled={loading}
+                  className={clsx(
+                    'px-3 py-1.5 text-sm rounded-md transition-colors',
+                    intervalMinutes === minutes
+                      ? 'bg-primary-600 text-white'
+                      : 'bg-dark-700 text-gray-300 hover:text-white'
+                  )}
+                >
+                  {minutes} мин
+                </button>
+              ))}
+            </div>
+          </div>
+          {matrix && (
+            <p className="text-sm text-gray-400 mt-2">
+              Время интервалов: {matrix.timezone}
+            </p>
+          )}
+
+          {loading && (
+            <div className="flex items-center space-x-2 text-gray-400 mt-3">
+              <Loader2 className="w-4 h-4 animate-spin" />
+              <span className="text-sm">Загрузка...</span>
+            </div>
+          )}
+
+          {error && (
+            <div className="mt-3 text-red-400 text-sm">
+              {error}
+            </div>
+          )}
+        </div>
+
+        {!loading && renderTable()}
+      </div>
+    </div>
+  );
+};
+
+export default IntervalsView;
@@ -1,23 +1,31 @@
 import React, { useState, useEffect } from 'react';
 import { Download, Loader2 } from 'lucide-react';
 import { GetMonthlyData } from '../../wailsjs/go/main/App';
+import { useRequestScope } from '../utils/requests';
 import { exportMonthlyDataToExcel } from '../utils/excelExport';
+import { formatDuration } from '../utils/duration';
 
 // Интерфейсы для данных
 interface MonthlyCallData {
   day: number;
   total_calls: number;
-  avg_call_duration: string; // в формате HH:MM:SS
+  avg_call_duration: number; // в секундах
   sl: number;
   total_abandoned: number;
+  abandon_rate: number; // % от поступивших без коротких брошенных
+  asa: number; // ожидание отвеченных звонков, сек
+  max_wait: number;
+  wait_p50: number;
+  wait_p90: number;
+  wait_p95: number;
   distinct_agents: number;
 }
 
 interface MonthlyChatData {
   day: number;
   total_chats: number;
-  avg_chat_frt: string; // в формате HH:MM:SS
-  resolution_time_avg: string; // в формате HH:MM:SS
+  avg_chat_frt: number; // в секундах
+  resolution_time_avg: number; // в секундах
 }
 
 interface MonthlyViewProps {
@@ -46,6 +54,7 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
   const [chatData, setChatData] = useState<MonthlyChatData[]>([]);
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState<string>('');
+  const beginLoad = useRequestScope('monthly');
 
   // Функция экспорта в Excel
   const handleExportToExcel = () => {
@@ -66,6 +75,7 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
   const loadData = async () => {
     if (!selectedMonth) return;
 
+    const load = beginLoad();
     setLoading(true);
     setError('');
 
@@ -78,7 +88,8 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
 
       console.log('Загружаем месячные данные для:', { startDate, endDate, queueName });
 
-      const response = await GetMonthlyData(startDate, endDate, queueName);
+      const response = await GetMonthlyData(load.id(), startDate, endDate, queueName);
+      if (load.stale()) return;
       
       if (response && typeof response === 'object') {
         // Обрабатываем данные звонков
@@ -94,10 +105,11 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
 
       onDataLoaded();
     } catch (error) {
+      if (load.stale()) return; // запрос отменен новой загрузкой
       console.error('Ошибка загрузки месячных данных:', error);
       setError(`Ошибка загрузки данных: ${error}`);
     } finally {
-      setLoading(false);
+      if (!load.stale()) setLoading(false);
     }
   };
 
@@ -145,6 +157,15 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
       chatDataMap.set(item.day, item);
     });
 
+    // Строки ожидания отвеченных звонков, сек
+    const waitRows: { label: string; value: (row: MonthlyCallData) => number }[] = [
+      { label: 'ASA', value: row => row.asa },
+      { label: 'Max wait', value: row => row.max_wait },
+      { label: 'Wait p50', value: row => row.wait_p50 },
+      { label: 'Wait p90', value: row => row.wait_p90 },
+      { label: 'Wait p95', value: row => row.wait_p95 },
+    ];
+
     // Получаем название месяца и год для заголовка
     const monthNames = [
       'Январь', 'Февраль', 'Март', 'Апрель', 'Май', 'Июнь',
@@ -205,7 +226,7 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
                   const dayData = callDataMap.get(day);
                   return (
                     <td key={day} className="px-2 py-3 text-center text-white border-r border-dark-600 text-sm">
-                      {dayData ? dayData.avg_call_duration || '00:00:00' : '00:00:00'}
+                      {formatDuration(dayData ? dayData.avg_call_duration : 0)}
                     </td>
                   );
                 })}
@@ -240,6 +261,38 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
                   );
                 })}
               </tr>
+
+              {/* Процент брошенных */}
+              <tr>
+                <td className="px-4 py-3 text-left font-medium text-red-400 border-r border-dark-600">
+                  Abandon rate
+                </td>
+                {allDays.map(day => {
+                  const dayData = callDataMap.get(day);
+                  return (
+                    <td key={day} className="px-2 py-3 text-center text-white border-r border-dark-600 text-sm">
+                      {dayData ? `${dayData.abandon_rate.toFixed(1)}%` : '0.0%'}
+                    </td>
+                  );
+                })}
+              </tr>
+
+              {/* Ожидание отвеченных звонков */}
+              {waitRows.map(({ label, value }) => (
+                <tr key={label}>
+                  <td className="px-4 py-3 text-left font-medium text-orange-400 border-r border-dark-600">
+                    {label}
+                  </td>
+                  {allDays.map(day => {
+                    const dayData = callDataMap.get(day);
+                    return (
+                      <td key={day} className="px-2 py-3 text-center text-white border-r border-dark-600 text-sm">
+                        {formatDuration(dayData ? value(dayData) : 0)}
+                      </td>
+                    );
+                  })}
+                </tr>
+              ))}
               
               {/* Чаты */}
               <tr>
@@ -265,7 +318,7 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
                   const dayData = chatDataMap.get(day);
                   return (
                     <td key={day} className="px-2 py-3 text-center text-white border-r border-dark-600 text-sm">
-                      {dayData ? dayData.avg_chat_frt || '00:00:00' : '00:00:00'}
+                      {formatDuration(dayData ? dayData.avg_chat_frt : 0)}
                     </td>
                   );
                 })}
@@ -280,7 +333,7 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
                   const dayData = chatDataMap.get(day);
                   return (
                     <td key={day} className="px-2 py-3 text-center text-white border-r border-dark-600 text-sm">
-                      {dayData ? dayData.resolution_time_avg || '00:00:00' : '00:00:00'}
+                      {formatDuration(dayData ? dayData.resolution_time_avg : 0)}
                     </td>
                   );
                 })}
@@ -320,45 +373,16 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
     
     // Средние значения
     const avgAht = callData.length > 0 ? 
-      callData.reduce((sum, item, _, arr) => {
-        // Конвертируем время в секунды для расчета среднего
-        const timeToSeconds = (time: string) => {
-          if (!time) return 0;
-          const parts = time.split(':');
-          return parseInt(parts[0]) * 3600 + parseInt(parts[1]) * 60 + parseInt(parts[2]);
-        };
-        return sum + timeToSeconds(item.avg_call_duration) / arr.length;
-      }, 0) : 0;
+      callData.reduce((sum, item, _, arr) => sum + item.avg_call_duration / arr.length, 0) : 0;
 
     const avgSl = callData.length > 0 ? 
       callData.reduce((sum, item, _, arr) => sum + item.sl / arr.length, 0) : 0;
 
     const avgFrt = chatData.length > 0 ? 
-      chatData.reduce((sum, item, _, arr) => {
-        const timeToSeconds = (time: string) => {
-          if (!time) return 0;
-          const parts = time.split(':');
-          return parseInt(parts[0]) * 3600 + parseInt(parts[1]) * 60 + parseInt(parts[2]);
-        };
-        return sum + timeToSeconds(item.avg_chat_frt) / arr.length;
-      }, 0) : 0;
+      chatData.reduce((sum, item, _, arr) => sum + item.avg_chat_frt / arr.length, 0) : 0;
 
     const avgRt = chatData.length > 0 ? 
-      chatData.reduce((sum, item, _, arr) => {
-        const timeToSeconds = (time: string) => {
-          if (!time) return 0;
-          const parts = time.split(':');
-          return parseInt(parts[0]) * 3600 + parseInt(parts[1]) * 60 + parseInt(parts[2]);
-        };
-        return sum + timeToSeconds(item.resolution_time_avg) / arr.length;
-      }, 0) : 0;
-
-    const formatSecondsToTime = (seconds: number) => {
-      const h = Math.floor(seconds / 3600);
-      const m = Math.floor((seconds % 3600) / 60);
-      const s = Math.floor(seconds % 60);
-      return `${h.toString().padStart(2, '0')}:${m.toString().padStart(2, '0')}:${s.toString().padStart(2, '0')}`;
-    };
+      chatData.reduce((sum, item, _, arr) => sum + item.resolution_time_avg / arr.length, 0) : 0;
 
     return (
       <div className="bg-dark-800 rounded-lg border border-dark-700 overflow-hidden">
@@ -391,7 +415,7 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
                   AHT (Min)
                 </td>
                 <td className="px-4 py-3 text-center text-white">
-                  {formatSecondsToTime(avgAht)}
+                  {formatDuration(avgAht)}
                 </td>
               </tr>
               <tr>
@@ -431,7 +455,7 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
                   FRT (min)
                 </td>
                 <td className="px-4 py-3 text-center text-white">
-                  {formatSecondsToTime(avgFrt)}
+                  {formatDuration(avgFrt)}
                 </td>
               </tr>
               <tr>
@@ -439,7 +463,7 @@ const MonthlyView: React.FC<MonthlyViewProps> = ({
                   RT (min)
                 </td>
                 <td className="px-4 py-3 text-center text-white">
-                  {formatSecondsToTime(avgRt)}
+                  {formatDuration(avgRt)}
                 </td>
               </tr>
               <tr>
@@ -0,0 +1,157 @@
+import React, { useState, useEffect } from 'react';
+import { AlertTriangle, Loader2 } from 'lucide-react';
+import clsx from 'clsx';
+import { GetQueueStats } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { useRequestScope } from '../utils/requests';
+
+interface QueuesViewProps {
+  startDate: string;
+  endDate: string;
+  shouldLoadData: boolean;
+  onDataLoaded: () => void;
+}
+
+// Подписи источников данных
+const sourceLabels: Record<string, string> = {
+  call_report: 'call_report',
+  chat_report: 'chat_report',
+  request: 'MongoDB request',
+};
+
+const QueuesView: React.FC<QueuesViewProps> = ({
+  startDate,
+  endDate,
+  shouldLoadData,
+  onDataLoaded
+}) => {
+  const [stats, setStats] = useState<main.QueueStats | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string>('');
+  const beginLoad = useRequestScope('queues');
+
+  // Загрузка очередей за период
+  const loadData = async () => {
+    if (!startDate || !endDate) return;
+
+    const load = beginLoad();
+    setLoading(true);
+    setError('');
+
+    try {
+      const result = await GetQueueStats(load.id(), startDate, endDate);
+      if (load.stale()) return;
+      setStats(result);
+      onDataLoaded();
+    } catch (error) {
+      if (load.stale()) return; // запрос отменен новой загрузкой
+      console.error('Ошибка загрузки очередей:', error);
+      setError(`Ошибка загрузки данных: ${error}`);
+    } finally {
+      if (!load.stale()) setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    if (shouldLoadData) {
+      loadData();
+    }
+  }, [startDate, endDate, shouldLoadData]);
+
+  const renderTable = () => {
+    if (!stats || stats.queues.length === 0) {
+      return (
+        <div className="bg-dark-800 p-6 rounded-lg border border-dark-700 text-center">
+          <p className="text-gray-400">За выбранный период очередей не найдено</p>
+        </div>
+      );
+    }
+
+    return (
+      <div className="bg-dark-800 rounded-lg border border-dark-700 overflow-hidden">
+        <div className="overflow-x-auto">
+          <table className="w-full">
+            <thead>
+              <tr className="bg-dark-700">
+                <th className="px-4 py-3 text-left font-medium text-white border-r border-dark-600">Queue</th>
+                <th className="px-4 py-3 text-center font-medium text-white border-r border-dark-600">Calls</th>
+                <th className="px-4 py-3 text-center font-medium text-white border-r border-dark-600">Chats</th>
+                <th className="px-4 py-3 text-center font-medium text-white border-r border-dark-600">Requests</th>
+                <th className="px-4 py-3 text-center font-medium text-white border-r border-dark-600">First seen</th>
+                <th className="px-4 py-3 text-center font-medium text-white border-r border-dark-600">Last seen</th>
+                <th className="px-4 py-3 text-left font-medium text-white">Groups</th>
+              </tr>
+            </thead>
+            <tbody className="divide-y divide-dark-600">
+              {stats.queues.map((queue) => (
+                <tr key={queue.name} className={clsx(queue.unassigned && 'bg-yellow-900/20')}>
+                  <td className="px-4 py-3 text-left text-white border-r border-dark-600">
+                    <div className="flex items-center space-x-2">
+                      {queue.unassigned && <AlertTriangle className="w-4 h-4 text-yellow-400" />}
+                      <span>{queue.name || '(без очереди)'}</span>
+                    </div>
+                  </td>
+                  <td className="px-4 py-3 text-center text-white border-r border-dark-600">{queue.calls.toLocaleString()}</td>
+                  <td className="px-4 py-3 text-center text-white border-r border-dark-600">{queue.chats.toLocaleString()}</td>
+                  <td className="px-4 py-3 text-center text-white border-r border-dark-600">{queue.requests.toLocaleString()}</td>
+                  <td className="px-4 py-3 text-center text-white border-r border-dark-600">{queue.first_seen}</td>
+                  <td className="px-4 py-3 text-center text-white border-r border-dark-600">{queue.last_seen}</td>
+                  <td className="px-4 py-3 text-left text-sm">
+                    {queue.groups.length > 0 ? (
+                      <span className="text-gray-300">{queue.groups.join(', ')}</span>
+                    ) : (
+                      <span className="text-yellow-400">не входит ни в одну группу</span>
+                    )}
+                  </td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+        </div>
+      </div>
+    );
+  };
+
+  return (
+    <div className="flex-1 p-8 bg-white dark:bg-dark-900 overflow-y-auto h-screen transition-colors duration-300">
+      <div className="max-w-full">
+        {/* Заголовок */}
+        <div className="bg-dark-800 p-6 rounded-lg border border-dark-700 mb-6">
+          <h2 className="text-xl font-semibold text-white">Очереди и каналы</h2>
+          {stats && (
+            <p className="text-sm text-gray-400 mt-2">
+              Источники: {stats.sources.map((source) => sourceLabels[source] || source).join(', ')}
+            </p>
+          )}
+
+          {stats && stats.unassigned > 0 && (
+            <div className="flex items-center space-x-2 mt-3 text-yellow-400 text-sm">
+              <AlertTriangle className="w-4 h-4" />
+              <span>
+                Очередей вне групп: {stats.unassigned}. Их данные не попадают на дашборд —
+                добавьте их в queue_groups профиля.
+              </span>
+            </div>
+          )}
+
+          {loading && (
+            <div className="flex items-center space-x-2 text-gray-400 mt-3">
+              <Loader2 className="w-4 h-4 animate-spin" />
+              <span className="text-sm">Загрузка...</span>
+            </div>
+          )}
+
+          {error && (
+            <div className="mt-3 text-red-400 text-sm">
+              {error}
+            </div>
+          )}
+        </div>
+
+        {!loading && renderTable()}
+      </div>
+    </div>
+  );
+};
+
+export default QueuesView;
@@ -0,0 +1,130 @@
+import React, { useState, useEffect } from 'react';
+import { Loader2 } from 'lucide-react';
+import clsx from 'clsx';
+import { GetServiceLevel } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { useRequestScope } from '../utils/requests';
+
+interface ServiceLevelPanelProps {
+  queueName: string;
+  startDate: string;
+  endDate: string;
+  reloadKey: unknown; // новые данные Daily — перечитать SL
+}
+
+// SL звонков и чатов по порогам 10/20/30/60 с и порогу цели группы очередей
+const ServiceLevelPanel: React.FC<ServiceLevelPanelProps> = ({ queueName, startDate, endDate, reloadKey }) => {
+  const [report, setReport] = useState<main.ServiceLevelReport | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string>('');
+  const beginLoad = useRequestScope('service-level');
+
+  const loadData = async () => {
+    if (!startDate || !endDate) return;
+
+    const load = beginLoad();
+    setLoading(true);
+    setError('');
+
+    try {
+      const result = await GetServiceLevel(load.id(), startDate, endDate, queueName);
+      if (load.stale()) return;
+      setReport(result);
+    } catch (error) {
+      if (load.stale()) return; // запрос отменен новой загрузкой
+      console.error('Ошибка загрузки SL по порогам:', error);
+      setError(`Ошибка загрузки данных: ${error}`);
+    } finally {
+      if (!load.stale()) setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    loadData();
+  }, [reloadKey]);
+
+  const renderSeries = (title: string, basis: string, series: main.ServiceLevelSeries) => {
+    if (!series.days || series.days.length === 0) {
+      return null;
+    }
+
+    const target = series.target;
+    const cell = 'px-3 py-2 text-center text-xs border-r border-gray-200 dark:border-dark-600 whitespace-nowrap';
+    const rows = [...series.days, series.total];
+
+    return (
+      <div className="mb-4">
+        <h3 className="text-sm font-semibold text-gray-900 dark:text-white mb-2">
+          {title}: {basis}, target {target.target_percent}% in {target.threshold_seconds}s
+        </h3>
+        <div className="overflow-x-auto">
+          <table className="w-full">
+            <thead>
+              <tr className="bg-gray-100 dark:bg-dark-700">
+                <th className={clsx(cell, 'text-left font-medium text-gray-900 dark:text-white')}>Date</th>
+                <th className={clsx(cell, 'font-medium text-gray-900 dark:text-white')}>Total</th>
+                {series.total.thresholds.map(threshold => (
+                  <th
+                    key={threshold.seconds}
+                    className={clsx(
+                      cell,
+                      'font-medium text-gray-900 dark:text-white',
+                      threshold.seconds === target.threshold_seconds && 'bg-primary-600/20'
+                    )}
+                  >
+                    ≤ {threshold.seconds}s
+                  </th>
+                ))}
+              </tr>
+            </thead>
+            <tbody className="divide-y divide-gray-200 dark:divide-dark-600">
+              {rows.map(row => (
+                <tr key={row.date || 'total'} className={clsx(!row.date && 'font-semibold bg-gray-100 dark:bg-dark-700')}>
+                  <td className={clsx(cell, 'text-left text-gray-900 dark:text-white')}>{row.date || 'Period'}</td>
+                  <td className={clsx(cell, 'text-gray-900 dark:text-white')}>{row.total}</td>
+                  {row.thresholds.map(threshold => (
+                    <td
+                      key={threshold.seconds}
+                      className={clsx(
+                        cell,
+                        threshold.seconds !== target.threshold_seconds
+                          ? 'text-gray-700 dark:text-gray-300'
+                          : row.met ? 'text-green-600 dark:text-green-400' : 'text-red-600 dark:text-red-400'
+                      )}
+                    >
+                      {threshold.sl.toFixed(2)}%
+                    </td>
+                  ))}
+                </tr>
+              ))}
+            </tbody>
+          </table>
+        </div>
+      </div>
+    );
+  };
+
+  return (
+    <div className="bg-gray-50 dark:bg-dark-800 p-6 rounded-lg border border-gray-200 dark:border-dark-700 mb-6">
+      <h2 className="text-xl font-semibold text-gray-900 dark:text-white mb-4">SL by threshold</h2>
+
+      {loading && (
+        <div className="flex items-center space-x-2 text-gray-600 dark:text-gray-400">
+          <Loader2 className="w-4 h-4 animate-spin" />
+          <span className="text-sm">Загрузка...</span>
+        </div>
+      )}
+
+      {error && <div className="text-red-600 dark:text-red-400 text-sm">{error}</div>}
+
+      {!loading && report && (
+        <>
+          {renderSeries('Calls', 'queue wait', report.calls)}
+          {renderSeries('Chats', 'first response time', report.chats)}
+        </>
+      )}
+    </div>
+  );
+};
+
+export default ServiceLevelPanel;
@@ -1,4 +1,4 @@
-import React, { useState, useEffect } from 'react';
+import React, { useState, useEffect, useRef } from 'react';
 import { 
   Database, 
   Calendar,
@@ -11,15 +11,19 @@ import {
   TrendingUp,
   Loader2,
   Download,
-  FileSpreadsheet
+  FileSpreadsheet,
+  Settings
 } from 'lucide-react';
 import clsx from 'clsx';
-import { GetDatabaseStats } from '../../wailsjs/go/main/App';
+import { GetConnectionStatus, GetQueueGroups, ListProfiles, SwitchProfile } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
+import ConnectionSettings from './ConnectionSettings';
 
 // Типы для состояния
-type QueueFilter = 'all' | 'm10' | 'aml';
-type DashboardView = 'daily' | 'hourly' | 'monthly' | 'classifiers' | 'online';
-type StandardMetric = 'calls' | 'aht' | 'sl' | 'chats' | 'frt' | 'rt' | 'abandoned' | 'total' | 'detailed_daily';
+type QueueFilter = string;
+type DashboardView = 'daily' | 'hourly' | 'intervals' | 'monthly' | 'agents' | 'classifiers' | 'queues' | 'online';
+type StandardMetric = 'calls' | 'aht' | 'sl' | 'chats' | 'frt' | 'rt' | 'abandoned' | 'abandon_rate' | 'asa' | 'occupancy' | 'total' | 'detailed_daily';
 type ClassifierMetric = 'call' | 'chat' | 'overall' | 'topics' | 'subtopics_daily';
 
 // Получение текущей даты в формате YYYY-MM-DD
@@ -77,20 +81,25 @@ const Sidebar: React.FC<SidebarProps> = ({
 }) => {
   const [dbStatus, setDbStatus] = useState({
     mysql: 'Проверка...',
-    mongodb: 'Проверка...'
+    mongodb: 'Проверка...',
+    mysqlError: '',
+    mongodbError: ''
   });
+  const [isSettingsOpen, setIsSettingsOpen] = useState(false);
+  const [profiles, setProfiles] = useState<main.ProfileInfo[]>([]);
+  const [activeProfile, setActiveProfile] = useState('');
+  const [isSwitching, setIsSwitching] = useState(false);
 
-  const queueFilters = [
-    { id: 'all', label: 'All queues' },
-    { id: 'm10', label: 'm10' },
-    { id: 'aml', label: 'AML' },
-  ];
+  const [queueGroups, setQueueGroups] = useState<main.QueueGroup[]>([]);
 
   const dashboardViews = [
     { id: 'daily', label: 'Daily' },
     { id: 'hourly', label: 'Hourly' },
+    { id: 'intervals', label: 'Intervals' },
     { id: 'monthly', label: 'Monthly' },
+    { id: 'agents', label: 'Agents' },
     { id: 'classifiers', label: 'Classifiers' },
+    { id: 'queues', label: 'Queues' },
     { id: 'online', label: 'Online' },
   ];
 
@@ -102,6 +111,9 @@ const Sidebar: React.FC<SidebarProps> = ({
     { id: 'frt', label: 'FRT' },
     { id: 'rt', label: 'RT' },
     { id: 'abandoned', label: 'Abandoned' },
+    { id: 'abandon_rate', label: 'Abandon rate' },
+    { id: 'asa', label: 'ASA & wait' },
+    { id: 'occupancy', label: 'Occupancy' },
     { id: 'total', label: 'Total' },
     { id: 'detailed_daily', label: 'Detailed daily' },
   ];
@@ -160,32 +172,73 @@ const Sidebar: React.FC<SidebarProps> = ({
 
   const availableMonths = generateMonths();
 
-  // Функция проверки статуса подключений
+  // Применение состояния соединений, полученного от супервизора
+  const applyStatus = (status: main.ConnectionStatus) => {
+    setDbStatus({
+      mysql: status.mysql.state || 'Не подключено',
+      mongodb: status.mongodb.state || 'Не подключено',
+      mysqlError: status.mysql.error || '',
+      mongodbError: status.mongodb.error || ''
+    });
+    setActiveProfile(status.profile || '');
+  };
+
+  // Актуальный фильтр для обработчика события db:profile
+  const activeQueueRef = useRef(activeQueue);
+  activeQueueRef.current = activeQueue;
+
+  // Функция загрузки статуса подключений, списка профилей и групп очередей
   const checkDatabaseStatus = async () => {
     try {
-      const stats = await GetDatabaseStats();
-      setDbStatus({
-        mysql: stats.mysql || 'Не подключено',
-        mongodb: stats.mongodb || 'Не подключено'
-      });
+      applyStatus(await GetConnectionStatus());
+      setProfiles(await ListProfiles());
+      const groups = await GetQueueGroups();
+      setQueueGroups(groups);
+      // Группы очередей задаются профилем — сбрасываем фильтр, если выбранной группы в нем нет
+      if (groups.length > 0 && !groups.some((group) => group.id.toLowerCase() === activeQueueRef.current.toLowerCase())) {
+        setActiveQueue(groups[0].id);
+      }
     } catch (error) {
       console.error('Ошибка проверки статуса БД:', error);
       setDbStatus({
         mysql: 'Ошибка',
-        mongodb: 'Ошибка'
+        mongodb: 'Ошибка',
+        mysqlError: String(error),
+        mongodbError: String(error)
       });
     }
   };
 
-  // Проверяем статус при загрузке и каждые 30 секунд
+  // Загружаем статус при старте, дальше супервизор присылает изменения событием db:status
   useEffect(() => {
     checkDatabaseStatus();
-    const interval = setInterval(checkDatabaseStatus, 30000);
-    return () => clearInterval(interval);
+    const offStatus = EventsOn('db:status', applyStatus);
+    const offProfile = EventsOn('db:profile', checkDatabaseStatus);
+    return () => {
+      offStatus();
+      offProfile();
+    };
   }, []);
 
+  // Переключение профиля подключения (production / replica / test)
+  const handleSwitchProfile = async (name: string) => {
+    setIsSwitching(true);
+    try {
+      await SwitchProfile(name);
+    } catch (error) {
+      console.error('Ошибка переключения профиля:', error);
+      alert(`Не удалось переключиться на профиль ${name}: ${error}`);
+    } finally {
+      setIsSwitching(false);
+      checkDatabaseStatus();
+    }
+  };
+
   const getBadgeColor = (status: string) => {
-    return status === 'Подключено' ? 'bg-green-500' : 'bg-red-500';
+    if (status === 'Подключено') {
+      return 'bg-green-500';
+    }
+    return status === 'Переподключение' ? 'bg-yellow-500 animate-pulse' : 'bg-red-500';
   };
 
   const handleApplyFilters = () => {
@@ -199,7 +252,7 @@ const Sidebar: React.FC<SidebarProps> = ({
   };
 
   // Определяем, показывать ли блок метрик
-  const shouldShowMetrics = activeView !== 'monthly' && activeView !== 'online';
+  const shouldShowMetrics = activeView !== 'monthly' && activeView !== 'agents' && activeView !== 'queues' && activeView !== 'online';
   
   // Определяем, показывать ли блок периода
   const shouldShowPeriod = activeView !== 'online';
@@ -224,23 +277,52 @@ const Sidebar: React.FC<SidebarProps> = ({
       {/* Статус подключений */}
       <div className="p-4 border-b border-gray-200 dark:border-dark-700">
         <div className="space-y-2">
+          <div className="flex items-center justify-between space-x-2">
+            <select
+              value={activeProfile}
+              onChange={(e) => handleSwitchProfile(e.target.value)}
+              disabled={isSwitching || profiles.length < 2}
+              title="Профиль подключения"
+              className="flex-1 px-2 py-1 bg-white dark:bg-dark-700 border border-gray-300 dark:border-dark-600 rounded-lg text-gray-900 dark:text-white text-xs focus:border-primary-500 focus:outline-none disabled:opacity-70"
+            >
+              {profiles.map((profile) => (
+                <option key={profile.name} value={profile.name} title={`MySQL ${profile.mysql}, MongoDB ${profile.mongodb}`}>
+                  {profile.name}
+                </option>
+              ))}
+            </select>
+            {isSwitching && <Loader2 className="w-4 h-4 animate-spin text-gray-500" />}
+            <button
+              onClick={() => setIsSettingsOpen(true)}
+              title="Настройки подключения"
+              className="text-gray-500 hover:text-gray-900 dark:hover:text-white"
+            >
+              <Settings className="w-4 h-4" />
+            </button>
+          </div>
           <div className="flex items-center justify-between text-sm">
             <span className="text-gray-600 dark:text-gray-400">MySQL</span>
-            <div className="flex items-center space-x-2">
-              <div className={`w-2 h-2 rounded-full ${dbStatus.mysql === 'Подключено' ? 'bg-green-500' : 'bg-red-500'}`} />
+            <div className="flex items-center space-x-2" title={dbStatus.mysqlError}>
+              <div className={`w-2 h-2 rounded-full ${getBadgeColor(dbStatus.mysql)}`} />
               <span className="text-xs text-gray-700 dark:text-gray-300">{dbStatus.mysql}</span>
             </div>
           </div>
           <div className="flex items-center justify-between text-sm">
             <span className="text-gray-600 dark:text-gray-400">MongoDB</span>
-            <div className="flex items-center space-x-2">
-              <div className={`w-2 h-2 rounded-full ${dbStatus.mongodb === 'Подключено' ? 'bg-green-500' : 'bg-red-500'}`} />
+            <div className="flex items-center space-x-2" title={dbStatus.mongodbError}>
+              <div className={`w-2 h-2 rounded-full ${getBadgeColor(dbStatus.mongodb)}`} />
               <span className="text-xs text-gray-700 dark:text-gray-300">{dbStatus.mongodb}</span>
             </div>
           </div>
         </div>
       </div>
 
+      <ConnectionSettings
+        isOpen={isSettingsOpen}
+        onClose={() => setIsSettingsOpen(false)}
+        onSaved={checkDatabaseStatus}
+      />
+
       {/* Фильтр по очередям */}
       <div className="p-4 border-b border-gray-200 dark:border-dark-700">
         <div className="flex items-center space-x-2 mb-3">
@@ -248,10 +330,10 @@ const Sidebar: React.FC<SidebarProps> = ({
           <h3 className="text-sm font-semibold text-gray-700 dark:text-gray-300 uppercase">Queues</h3>
         </div>
         <div className="space-y-1">
-          {queueFilters.map((queue) => (
+          {queueGroups.map((queue) => (
             <button
               key={queue.id}
-              onClick={() => setActiveQueue(queue.id as QueueFilter)}
+              onClick={() => setActiveQueue(queue.id)}
               className={clsx(
                 'w-full text-left px-3 py-2 text-sm rounded-lg transition-all duration-200',
                 activeQueue === queue.id
@@ -259,7 +341,7 @@ const Sidebar: React.FC<SidebarProps> = ({
                   : 'text-gray-700 dark:text-gray-300 hover:text-gray-900 dark:hover:text-white hover:bg-gray-100 dark:hover:bg-dark-700'
               )}
             >
-              {queue.label}
+              {queue.label || queue.id}
             </button>
           ))}
         </div>
@@ -303,8 +385,8 @@ const Sidebar: React.FC<SidebarProps> = ({
               <h4 className="text-xs text-gray-500 dark:text-gray-500 uppercase mb-2">Standard</h4>
               {defaultMetrics
                 .filter(metric => {
-                  // Скрываем detailed_daily для hourly view
-                  if (activeView === 'hourly' && metric.id === 'detailed_daily') {
+                  // Скрываем detailed_daily для hourly и intervals view
+                  if ((activeView === 'hourly' || activeView === 'intervals') && metric.id === 'detailed_daily') {
                     return false;
                   }
                   return true;
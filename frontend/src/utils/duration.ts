@@ -0,0 +1,10 @@
+// Длительности приходят из backend числом секунд
+
+// Форматирование секунд в HH:MM:SS (как SEC_TO_TIME в MySQL)
+export const formatDuration = (seconds: number): string => {
+  const total = Math.round(seconds || 0);
+  const h = Math.floor(total / 3600);
+  const m = Math.floor((total % 3600) / 60);
+  const s = total % 60;
+  return `${h.toString().padStart(2, '0')}:${m.toString().padStart(2, '0')}:${s.toString().padStart(2, '0')}`;
+};
@@ -1,5 +1,6 @@
 import * as XLSX from 'xlsx-js-style';
 import { saveAs } from 'file-saver';
+import { formatDuration } from './duration';
 
 // Интерфейс для данных дневной статистики
 interface DailyData {
@@ -9,10 +10,17 @@ interface DailyData {
   avg_call_duration_minutes: number;
   sl: number;
   total_abandoned: number;
+  abandon_rate: number;
+  asa: number; // ожидание отвеченных звонков, сек
+  max_wait: number;
+  wait_p50: number;
+  wait_p90: number;
+  wait_p95: number;
   total_chats: number;
   avg_chat_frt: string;
   resolution_time_avg: string;
   distinct_agents: number;
+  occupancy: number; // занятость агентов, %
   total_inquiries: number;
 }
 
@@ -20,17 +28,23 @@ interface DailyData {
 interface MonthlyCallData {
   day: number;
   total_calls: number;
-  avg_call_duration: string;
+  avg_call_duration: number; // в секундах
   sl: number;
   total_abandoned: number;
+  abandon_rate: number;
+  asa: number; // в секундах
+  max_wait: number;
+  wait_p50: number;
+  wait_p90: number;
+  wait_p95: number;
   distinct_agents: number;
 }
 
 interface MonthlyChatData {
   day: number;
   total_chats: number;
-  avg_chat_frt: string;
-  resolution_time_avg: string;
+  avg_chat_frt: number; // в секундах
+  resolution_time_avg: number; // в секундах
 }
 
 // Интерфейс для почасовых данных (Detailed daily)
@@ -237,6 +251,37 @@ export const exportMetricToExcel = (
       sheetName = 'Abandoned Data';
       break;
 
+    case 'abandon_rate':
+      metricData = [
+        ['Metric / 2025', ...dates],
+        ['Abandon rate (%)', ...tableData.map(row => `${row.abandon_rate.toFixed(1)}%`)]
+      ];
+      fileName = `Abandon_Rate_${queueName}_${formatDateForFilename(startDate)}_to_${formatDateForFilename(endDate)}.xlsx`;
+      sheetName = 'Abandon Rate Data';
+      break;
+
+    case 'asa':
+      metricData = [
+        ['Metric / 2025', ...dates],
+        ['ASA', ...tableData.map(row => formatDuration(row.asa))],
+        ['Max wait', ...tableData.map(row => formatDuration(row.max_wait))],
+        ['Wait p50', ...tableData.map(row => formatDuration(row.wait_p50))],
+        ['Wait p90', ...tableData.map(row => formatDuration(row.wait_p90))],
+        ['Wait p95', ...tableData.map(row => formatDuration(row.wait_p95))]
+      ];
+      fileName = `Wait_${queueName}_${formatDateForFilename(startDate)}_to_${formatDateForFilename(endDate)}.xlsx`;
+      sheetName = 'Wait Data';
+      break;
+
+    case 'occupancy':
+      metricData = [
+        ['Metric / 2025', ...dates],
+        ['Occupancy (%)', ...tableData.map(row => `${row.occupancy.toFixed(1)}%`)]
+      ];
+      fileName = `Occupancy_${queueName}_${formatDateForFilename(startDate)}_to_${formatDateForFilename(endDate)}.xlsx`;
+      sheetName = 'Occupancy Data';
+      break;
+
     case 'total':
       metricData = [
         ['Metric / 2025', ...dates],
@@ -305,9 +350,10 @@ export const exportAllDataToExcel = (
 
   // Подготавливаем данные
   const allMetricsData = [
-    ['Date', 'Calls', 'AHT (min)', 'SL (%)', 'Abandoned', 'Chats', 'FRT (min)', 'RT (min)', 'Agents'],
+    ['Date', 'Calls', 'AHT (min)', 'SL (%)', 'Abandoned', 'Abandon rate (%)', 'ASA', 'Max wait', 'Wait p50', 'Wait p90', 'Wait p95', 'Chats', 'FRT (min)', 'RT (min)', 'Agents', 'Occupancy (%)'],
     ...tableData.map(row => [
-      new Date(row.date).toLocaleDateString('ru-RU', { 
+      // Подписи недель, месяцев и кварталов выгружаются как есть
+      !/^\d{4}-\d{2}-\d{2}$/.test(row.date) ? row.date : new Date(row.date).toLocaleDateString('ru-RU', { 
         day: '2-digit', 
         month: '2-digit', 
         year: 'numeric' 
@@ -316,10 +362,17 @@ export const exportAllDataToExcel = (
       row.avg_call_duration,
       `${row.sl.toFixed(1)}%`,
       row.total_abandoned,
+      `${row.abandon_rate.toFixed(1)}%`,
+      formatDuration(row.asa),
+      formatDuration(row.max_wait),
+      formatDuration(row.wait_p50),
+      formatDuration(row.wait_p90),
+      formatDuration(row.wait_p95),
       row.total_chats,
       row.avg_chat_frt,
       row.resolution_time_avg,
-      row.distinct_agents
+      row.distinct_agents,
+      `${row.occupancy.toFixed(1)}%`
     ])
   ];
 
@@ -339,10 +392,17 @@ export const exportAllDataToExcel = (
     { wch: 15 }, // AHT
     { wch: 10 }, // SL
     { wch: 12 }, // Abandoned
+    { wch: 16 }, // Abandon rate
+    { wch: 10 }, // ASA
+    { wch: 10 }, // Max wait
+    { wch: 10 }, // Wait p50
+    { wch: 10 }, // Wait p90
+    { wch: 10 }, // Wait p95
     { wch: 8 },  // Chats
     { wch: 15 }, // FRT
     { wch: 15 }, // RT
-    { wch: 8 }   // Agents
+    { wch: 8 },  // Agents
+    { wch: 14 }  // Occupancy
   ];
 
   // Добавляем worksheet в workbook
@@ -423,7 +483,7 @@ export const exportMonthlyDataToExcel = (
       'AHT (min)',
       ...allDays.map(day => {
         const dayData = callDataMap.get(day);
-        return dayData ? dayData.avg_call_duration : '00:00:00';
+        return formatDuration(dayData ? dayData.avg_call_duration : 0);
       })
     ],
     [
@@ -440,6 +500,26 @@ export const exportMonthlyDataToExcel = (
         return dayData ? dayData.total_abandoned : 0;
       })
     ],
+    [
+      'Abandon rate (%)',
+      ...allDays.map(day => {
+        const dayData = callDataMap.get(day);
+        return dayData ? `${dayData.abandon_rate.toFixed(1)}%` : '0.0%';
+      })
+    ],
+    ...([
+      ['ASA', (row: MonthlyCallData) => row.asa],
+      ['Max wait', (row: MonthlyCallData) => row.max_wait],
+      ['Wait p50', (row: MonthlyCallData) => row.wait_p50],
+      ['Wait p90', (row: MonthlyCallData) => row.wait_p90],
+      ['Wait p95', (row: MonthlyCallData) => row.wait_p95],
+    ] as const).map(([label, value]) => [
+      label,
+      ...allDays.map(day => {
+        const dayData = callDataMap.get(day);
+        return formatDuration(dayData ? value(dayData) : 0);
+      })
+    ]),
     [
       'Chats',
       ...allDays.map(day => {
@@ -451,14 +531,14 @@ export const exportMonthlyDataToExcel = (
       'FRT (min)',
       ...allDays.map(day => {
         const dayData = chatDataMap.get(day);
-        return dayData ? dayData.avg_chat_frt : '00:00:00';
+        return formatDuration(dayData ? dayData.avg_chat_frt : 0);
       })
     ],
     [
       'RT (min)',
       ...allDays.map(day => {
         const dayData = chatDataMap.get(day);
-        return dayData ? dayData.resolution_time_avg : '00:00:00';
+        return formatDuration(dayData ? dayData.resolution_time_avg : 0);
       })
     ],
     [
@@ -549,33 +629,18 @@ const calculateMonthlySummary = (callData: MonthlyCallData[], chatData: MonthlyC
   const totalAbandoned = callData.reduce((sum, item) => sum + item.total_abandoned, 0);
   const totalChats = chatData.reduce((sum, item) => sum + item.total_chats, 0);
   
-  // Конвертация времени в секунды
-  const timeToSeconds = (time: string) => {
-    if (!time) return 0;
-    const parts = time.split(':');
-    return parseInt(parts[0]) * 3600 + parseInt(parts[1]) * 60 + parseInt(parts[2]);
-  };
-
-  // Форматирование секунд в HH:MM:SS
-  const formatSecondsToTime = (seconds: number) => {
-    const h = Math.floor(seconds / 3600);
-    const m = Math.floor((seconds % 3600) / 60);
-    const s = Math.floor(seconds % 60);
-    return `${h.toString().padStart(2, '0')}:${m.toString().padStart(2, '0')}:${s.toString().padStart(2, '0')}`;
-  };
-
   // Средние значения
   const avgAht = callData.length > 0 ? 
-    callData.reduce((sum, item, _, arr) => sum + timeToSeconds(item.avg_call_duration) / arr.length, 0) : 0;
+    callData.reduce((sum, item, _, arr) => sum + item.avg_call_duration / arr.length, 0) : 0;
 
   const avgSl = callData.length > 0 ? 
     callData.reduce((sum, item, _, arr) => sum + item.sl / arr.length, 0) : 0;
 
   const avgFrt = chatData.length > 0 ? 
-    chatData.reduce((sum, item, _, arr) => sum + timeToSeconds(item.avg_chat_frt) / arr.length, 0) : 0;
+    chatData.reduce((sum, item, _, arr) => sum + item.avg_chat_frt / arr.length, 0) : 0;
 
   const avgRt = chatData.length > 0 ? 
-    chatData.reduce((sum, item, _, arr) => sum + timeToSeconds(item.resolution_time_avg) / arr.length, 0) : 0;
+    chatData.reduce((sum, item, _, arr) => sum + item.resolution_time_avg / arr.length, 0) : 0;
 
   return {
     totalCalls,
@@ -583,10 +648,10 @@ const calculateMonthlySummary = (callData: MonthlyCallData[], chatData: MonthlyC
     totalChats,
     totalInquiries: totalCalls + totalChats,
     abandonedPercent: totalCalls > 0 ? (totalAbandoned / totalCalls) * 100 : 0,
-    avgAht: formatSecondsToTime(avgAht),
+    avgAht: formatDuration(avgAht),
     avgSl,
-    avgFrt: formatSecondsToTime(avgFrt),
-    avgRt: formatSecondsToTime(avgRt)
+    avgFrt: formatDuration(avgFrt),
+    avgRt: formatDuration(avgRt)
   };
 };
 
@@ -0,0 +1,44 @@
+import { useEffect, useRef } from 'react';
+import { CancelRequest } from '../../wailsjs/go/main/App';
+
+// Запросы к backend передают идентификатор, по которому их можно отменить.
+// Новая загрузка представления отменяет незавершенную предыдущую, уход
+// с представления — все его запросы.
+
+let counter = 0;
+
+export interface RequestLoad {
+  // Идентификатор для очередного запроса этой загрузки
+  id: () => string;
+  // Загрузку заменила более новая: ее результат и ошибку нужно игнорировать
+  stale: () => boolean;
+}
+
+export const useRequestScope = (scope: string) => {
+  const active = useRef<Set<string>>(new Set());
+  const generation = useRef(0);
+
+  const cancelAll = () => {
+    active.current.forEach((id) => CancelRequest(id));
+    active.current.clear();
+  };
+
+  useEffect(() => () => {
+    generation.current++;
+    cancelAll();
+  }, []);
+
+  // Начинает новую загрузку, отменяя запросы предыдущей
+  return (): RequestLoad => {
+    cancelAll();
+    const load = ++generation.current;
+    return {
+      id: () => {
+        const id = `${scope}-${++counter}`;
+        active.current.add(id);
+        return id;
+      },
+      stale: () => load !== generation.current,
+    };
+  };
+};
@@ -1,32 +1,71 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {main} from '../models';
 
-export function GetAvailableTopics(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+export function CancelRequest(arg1:string):Promise<boolean>;
 
-export function GetCallClassifiers(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+export function DeleteProfile(arg1:string):Promise<void>;
 
-export function GetChatClassifiers(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+export function GetAbandonWaits(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<main.AbandonWaitReport>;
 
-export function GetDailyData(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+export function GetAgentDetail(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<main.AgentDetail>;
 
-export function GetDatabaseStats():Promise<Record<string, any>>;
+export function GetAgentPerformance(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<main.AgentPerformanceReport>;
 
-export function GetHourlyData(arg1:string,arg2:string,arg3:string,arg4:string):Promise<Record<string, any>>;
+export function GetAvailableTopics(arg1:string,arg2:string,arg3:string,arg4:string):Promise<Array<string>>;
 
-export function GetMonthlyData(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+export function GetCallClassifiers(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.ClassifierReport>;
 
-export function GetOverallClassifiers(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+export function GetChatClassifiers(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.ClassifierReport>;
 
-export function GetQueueStats(arg1:string,arg2:string):Promise<Record<string, any>>;
+export function GetConnectionSettings():Promise<main.ConnectionSettings>;
 
-export function GetSubtopicsDaily(arg1:string,arg2:string,arg3:string,arg4:string):Promise<Record<string, any>>;
+export function GetConnectionStatus():Promise<main.ConnectionStatus>;
 
-export function GetTopics(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+export function GetDailyData(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.DailySeries>;
+
+export function GetDatabaseStats():Promise<main.DatabaseStats>;
+
+export function GetDurationDistribution(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:number):Promise<main.DurationDistribution>;
+
+export function GetHourlyData(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<main.HourlyMatrix>;
+
+export function GetIntervalData(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:number):Promise<main.IntervalMatrix>;
+
+export function GetMonthlyData(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.MonthlyReport>;
+
+export function GetOverallClassifiers(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.ClassifierReport>;
+
+export function GetPeriodData(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<main.PeriodReport>;
+
+export function GetQueueGroups():Promise<Array<main.QueueGroup>>;
+
+export function GetQueueStats(arg1:string,arg2:string,arg3:string):Promise<main.QueueStats>;
+
+export function GetServiceLevel(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.ServiceLevelReport>;
+
+export function GetSubtopicsDaily(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<main.ClassifierReport>;
+
+export function GetTopics(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.TopicReport>;
 
 export function Greet(arg1:string):Promise<string>;
 
+export function ListProfiles():Promise<Array<main.ProfileInfo>>;
+
+export function SaveConnectionSettings(arg1:main.DatabaseConfig):Promise<void>;
+
+export function SaveProfile(arg1:string,arg2:main.DatabaseConfig):Promise<void>;
+
+export function SetDatabasePassword(arg1:string,arg2:string):Promise<void>;
+
+export function SwitchProfile(arg1:string):Promise<void>;
+
+export function TestConnectionSettings(arg1:main.DatabaseConfig):Promise<main.ConnectionTestResult>;
+
 export function TestMongoDBConnection():Promise<string>;
 
-export function TestMongoDBConnectionDetailed():Promise<Record<string, any>>;
+export function TestMongoDBConnectionDetailed():Promise<main.MongoDiagnostics>;
 
 export function TestMySQLConnection():Promise<string>;
+
+export function UnlockSecretStore(arg1:string):Promise<void>;
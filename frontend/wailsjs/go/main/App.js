@@ -2,54 +2,126 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
-export function GetAvailableTopics(arg1, arg2, arg3) {
-  return window['go']['main']['App']['GetAvailableTopics'](arg1, arg2, arg3);
+export function CancelRequest(arg1) {
+  return window['go']['main']['App']['CancelRequest'](arg1);
 }
 
-export function GetCallClassifiers(arg1, arg2, arg3) {
-  return window['go']['main']['App']['GetCallClassifiers'](arg1, arg2, arg3);
+export function DeleteProfile(arg1) {
+  return window['go']['main']['App']['DeleteProfile'](arg1);
 }
 
-export function GetChatClassifiers(arg1, arg2, arg3) {
-  return window['go']['main']['App']['GetChatClassifiers'](arg1, arg2, arg3);
+export function GetAbandonWaits(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['GetAbandonWaits'](arg1, arg2, arg3, arg4, arg5);
 }
 
-export function GetDailyData(arg1, arg2, arg3) {
-  return window['go']['main']['App']['GetDailyData'](arg1, arg2, arg3);
+export function GetAgentDetail(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['GetAgentDetail'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function GetAgentPerformance(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['GetAgentPerformance'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function GetAvailableTopics(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetAvailableTopics'](arg1, arg2, arg3, arg4);
+}
+
+export function GetCallClassifiers(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetCallClassifiers'](arg1, arg2, arg3, arg4);
+}
+
+export function GetChatClassifiers(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetChatClassifiers'](arg1, arg2, arg3, arg4);
+}
+
+export function GetConnectionSettings() {
+  return window['go']['main']['App']['GetConnectionSettings']();
+}
+
+export function GetConnectionStatus() {
+  return window['go']['main']['App']['GetConnectionStatus']();
+}
+
+export function GetDailyData(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetDailyData'](arg1, arg2, arg3, arg4);
 }
 
 export function GetDatabaseStats() {
   return window['go']['main']['App']['GetDatabaseStats']();
 }
 
-export function GetHourlyData(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['GetHourlyData'](arg1, arg2, arg3, arg4);
+export function GetDurationDistribution(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['GetDurationDistribution'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function GetHourlyData(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['GetHourlyData'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function GetIntervalData(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['GetIntervalData'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function GetMonthlyData(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetMonthlyData'](arg1, arg2, arg3, arg4);
+}
+
+export function GetOverallClassifiers(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetOverallClassifiers'](arg1, arg2, arg3, arg4);
+}
+
+export function GetPeriodData(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['GetPeriodData'](arg1, arg2, arg3, arg4, arg5);
 }
 
-export function GetMonthlyData(arg1, arg2, arg3) {
-  return window['go']['main']['App']['GetMonthlyData'](arg1, arg2, arg3);
+export function GetQueueGroups() {
+  return window['go']['main']['App']['GetQueueGroups']();
 }
 
-export function GetOverallClassifiers(arg1, arg2, arg3) {
-  return window['go']['main']['App']['GetOverallClassifiers'](arg1, arg2, arg3);
+export function GetQueueStats(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetQueueStats'](arg1, arg2, arg3);
 }
 
-export function GetQueueStats(arg1, arg2) {
-  return window['go']['main']['App']['GetQueueStats'](arg1, arg2);
+export function GetServiceLevel(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetServiceLevel'](arg1, arg2, arg3, arg4);
 }
 
-export function GetSubtopicsDaily(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['GetSubtopicsDaily'](arg1, arg2, arg3, arg4);
+export function GetSubtopicsDaily(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['GetSubtopicsDaily'](arg1, arg2, arg3, arg4, arg5);
 }
 
-export function GetTopics(arg1, arg2, arg3) {
-  return window['go']['main']['App']['GetTopics'](arg1, arg2, arg3);
+export function GetTopics(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetTopics'](arg1, arg2, arg3, arg4);
 }
 
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ListProfiles() {
+  return window['go']['main']['App']['ListProfiles']();
+}
+
+export function SaveConnectionSettings(arg1) {
+  return window['go']['main']['App']['SaveConnectionSettings'](arg1);
+}
+
+export function SaveProfile(arg1, arg2) {
+  return window['go']['main']['App']['SaveProfile'](arg1, arg2);
+}
+
+export function SetDatabasePassword(arg1, arg2) {
+  return window['go']['main']['App']['SetDatabasePassword'](arg1, arg2);
+}
+
+export function SwitchProfile(arg1) {
+  return window['go']['main']['App']['SwitchProfile'](arg1);
+}
+
+export function TestConnectionSettings(arg1) {
+  return window['go']['main']['App']['TestConnectionSettings'](arg1);
+}
+
 export function TestMongoDBConnection() {
   return window['go']['main']['App']['TestMongoDBConnection']();
 }
@@ -61,3 +133,7 @@ export function TestMongoDBConnectionDetailed() {
 export function TestMySQLConnection() {
   return window['go']['main']['App']['TestMySQLConnection']();
 }
+
+export function UnlockSecretStore(arg1) {
+  return window['go']['main']['App']['UnlockSecretStore'](arg1);
+}
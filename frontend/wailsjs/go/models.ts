@@ -0,0 +1,1409 @@
+export namespace main {
+	
+	export class WaitHistogramRow {
+	    period: string;
+	    bins: number[];
+	    abandoned: number;
+	    short: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WaitHistogramRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
+	        this.bins = source["bins"];
+	        this.abandoned = source["abandoned"];
+	        this.short = source["short"];
+	    }
+	}
+	export class AbandonWaitReport {
+	    granularity: string;
+	    short_abandon_seconds: number;
+	    bins: string[];
+	    rows: WaitHistogramRow[];
+	    total: WaitHistogramRow;
+	
+	    static createFrom(source: any = {}) {
+	        return new AbandonWaitReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.granularity = source["granularity"];
+	        this.short_abandon_seconds = source["short_abandon_seconds"];
+	        this.bins = source["bins"];
+	        this.rows = this.convertValues(source["rows"], WaitHistogramRow);
+	        this.total = this.convertValues(source["total"], WaitHistogramRow);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AgentConcurrency {
+	    chats: number;
+	    max: number;
+	    peak_at: string;
+	    average: number;
+	    overlap_share: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentConcurrency(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.chats = source["chats"];
+	        this.max = source["max"];
+	        this.peak_at = source["peak_at"];
+	        this.average = source["average"];
+	        this.overlap_share = source["overlap_share"];
+	    }
+	}
+	export class AgentDay {
+	    date: string;
+	    calls: number;
+	    chats: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentDay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.calls = source["calls"];
+	        this.chats = source["chats"];
+	    }
+	}
+	export class QueryTiming {
+	    query: string;
+	    duration_ms: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueryTiming(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.query = source["query"];
+	        this.duration_ms = source["duration_ms"];
+	    }
+	}
+	export class AgentTopic {
+	    topic: string;
+	    total: number;
+	    ratio: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentTopic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.topic = source["topic"];
+	        this.total = source["total"];
+	        this.ratio = source["ratio"];
+	    }
+	}
+	export class DurationBin {
+	    label: string;
+	    from: number;
+	    to: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DurationBin(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.count = source["count"];
+	    }
+	}
+	export class DurationDistribution {
+	    metric: string;
+	    cap_seconds: number;
+	    count: number;
+	    trimmed: number;
+	    mean: number;
+	    median: number;
+	    p90: number;
+	    p95: number;
+	    max: number;
+	    bins: DurationBin[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DurationDistribution(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.metric = source["metric"];
+	        this.cap_seconds = source["cap_seconds"];
+	        this.count = source["count"];
+	        this.trimmed = source["trimmed"];
+	        this.mean = source["mean"];
+	        this.median = source["median"];
+	        this.p90 = source["p90"];
+	        this.p95 = source["p95"];
+	        this.max = source["max"];
+	        this.bins = this.convertValues(source["bins"], DurationBin);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AgentHour {
+	    hour: number;
+	    calls: number;
+	    chats: number;
+	    occupancy: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentHour(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hour = source["hour"];
+	        this.calls = source["calls"];
+	        this.chats = source["chats"];
+	        this.occupancy = source["occupancy"];
+	    }
+	}
+	export class AgentMetrics {
+	    calls: number;
+	    chats: number;
+	    handled: number;
+	    aht: number;
+	    frt: number;
+	    rt: number;
+	    active_hours: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentMetrics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.calls = source["calls"];
+	        this.chats = source["chats"];
+	        this.handled = source["handled"];
+	        this.aht = source["aht"];
+	        this.frt = source["frt"];
+	        this.rt = source["rt"];
+	        this.active_hours = source["active_hours"];
+	    }
+	}
+	export class AgentDetail {
+	    user_id: string;
+	    name: string;
+	    metrics: AgentMetrics;
+	    daily: AgentDay[];
+	    hourly: AgentHour[];
+	    aht: DurationDistribution;
+	    frt: DurationDistribution;
+	    rt: DurationDistribution;
+	    concurrency: AgentConcurrency;
+	    occupancy: number;
+	    topics: AgentTopic[];
+	    timings: QueryTiming[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentDetail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.user_id = source["user_id"];
+	        this.name = source["name"];
+	        this.metrics = this.convertValues(source["metrics"], AgentMetrics);
+	        this.daily = this.convertValues(source["daily"], AgentDay);
+	        this.hourly = this.convertValues(source["hourly"], AgentHour);
+	        this.aht = this.convertValues(source["aht"], DurationDistribution);
+	        this.frt = this.convertValues(source["frt"], DurationDistribution);
+	        this.rt = this.convertValues(source["rt"], DurationDistribution);
+	        this.concurrency = this.convertValues(source["concurrency"], AgentConcurrency);
+	        this.occupancy = source["occupancy"];
+	        this.topics = this.convertValues(source["topics"], AgentTopic);
+	        this.timings = this.convertValues(source["timings"], QueryTiming);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class AgentMedian {
+	    calls: number;
+	    chats: number;
+	    handled: number;
+	    aht: number;
+	    frt: number;
+	    rt: number;
+	    active_hours: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentMedian(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.calls = source["calls"];
+	        this.chats = source["chats"];
+	        this.handled = source["handled"];
+	        this.aht = source["aht"];
+	        this.frt = source["frt"];
+	        this.rt = source["rt"];
+	        this.active_hours = source["active_hours"];
+	    }
+	}
+	
+	export class AgentPerformance {
+	    rank: number;
+	    user_id: string;
+	    name: string;
+	    metrics: AgentMetrics;
+	    vs_median: number;
+	    better_than_median: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentPerformance(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rank = source["rank"];
+	        this.user_id = source["user_id"];
+	        this.name = source["name"];
+	        this.metrics = this.convertValues(source["metrics"], AgentMetrics);
+	        this.vs_median = source["vs_median"];
+	        this.better_than_median = source["better_than_median"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AgentPerformanceReport {
+	    sort_by: string;
+	    agents: AgentPerformance[];
+	    median: AgentMedian;
+	    timings: QueryTiming[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentPerformanceReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sort_by = source["sort_by"];
+	        this.agents = this.convertValues(source["agents"], AgentPerformance);
+	        this.median = this.convertValues(source["median"], AgentMedian);
+	        this.timings = this.convertValues(source["timings"], QueryTiming);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ClassifierResult {
+	    report_date: string;
+	    topic: string;
+	    subtopic: string;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ClassifierResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.report_date = source["report_date"];
+	        this.topic = source["topic"];
+	        this.subtopic = source["subtopic"];
+	        this.total = source["total"];
+	    }
+	}
+	export class ClassifierReport {
+	    type: string;
+	    data: ClassifierResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ClassifierReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.data = this.convertValues(source["data"], ClassifierResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SLTarget {
+	    threshold_seconds: number;
+	    target_percent: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SLTarget(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.threshold_seconds = source["threshold_seconds"];
+	        this.target_percent = source["target_percent"];
+	    }
+	}
+	export class QueueGroup {
+	    id: string;
+	    label: string;
+	    call_queues: string[];
+	    chat_channels: string[];
+	    call_sl: SLTarget;
+	    chat_sl: SLTarget;
+	    short_abandon_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueueGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.label = source["label"];
+	        this.call_queues = source["call_queues"];
+	        this.chat_channels = source["chat_channels"];
+	        this.call_sl = this.convertValues(source["call_sl"], SLTarget);
+	        this.chat_sl = this.convertValues(source["chat_sl"], SLTarget);
+	        this.short_abandon_seconds = source["short_abandon_seconds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SSHConfig {
+	    host?: string;
+	    port?: string;
+	    user?: string;
+	    key_file?: string;
+	    known_hosts?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SSHConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.user = source["user"];
+	        this.key_file = source["key_file"];
+	        this.known_hosts = source["known_hosts"];
+	    }
+	}
+	export class MongoDBConfig {
+	    uri?: string;
+	    host: string;
+	    port: string;
+	    user: string;
+	    password?: string;
+	    database: string;
+	    auth_source?: string;
+	    auth_mechanism?: string;
+	    tls: TLSConfig;
+	
+	    static createFrom(source: any = {}) {
+	        return new MongoDBConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.uri = source["uri"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.user = source["user"];
+	        this.password = source["password"];
+	        this.database = source["database"];
+	        this.auth_source = source["auth_source"];
+	        this.auth_mechanism = source["auth_mechanism"];
+	        this.tls = this.convertValues(source["tls"], TLSConfig);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TLSConfig {
+	    mode?: string;
+	    ca_file?: string;
+	    cert_file?: string;
+	    key_file?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TLSConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.ca_file = source["ca_file"];
+	        this.cert_file = source["cert_file"];
+	        this.key_file = source["key_file"];
+	    }
+	}
+	export class MySQLConfig {
+	    host: string;
+	    port: string;
+	    user: string;
+	    password?: string;
+	    database: string;
+	    tls: TLSConfig;
+	
+	    static createFrom(source: any = {}) {
+	        return new MySQLConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.user = source["user"];
+	        this.password = source["password"];
+	        this.database = source["database"];
+	        this.tls = this.convertValues(source["tls"], TLSConfig);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DatabaseConfig {
+	    mysql: MySQLConfig;
+	    mongodb: MongoDBConfig;
+	    ssh: SSHConfig;
+	    queue_groups?: QueueGroup[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DatabaseConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mysql = this.convertValues(source["mysql"], MySQLConfig);
+	        this.mongodb = this.convertValues(source["mongodb"], MongoDBConfig);
+	        this.ssh = this.convertValues(source["ssh"], SSHConfig);
+	        this.queue_groups = this.convertValues(source["queue_groups"], QueueGroup);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConnectionSettings {
+	    profile: string;
+	    config: DatabaseConfig;
+	    config_path: string;
+	    mysql_password_set: boolean;
+	    mongodb_password_set: boolean;
+	    secret_store_locked: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectionSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.profile = source["profile"];
+	        this.config = this.convertValues(source["config"], DatabaseConfig);
+	        this.config_path = source["config_path"];
+	        this.mysql_password_set = source["mysql_password_set"];
+	        this.mongodb_password_set = source["mongodb_password_set"];
+	        this.secret_store_locked = source["secret_store_locked"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DatabaseStatus {
+	    state: string;
+	    error?: string;
+	    attempts: number;
+	    next_retry?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DatabaseStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.state = source["state"];
+	        this.error = source["error"];
+	        this.attempts = source["attempts"];
+	        this.next_retry = source["next_retry"];
+	    }
+	}
+	export class ConnectionStatus {
+	    profile: string;
+	    mysql: DatabaseStatus;
+	    mongodb: DatabaseStatus;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectionStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.profile = source["profile"];
+	        this.mysql = this.convertValues(source["mysql"], DatabaseStatus);
+	        this.mongodb = this.convertValues(source["mongodb"], DatabaseStatus);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConnectionTestResult {
+	    ok: boolean;
+	    mysql: string;
+	    mongodb: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectionTestResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ok = source["ok"];
+	        this.mysql = source["mysql"];
+	        this.mongodb = source["mongodb"];
+	    }
+	}
+	export class DailyPoint {
+	    date: string;
+	    value: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DailyPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.value = source["value"];
+	    }
+	}
+	export class DailySeries {
+	    calls: DailyPoint[];
+	    aht: DailyPoint[];
+	    sl: DailyPoint[];
+	    abandoned: DailyPoint[];
+	    abandon_rate: DailyPoint[];
+	    asa: DailyPoint[];
+	    max_wait: DailyPoint[];
+	    wait_p50: DailyPoint[];
+	    wait_p90: DailyPoint[];
+	    wait_p95: DailyPoint[];
+	    chats: DailyPoint[];
+	    frt: DailyPoint[];
+	    rt: DailyPoint[];
+	    agents: DailyPoint[];
+	    occupancy: DailyPoint[];
+	    sl_target: SLTarget;
+	    timings: QueryTiming[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DailySeries(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.calls = this.convertValues(source["calls"], DailyPoint);
+	        this.aht = this.convertValues(source["aht"], DailyPoint);
+	        this.sl = this.convertValues(source["sl"], DailyPoint);
+	        this.abandoned = this.convertValues(source["abandoned"], DailyPoint);
+	        this.abandon_rate = this.convertValues(source["abandon_rate"], DailyPoint);
+	        this.asa = this.convertValues(source["asa"], DailyPoint);
+	        this.max_wait = this.convertValues(source["max_wait"], DailyPoint);
+	        this.wait_p50 = this.convertValues(source["wait_p50"], DailyPoint);
+	        this.wait_p90 = this.convertValues(source["wait_p90"], DailyPoint);
+	        this.wait_p95 = this.convertValues(source["wait_p95"], DailyPoint);
+	        this.chats = this.convertValues(source["chats"], DailyPoint);
+	        this.frt = this.convertValues(source["frt"], DailyPoint);
+	        this.rt = this.convertValues(source["rt"], DailyPoint);
+	        this.agents = this.convertValues(source["agents"], DailyPoint);
+	        this.occupancy = this.convertValues(source["occupancy"], DailyPoint);
+	        this.sl_target = this.convertValues(source["sl_target"], SLTarget);
+	        this.timings = this.convertValues(source["timings"], QueryTiming);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class DatabaseStats {
+	    mysql: string;
+	    mongodb: string;
+	    profile: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DatabaseStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mysql = source["mysql"];
+	        this.mongodb = source["mongodb"];
+	        this.profile = source["profile"];
+	    }
+	}
+	
+	
+	
+	export class HourlyValues {
+	    date: string;
+	    hours: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new HourlyValues(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.hours = source["hours"];
+	    }
+	}
+	export class HourlyMatrix {
+	    metric: string;
+	    rows: HourlyValues[];
+	
+	    static createFrom(source: any = {}) {
+	        return new HourlyMatrix(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.metric = source["metric"];
+	        this.rows = this.convertValues(source["rows"], HourlyValues);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class IntervalValues {
+	    date: string;
+	    values: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new IntervalValues(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.values = source["values"];
+	    }
+	}
+	export class IntervalMatrix {
+	    metric: string;
+	    interval_minutes: number;
+	    timezone: string;
+	    labels: string[];
+	    rows: IntervalValues[];
+	    average: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new IntervalMatrix(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.metric = source["metric"];
+	        this.interval_minutes = source["interval_minutes"];
+	        this.timezone = source["timezone"];
+	        this.labels = source["labels"];
+	        this.rows = this.convertValues(source["rows"], IntervalValues);
+	        this.average = source["average"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class MongoDiagnostics {
+	    existing_connection: string;
+	    settings: string;
+	    test_configured: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MongoDiagnostics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.existing_connection = source["existing_connection"];
+	        this.settings = source["settings"];
+	        this.test_configured = source["test_configured"];
+	    }
+	}
+	export class MonthlyCallDay {
+	    month: string;
+	    day: number;
+	    total_calls: number;
+	    avg_call_duration: number;
+	    sl: number;
+	    total_abandoned: number;
+	    abandon_rate: number;
+	    asa: number;
+	    max_wait: number;
+	    wait_p50: number;
+	    wait_p90: number;
+	    wait_p95: number;
+	    distinct_agents: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MonthlyCallDay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.month = source["month"];
+	        this.day = source["day"];
+	        this.total_calls = source["total_calls"];
+	        this.avg_call_duration = source["avg_call_duration"];
+	        this.sl = source["sl"];
+	        this.total_abandoned = source["total_abandoned"];
+	        this.abandon_rate = source["abandon_rate"];
+	        this.asa = source["asa"];
+	        this.max_wait = source["max_wait"];
+	        this.wait_p50 = source["wait_p50"];
+	        this.wait_p90 = source["wait_p90"];
+	        this.wait_p95 = source["wait_p95"];
+	        this.distinct_agents = source["distinct_agents"];
+	    }
+	}
+	export class MonthlyChatDay {
+	    month: string;
+	    day: number;
+	    total_chats: number;
+	    avg_chat_frt: number;
+	    resolution_time_avg: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MonthlyChatDay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.month = source["month"];
+	        this.day = source["day"];
+	        this.total_chats = source["total_chats"];
+	        this.avg_chat_frt = source["avg_chat_frt"];
+	        this.resolution_time_avg = source["resolution_time_avg"];
+	    }
+	}
+	export class MonthlyReport {
+	    calls: MonthlyCallDay[];
+	    chats: MonthlyChatDay[];
+	    sl_target: SLTarget;
+	
+	    static createFrom(source: any = {}) {
+	        return new MonthlyReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.calls = this.convertValues(source["calls"], MonthlyCallDay);
+	        this.chats = this.convertValues(source["chats"], MonthlyChatDay);
+	        this.sl_target = this.convertValues(source["sl_target"], SLTarget);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class PeriodMetrics {
+	    period: string;
+	    start: string;
+	    end: string;
+	    calls: number;
+	    aht: number;
+	    sl: number;
+	    abandoned: number;
+	    abandon_rate: number;
+	    asa: number;
+	    max_wait: number;
+	    wait_p50: number;
+	    wait_p90: number;
+	    wait_p95: number;
+	    chats: number;
+	    frt: number;
+	    rt: number;
+	    agents: number;
+	    occupancy: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PeriodMetrics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.calls = source["calls"];
+	        this.aht = source["aht"];
+	        this.sl = source["sl"];
+	        this.abandoned = source["abandoned"];
+	        this.abandon_rate = source["abandon_rate"];
+	        this.asa = source["asa"];
+	        this.max_wait = source["max_wait"];
+	        this.wait_p50 = source["wait_p50"];
+	        this.wait_p90 = source["wait_p90"];
+	        this.wait_p95 = source["wait_p95"];
+	        this.chats = source["chats"];
+	        this.frt = source["frt"];
+	        this.rt = source["rt"];
+	        this.agents = source["agents"];
+	        this.occupancy = source["occupancy"];
+	    }
+	}
+	export class PeriodReport {
+	    granularity: string;
+	    periods: PeriodMetrics[];
+	    sl_target: SLTarget;
+	    timings: QueryTiming[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PeriodReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.granularity = source["granularity"];
+	        this.periods = this.convertValues(source["periods"], PeriodMetrics);
+	        this.sl_target = this.convertValues(source["sl_target"], SLTarget);
+	        this.timings = this.convertValues(source["timings"], QueryTiming);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProfileInfo {
+	    name: string;
+	    active: boolean;
+	    mysql: string;
+	    mongodb: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProfileInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.active = source["active"];
+	        this.mysql = source["mysql"];
+	        this.mongodb = source["mongodb"];
+	    }
+	}
+	
+	
+	export class QueueInfo {
+	    name: string;
+	    calls: number;
+	    chats: number;
+	    requests: number;
+	    first_seen: string;
+	    last_seen: string;
+	    groups: string[];
+	    unassigned: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueueInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.calls = source["calls"];
+	        this.chats = source["chats"];
+	        this.requests = source["requests"];
+	        this.first_seen = source["first_seen"];
+	        this.last_seen = source["last_seen"];
+	        this.groups = source["groups"];
+	        this.unassigned = source["unassigned"];
+	    }
+	}
+	export class QueueStats {
+	    queues: QueueInfo[];
+	    unassigned: number;
+	    sources: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new QueueStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.queues = this.convertValues(source["queues"], QueueInfo);
+	        this.unassigned = source["unassigned"];
+	        this.sources = source["sources"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class ThresholdSL {
+	    seconds: number;
+	    sl: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ThresholdSL(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.seconds = source["seconds"];
+	        this.sl = source["sl"];
+	    }
+	}
+	export class ServiceLevelDay {
+	    date: string;
+	    total: number;
+	    thresholds: ThresholdSL[];
+	    sl: number;
+	    met: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceLevelDay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.total = source["total"];
+	        this.thresholds = this.convertValues(source["thresholds"], ThresholdSL);
+	        this.sl = source["sl"];
+	        this.met = source["met"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServiceLevelSeries {
+	    target: SLTarget;
+	    days: ServiceLevelDay[];
+	    total: ServiceLevelDay;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceLevelSeries(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.target = this.convertValues(source["target"], SLTarget);
+	        this.days = this.convertValues(source["days"], ServiceLevelDay);
+	        this.total = this.convertValues(source["total"], ServiceLevelDay);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ServiceLevelReport {
+	    calls: ServiceLevelSeries;
+	    chats: ServiceLevelSeries;
+	    timings: QueryTiming[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ServiceLevelReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.calls = this.convertValues(source["calls"], ServiceLevelSeries);
+	        this.chats = this.convertValues(source["chats"], ServiceLevelSeries);
+	        this.timings = this.convertValues(source["timings"], QueryTiming);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class TopicResult {
+	    report_date: string;
+	    topic: string;
+	    total: number;
+	    ratio: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TopicResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.report_date = source["report_date"];
+	        this.topic = source["topic"];
+	        this.total = source["total"];
+	        this.ratio = source["ratio"];
+	    }
+	}
+	export class TopicReport {
+	    type: string;
+	    data: TopicResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TopicReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.data = this.convertValues(source["data"], TopicResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+
+}
+
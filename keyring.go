@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+// systemKeyring провайдер системного хранилища учетных данных:
+// Windows Credential Manager, macOS Keychain, Secret Service в Linux
+type systemKeyring struct{}
+
+// Get возвращает секрет; отсутствующий секрет - ErrSecretNotFound
+func (systemKeyring) Get(service, user string) (string, error) {
+	value, err := keyring.Get(service, user)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrSecretNotFound
+	}
+	return value, err
+}
+
+// Set сохраняет секрет
+func (systemKeyring) Set(service, user, password string) error {
+	return keyring.Set(service, user, password)
+}
+
+// Delete удаляет секрет; удаление отсутствующего секрета не ошибка
+func (systemKeyring) Delete(service, user string) error {
+	if err := keyring.Delete(service, user); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return err
+	}
+	return nil
+}
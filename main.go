@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"embed"
+	"log"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -14,11 +16,24 @@ var assets embed.FS
 
 // main - основная функция приложения
 func main() {
+	// Загрузка конфигурации баз данных: файл, переменные окружения CCDASH_*, флаги
+	profiles, configPath, err := LoadDatabaseConfig(os.Args[1:])
+	if err != nil {
+		log.Printf("Ошибка конфигурации баз данных (%s): %v", configPath, err)
+	}
+
+	// Хранилище паролей: зашифрованный файл или системное хранилище
+	SetKeyringProvider(systemKeyring{})
+	secrets, err := OpenSecretStore()
+	if err != nil {
+		log.Printf("Ошибка открытия хранилища секретов: %v", err)
+	}
+
 	// Создание экземпляра приложения
-	app := NewApp()
+	app := NewApp(profiles, configPath, secrets)
 
 	// Настройка и запуск приложения Wails
-	err := wails.Run(&options.App{
+	err = wails.Run(&options.App{
 		Title:  "CC Dashboard",
 		Width:  1400,
 		Height: 900,
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+)
+
+// Занятость агентов (occupancy): доля времени агента в интервале, занятая
+// разговорами и открытыми чатами. Данных о входе агентов в системе нет,
+// поэтому знаменатель — длина интервала, в котором агент что-то обрабатывал.
+// Одновременные обработки агента (несколько чатов, чат во время звонка)
+// сливаются, так что занятость агента не превышает 100%.
+
+// agentInterval интервал дня шириной width минут у одного агента
+type agentInterval struct {
+	userID string
+	date   string
+	bucket int
+}
+
+// busySeconds занятые секунды агентов по интервалам шириной width минут.
+// Обработки, продолжающиеся после endDate, учитываются только до конца периода.
+func busySeconds(width int, startDate, endDate string, sources ...[]HandleSpan) map[agentInterval]float64 {
+	type span struct{ start, end float64 } // секунды Unix
+	byAgent := make(map[string][]span)
+	for _, spans := range sources {
+		for _, s := range spans {
+			if s.Duration <= 0 {
+				continue
+			}
+			day, err := time.Parse(dateLayout, s.Date)
+			if err != nil {
+				continue
+			}
+			start := float64(day.Unix() + int64(s.Second))
+			byAgent[s.UserID] = append(byAgent[s.UserID], span{start, start + s.Duration})
+		}
+	}
+
+	size := float64(width * 60)
+	busy := make(map[agentInterval]float64)
+	add := func(userID string, from, to float64) {
+		for from < to {
+			bucketStart := float64(int64(from/size)) * size
+			next := min(to, bucketStart+size)
+			t := time.Unix(int64(bucketStart), 0).UTC()
+			date := t.Format(dateLayout)
+			if date >= startDate && date <= endDate {
+				minute := t.Hour()*60 + t.Minute()
+				busy[agentInterval{userID, date, minute / width}] += next - from
+			}
+			from = next
+		}
+	}
+
+	for userID, spans := range byAgent {
+		slices.SortFunc(spans, func(a, b span) int { return cmp.Compare(a.start, b.start) })
+		current := spans[0]
+		for _, s := range spans[1:] {
+			if s.start <= current.end {
+				current.end = max(current.end, s.end)
+				continue
+			}
+			add(userID, current.start, current.end)
+			current = s
+		}
+		add(userID, current.start, current.end)
+	}
+	return busy
+}
+
+// groupOccupancy занятость по ключу: занятые секунды агентов к суммарной
+// длине их интервалов с обработками, %
+func groupOccupancy[K comparable](width int, busy map[agentInterval]float64, key func(agentInterval) K) map[K]float64 {
+	seconds := make(map[K]float64)
+	intervals := make(map[K]int)
+	for interval, s := range busy {
+		k := key(interval)
+		seconds[k] += s
+		intervals[k]++
+	}
+
+	occupancy := make(map[K]float64, len(seconds))
+	for k, s := range seconds {
+		occupancy[k] = ratio(s*100, intervals[k]*width*60)
+	}
+	return occupancy
+}
+
+// occupancyBuckets занятость группы очередей по дням и интервалам шириной width минут
+func occupancyBuckets(width int, busy map[agentInterval]float64) []BucketRow {
+	type dayBucket struct {
+		date   string
+		bucket int
+	}
+	occupancy := groupOccupancy(width, busy, func(i agentInterval) dayBucket { return dayBucket{i.date, i.bucket} })
+
+	values := make([]BucketValue, 0, len(occupancy))
+	for key, value := range occupancy {
+		values = append(values, BucketValue{Date: key.date, Bucket: key.bucket, Value: value})
+	}
+	return pivotBuckets(values, width)
+}
+
+// dailyOccupancy занятость группы очередей по дням по часовым интервалам агентов
+func dailyOccupancy(busy map[agentInterval]float64) []DailyPoint {
+	occupancy := groupOccupancy(60, busy, func(i agentInterval) string { return i.date })
+	points := make([]DailyPoint, 0, len(occupancy))
+	for _, date := range sortedKeys(occupancy) {
+		points = append(points, DailyPoint{Date: date, Value: asPercent(occupancy[date])})
+	}
+	return points
+}
+
+// handleSpans разговоры и открытые чаты агентов группы очередей
+func handleSpans(ctx context.Context, repos Repositories, startDate, endDate string, group QueueGroup) ([]HandleSpan, []HandleSpan, error) {
+	calls, err := repos.Calls.HandleSpans(ctx, startDate, endDate, group.CallQueues)
+	if err != nil {
+		return nil, nil, err
+	}
+	chats, err := repos.Chats.HandleSpans(ctx, startDate, endDate, group.ChatChannels)
+	if err != nil {
+		return nil, nil, err
+	}
+	return calls, chats, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBusySeconds(t *testing.T) {
+	calls := []HandleSpan{
+		// 09:10-09:20 делится между интервалами 09:00 и 09:15
+		{UserID: "a1", Date: "2024-03-01", Second: 9*3600 + 600, Duration: 600},
+		// Через полночь: после конца периода не учитывается
+		{UserID: "a2", Date: "2024-03-01", Second: 23*3600 + 55*60, Duration: 600},
+	}
+	chats := []HandleSpan{
+		// Чат 09:15-09:17 во время звонка не добавляет занятости
+		{UserID: "a1", Date: "2024-03-01", Second: 9*3600 + 900, Duration: 120},
+		{UserID: "a1", Date: "2024-03-01", Second: 12 * 3600},
+	}
+
+	got := busySeconds(15, "2024-03-01", "2024-03-01", calls, chats)
+	want := map[agentInterval]float64{
+		{"a1", "2024-03-01", 36}: 300,
+		{"a1", "2024-03-01", 37}: 300,
+		{"a2", "2024-03-01", 95}: 300,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("busySeconds = %v, want %v", got, want)
+	}
+
+	// Два агента в часе 09:00 и один в 23:00: 600 + 300 с из трех часов
+	hourly := busySeconds(60, "2024-03-01", "2024-03-02", calls, chats,
+		[]HandleSpan{{UserID: "a3", Date: "2024-03-01", Second: 9 * 3600, Duration: 1800}})
+	points := dailyOccupancy(hourly)
+	wantPoints := []DailyPoint{{"2024-03-01", 25}, {"2024-03-02", 8.33}}
+	if !reflect.DeepEqual(points, wantPoints) {
+		t.Errorf("dailyOccupancy = %v, want %v", points, wantPoints)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Детализация отчета по периодам: дни, ISO-недели (с понедельника), месяцы,
+// кварталы, годы или весь выбранный диапазон одним периодом. Метрики периода
+// пересчитываются из сумм и количеств за дни, а не усредняются по дням.
+
+// granularities допустимая детализация
+var granularities = []string{"day", "week", "month", "quarter", "year", "period"}
+
+// reportPeriod период отчета с границами в пределах выбранного диапазона
+type reportPeriod struct {
+	label      string
+	start, end string // YYYY-MM-DD включительно
+}
+
+// periodLabel подпись периода, в который попадает день
+func periodLabel(day time.Time, granularity string) string {
+	switch granularity {
+	case "day":
+		return day.Format(dateLayout)
+	case "week":
+		year, week := day.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case "month":
+		return day.Format("2006-01")
+	case "quarter":
+		return fmt.Sprintf("%d-Q%d", day.Year(), (int(day.Month())-1)/3+1)
+	case "year":
+		return day.Format("2006")
+	}
+	return ""
+}
+
+// splitPeriods делит диапазон дат на периоды; первый и последний период
+// обрезаются по границам диапазона
+func splitPeriods(startDate, endDate, granularity string) ([]reportPeriod, error) {
+	if !slices.Contains(granularities, granularity) {
+		return nil, fmt.Errorf("неподдерживаемая детализация: %s (допустимо %v)", granularity, granularities)
+	}
+	if _, err := periodDays(startDate, endDate); err != nil {
+		return nil, err
+	}
+	if granularity == "period" {
+		return []reportPeriod{{label: startDate + ".." + endDate, start: startDate, end: endDate}}, nil
+	}
+
+	start, _ := time.Parse(dateLayout, startDate)
+	end, _ := time.Parse(dateLayout, endDate)
+
+	var periods []reportPeriod
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		label := periodLabel(day, granularity)
+		date := day.Format(dateLayout)
+		if len(periods) > 0 && periods[len(periods)-1].label == label {
+			periods[len(periods)-1].end = date
+			continue
+		}
+		periods = append(periods, reportPeriod{label: label, start: date, end: date})
+	}
+	return periods, nil
+}
+
+// periodIndex номер периода, в который попадает дата; -1 вне диапазона
+func periodIndex(periods []reportPeriod, date string) int {
+	i, found := slices.BinarySearchFunc(periods, date, func(p reportPeriod, date string) int {
+		switch {
+		case p.end < date:
+			return -1
+		case p.start > date:
+			return 1
+		}
+		return 0
+	})
+	if !found {
+		return -1
+	}
+	return i
+}
+
+// aggregatePeriods собирает метрики периодов из дневных сумм звонков и чатов,
+// занятости агентов по часам и активности агентов. Агенты за период считаются уникальными.
+func aggregatePeriods(periods []reportPeriod, calls []CallTotals, chats []ChatTotals, waits []WaitCount, busy map[agentInterval]float64, activity ...[]AgentActivity) []PeriodMetrics {
+	callTotals := make([]CallTotals, len(periods))
+	for _, day := range calls {
+		if i := periodIndex(periods, day.Date); i >= 0 {
+			callTotals[i].add(day)
+		}
+	}
+	chatTotals := make([]ChatTotals, len(periods))
+	for _, day := range chats {
+		if i := periodIndex(periods, day.Date); i >= 0 {
+			chatTotals[i].add(day)
+		}
+	}
+	// Перцентили ожидания не складываются из дневных: считаем по всем звонкам периода
+	waitGroups := groupWaits(waits, func(w WaitCount) int { return periodIndex(periods, w.Date) })
+	agents := countAgents(func(e AgentActivity) int { return periodIndex(periods, e.Date) }, activity...)
+	occupancy := groupOccupancy(60, busy, func(i agentInterval) int { return periodIndex(periods, i.date) })
+
+	result := make([]PeriodMetrics, len(periods))
+	for i, period := range periods {
+		c, ch := callTotals[i], chatTotals[i]
+		w, _ := summarizeWaits(waitGroups[i])
+		result[i] = PeriodMetrics{
+			Period:      period.label,
+			Start:       period.start,
+			End:         period.end,
+			Calls:       c.Calls,
+			AHT:         asSeconds(ratio(c.DurationSum, c.DurationCount)),
+			SL:          asPercent(ratio(float64(c.WithinSL), c.Answered) * 100),
+			Abandoned:   c.Abandoned,
+			AbandonRate: asPercent(ratio(float64(c.Abandoned), c.Calls-c.ShortAbandoned) * 100),
+			ASA:         asSeconds(w.asa),
+			MaxWait:     asSeconds(w.max),
+			WaitP50:     asSeconds(w.p50),
+			WaitP90:     asSeconds(w.p90),
+			WaitP95:     asSeconds(w.p95),
+			Chats:       ch.Chats,
+			FRT:         asSeconds(ratio(ch.FRTSum, ch.FRTCount)),
+			RT:          asSeconds(ratio(ch.RTSum, ch.RTCount)),
+			Agents:      agents[i],
+			Occupancy:   asPercent(occupancy[i]),
+		}
+	}
+	return result
+}
+
+// ratio частное суммы и количества; ноль, если количество нулевое
+func ratio(sum float64, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitPeriods(t *testing.T) {
+	tests := []struct {
+		granularity string
+		start, end  string
+		want        []reportPeriod
+	}{
+		{"day", "2024-02-28", "2024-03-01", []reportPeriod{
+			{"2024-02-28", "2024-02-28", "2024-02-28"},
+			{"2024-02-29", "2024-02-29", "2024-02-29"},
+			{"2024-03-01", "2024-03-01", "2024-03-01"},
+		}},
+		// ISO-недели начинаются с понедельника; 30.12.2024 — первая неделя 2025 года
+		{"week", "2024-12-25", "2025-01-06", []reportPeriod{
+			{"2024-W52", "2024-12-25", "2024-12-29"},
+			{"2025-W01", "2024-12-30", "2025-01-05"},
+			{"2025-W02", "2025-01-06", "2025-01-06"},
+		}},
+		{"month", "2024-01-15", "2024-03-10", []reportPeriod{
+			{"2024-01", "2024-01-15", "2024-01-31"},
+			{"2024-02", "2024-02-01", "2024-02-29"},
+			{"2024-03", "2024-03-01", "2024-03-10"},
+		}},
+		{"quarter", "2024-03-15", "2024-07-01", []reportPeriod{
+			{"2024-Q1", "2024-03-15", "2024-03-31"},
+			{"2024-Q2", "2024-04-01", "2024-06-30"},
+			{"2024-Q3", "2024-07-01", "2024-07-01"},
+		}},
+		{"year", "2023-12-31", "2024-01-01", []reportPeriod{
+			{"2023", "2023-12-31", "2023-12-31"},
+			{"2024", "2024-01-01", "2024-01-01"},
+		}},
+		{"period", "2024-03-01", "2024-03-10", []reportPeriod{
+			{"2024-03-01..2024-03-10", "2024-03-01", "2024-03-10"},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := splitPeriods(tt.start, tt.end, tt.granularity)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.granularity, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %v\nwant %v", tt.granularity, got, tt.want)
+		}
+	}
+
+	if _, err := splitPeriods("2024-03-01", "2024-03-10", "fortnight"); err == nil {
+		t.Error("expected error for unknown granularity")
+	}
+	if _, err := splitPeriods("2024-03-10", "2024-03-01", "week"); err == nil {
+		t.Error("expected error for reversed period")
+	}
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ProfileInfo краткое описание профиля подключения для frontend (без паролей)
+type ProfileInfo struct {
+	Name    string `json:"name"`
+	Active  bool   `json:"active"`
+	MySQL   string `json:"mysql"`
+	MongoDB string `json:"mongodb"`
+}
+
+// Profiles возвращает копию набора профилей
+func (ds *DatabaseService) Profiles() ConnectionProfiles {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return ds.copyProfiles()
+}
+
+// copyProfiles копирует профили, вызывается под ds.mu
+func (ds *DatabaseService) copyProfiles() ConnectionProfiles {
+	profiles := ConnectionProfiles{
+		Active:   ds.profiles.Active,
+		Profiles: make(map[string]DatabaseConfig, len(ds.profiles.Profiles)),
+	}
+	for name, config := range ds.profiles.Profiles {
+		profiles.Profiles[name] = config
+	}
+	return profiles
+}
+
+// TestConfig проверяет конфигурацию профиля теми же шагами, что и
+// ConnectMySQL/ConnectMongoDB, и закрывает пробные соединения.
+// Пустой пароль означает "оставить текущий".
+func (ds *DatabaseService) TestConfig(profile string, config DatabaseConfig) (mysqlErr, mongoErr error) {
+	mysqlConn, mongoConn, mysqlErr, mongoErr := ds.open(profile, config)
+	closeConnections(mysqlConn, mongoConn)
+	return mysqlErr, mongoErr
+}
+
+// SaveProfile проверяет и сохраняет профиль: пароли - в хранилище секретов,
+// остальное - в файл конфигурации. Если профиль активен, живые соединения
+// подменяются без перезапуска; старые закрываются, когда завершатся все
+// запросы, которые успели их получить.
+func (ds *DatabaseService) SaveProfile(name string, config DatabaseConfig) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("имя профиля не задано")
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	live := name == ds.ActiveProfile()
+	var mysqlConn *mysqlConn
+	var mongoConn *mongoConn
+	if live {
+		var mysqlErr, mongoErr error
+		mysqlConn, mongoConn, mysqlErr, mongoErr = ds.open(name, config)
+		if mysqlErr != nil || mongoErr != nil {
+			closeConnections(mysqlConn, mongoConn)
+			return errors.Join(mysqlErr, mongoErr)
+		}
+	}
+
+	// Пароль из строки подключения MongoDB тоже уходит в хранилище,
+	// в файл конфигурации строка попадает без него
+	if password := config.MongoDB.uriPassword(); password != "" {
+		if config.MongoDB.Password == "" {
+			config.MongoDB.Password = password
+		}
+		config.MongoDB.URI = withoutURIPassword(config.MongoDB.URI)
+	}
+
+	// Новые пароли уходят в хранилище, в памяти остаются только явно
+	// переданные через окружение
+	current := ds.Profiles().Profiles[name]
+	if config.MySQL.Password != "" {
+		if err := ds.SetPassword(name, secretMySQLPassword, config.MySQL.Password); err != nil {
+			closeConnections(mysqlConn, mongoConn)
+			return fmt.Errorf("не удалось сохранить пароль MySQL: %v", err)
+		}
+		config.MySQL.Password = ""
+	} else {
+		config.MySQL.Password = current.MySQL.Password
+	}
+	if config.MongoDB.Password != "" {
+		if err := ds.SetPassword(name, secretMongoDBPassword, config.MongoDB.Password); err != nil {
+			closeConnections(mysqlConn, mongoConn)
+			return fmt.Errorf("не удалось сохранить пароль MongoDB: %v", err)
+		}
+		config.MongoDB.Password = ""
+	} else {
+		config.MongoDB.Password = current.MongoDB.Password
+	}
+
+	ds.mu.Lock()
+	if live && ds.profiles.Active != name {
+		ds.mu.Unlock()
+		closeConnections(mysqlConn, mongoConn)
+		return fmt.Errorf("профиль подключения изменился во время сохранения")
+	}
+	err := updateConfigFile(ds.configPath, func(file *configFile) {
+		file.setProfile(name, config)
+	})
+	if err != nil {
+		ds.mu.Unlock()
+		closeConnections(mysqlConn, mongoConn)
+		return err
+	}
+	ds.profiles.Profiles[name] = config
+	if live {
+		ds.swapLocked(mysqlConn, mongoConn)
+	}
+	ds.mu.Unlock()
+
+	if live {
+		log.Printf("Соединения профиля %s переключены на новую конфигурацию: MySQL %s, MongoDB %s",
+			name, config.MySQL, config.MongoDB)
+	}
+	return nil
+}
+
+// SwitchProfile подключается к базам профиля и делает его активным.
+// Запросы, начатые на предыдущем профиле, дорабатывают на его соединениях,
+// новые запросы получают соединения только нового профиля.
+func (ds *DatabaseService) SwitchProfile(name string) error {
+	profiles := ds.Profiles()
+	config, ok := profiles.Profiles[name]
+	if !ok {
+		return fmt.Errorf("профиль подключения %q не найден", name)
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	mysqlConn, mongoConn, mysqlErr, mongoErr := ds.open(name, config)
+	if mysqlErr != nil || mongoErr != nil {
+		closeConnections(mysqlConn, mongoConn)
+		return errors.Join(mysqlErr, mongoErr)
+	}
+
+	ds.mu.Lock()
+	// Пока соединения открывались, профиль могли удалить или пересохранить
+	if current, ok := ds.profiles.Profiles[name]; !ok || !reflect.DeepEqual(current, config) {
+		ds.mu.Unlock()
+		closeConnections(mysqlConn, mongoConn)
+		return fmt.Errorf("профиль подключения %q изменился во время переключения", name)
+	}
+	err := updateConfigFile(ds.configPath, func(file *configFile) {
+		file.ActiveProfile = name
+	})
+	if err != nil {
+		ds.mu.Unlock()
+		closeConnections(mysqlConn, mongoConn)
+		return fmt.Errorf("не удалось сохранить активный профиль: %w", err)
+	}
+	ds.profiles.Active = name
+	ds.swapLocked(mysqlConn, mongoConn)
+	ds.mu.Unlock()
+
+	log.Printf("Активный профиль подключения: %s", name)
+	return nil
+}
+
+// DeleteProfile удаляет неактивный именованный профиль и его пароли
+func (ds *DatabaseService) DeleteProfile(name string) error {
+	if name == defaultProfileName {
+		return fmt.Errorf("профиль %s нельзя удалить", defaultProfileName)
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if name == ds.profiles.Active {
+		return fmt.Errorf("нельзя удалить активный профиль %s", name)
+	}
+	if _, ok := ds.profiles.Profiles[name]; !ok {
+		return fmt.Errorf("профиль подключения %q не найден", name)
+	}
+
+	err := updateConfigFile(ds.configPath, func(file *configFile) {
+		delete(file.Profiles, name)
+		if file.ActiveProfile == name {
+			file.ActiveProfile = ""
+		}
+	})
+	if err != nil {
+		return err
+	}
+	delete(ds.profiles.Profiles, name)
+
+	if ds.secrets != nil {
+		for _, key := range []string{secretMySQLPassword, secretMongoDBPassword} {
+			if err := ds.secrets.Delete(profileSecretKey(name, key)); err != nil {
+				log.Printf("Не удалось удалить пароль %s профиля %s: %v", key, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// openConfig открывает соединения для конфигурации профиля.
+// Незаполненные пароли берутся из сохраненного профиля или хранилища.
+func (ds *DatabaseService) openConfig(profile string, config DatabaseConfig) (*mysqlConn, *mongoConn, error, error) {
+	current := ds.Profiles().Profiles[profile]
+
+	var mysqlConn *mysqlConn
+	mysqlPassword, mysqlErr := config.MySQL.Password, error(nil)
+	if mysqlPassword == "" {
+		mysqlPassword, mysqlErr = ds.resolvePassword(profile, secretMySQLPassword, current.MySQL.Password)
+	}
+	if mysqlErr == nil {
+		mysqlConn, mysqlErr = openMySQL(config.MySQL, config.SSH, mysqlPassword)
+	}
+
+	var mongoConn *mongoConn
+	mongoPassword, mongoErr := config.MongoDB.configuredPassword(), error(nil)
+	if mongoPassword == "" && config.MongoDB.needsPassword() {
+		mongoPassword, mongoErr = ds.resolvePassword(profile, secretMongoDBPassword, current.MongoDB.configuredPassword())
+	}
+	if mongoErr == nil {
+		mongoConn, mongoErr = openMongoDB(config.MongoDB, config.SSH, mongoPassword)
+	}
+
+	return mysqlConn, mongoConn, mysqlErr, mongoErr
+}
+
+// swapLocked подменяет оба соединения разом, вызывается под ds.mu
+func (ds *DatabaseService) swapLocked(mysqlConn *mysqlConn, mongoConn *mongoConn) {
+	oldMySQL, oldMongo := ds.mysql, ds.mongo
+	ds.mysql, ds.mongo = mysqlConn, mongoConn
+	oldMySQL.retire()
+	oldMongo.retire()
+}
+
+// closeConnections закрывает пробные соединения, которые не пошли в работу
+func closeConnections(mysqlConn *mysqlConn, mongoConn *mongoConn) {
+	mysqlConn.close()
+	mongoConn.close(context.Background())
+}
+
+// ListProfiles возвращает профили подключения и отмечает активный
+func (a *App) ListProfiles() []ProfileInfo {
+	profiles := a.dbService.Profiles()
+	result := make([]ProfileInfo, 0, len(profiles.Profiles))
+	for _, name := range profiles.Names() {
+		config := profiles.Profiles[name]
+		result = append(result, ProfileInfo{
+			Name:    name,
+			Active:  name == profiles.Active,
+			MySQL:   config.MySQL.String(),
+			MongoDB: config.MongoDB.String(),
+		})
+	}
+	return result
+}
+
+// SwitchProfile переключает приложение на другой профиль без перезапуска
+// и сообщает frontend, что ранее загруженные данные нужно перечитать
+func (a *App) SwitchProfile(name string) error {
+	if err := a.dbService.SwitchProfile(name); err != nil {
+		log.Printf("Не удалось переключить профиль на %s: %v", name, err)
+		return err
+	}
+	a.supervisor.Wake()
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "db:profile", name)
+	}
+	return nil
+}
+
+// SaveProfile создает или обновляет именованный профиль подключения
+func (a *App) SaveProfile(name string, config DatabaseConfig) error {
+	return a.dbService.SaveProfile(name, config)
+}
+
+// DeleteProfile удаляет неактивный профиль подключения
+func (a *App) DeleteProfile(name string) error {
+	return a.dbService.DeleteProfile(name)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const profilesTestConfig = `{
+  "mysql": {"host": "mysql.prod", "port": "3306", "user": "report", "database": "report"},
+  "mongodb": {"host": "mongo.prod", "port": "27017", "user": "reader", "database": "request"},
+  "profiles": {
+    "replica": {"mysql": {"host": "mysql.replica"}}
+  }
+}`
+
+// newProfilesTestService загружает профили из файла конфигурации
+// и подменяет открытие соединений, чтобы не ходить в базы
+func newProfilesTestService(t *testing.T, args ...string) (*DatabaseService, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(profilesTestConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	profiles, configPath, err := LoadDatabaseConfig(append([]string{"-config", path}, args...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := NewDatabaseService(profiles, configPath, nil)
+	ds.open = func(string, DatabaseConfig) (*mysqlConn, *mongoConn, error, error) {
+		return nil, nil, nil, nil
+	}
+	return ds, path
+}
+
+// readRawConfig читает файл конфигурации без значений по умолчанию
+func readRawConfig(t *testing.T, path string) configFile {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file configFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestSwitchProfileKeepsFileLayer(t *testing.T) {
+	t.Setenv("CCDASH_MYSQL_HOST", "mysql.override")
+	ds, path := newProfilesTestService(t, "-mongodb-user", "flag-user")
+	if got := ds.Config().MySQL.Host; got != "mysql.override" {
+		t.Fatalf("active mysql.host = %q, want env override", got)
+	}
+
+	if err := ds.SwitchProfile("replica"); err != nil {
+		t.Fatal(err)
+	}
+
+	file := readRawConfig(t, path)
+	if file.ActiveProfile != "replica" {
+		t.Fatalf("active_profile = %q, want replica", file.ActiveProfile)
+	}
+	if file.MySQL.Host != "mysql.prod" || file.MongoDB.User != "reader" {
+		t.Fatalf("overrides written to file: mysql.host %q, mongodb.user %q", file.MySQL.Host, file.MongoDB.User)
+	}
+	want := DatabaseConfig{MySQL: MySQLConfig{Host: "mysql.replica"}}
+	if got := file.Profiles["replica"]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("replica profile in file = %+v, want overlay %+v", got, want)
+	}
+}
+
+func TestSaveProfileWritesOverlay(t *testing.T) {
+	ds, path := newProfilesTestService(t)
+
+	config := ds.Profiles().Profiles["replica"]
+	config.MongoDB.Host = "mongo.replica"
+	if err := ds.SaveProfile("replica", config); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.SaveProfile("test", DatabaseConfig{
+		MySQL:   MySQLConfig{Host: "mysql.test", Port: "3306", User: "report", Database: "report"},
+		MongoDB: MongoDBConfig{Host: "mongo.prod", Port: "27017", User: "reader", Database: "request"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	file := readRawConfig(t, path)
+	wantReplica := DatabaseConfig{
+		MySQL:   MySQLConfig{Host: "mysql.replica"},
+		MongoDB: MongoDBConfig{Host: "mongo.replica"},
+	}
+	if got := file.Profiles["replica"]; !reflect.DeepEqual(got, wantReplica) {
+		t.Fatalf("replica profile in file = %+v, want %+v", got, wantReplica)
+	}
+	wantTest := DatabaseConfig{MySQL: MySQLConfig{Host: "mysql.test"}}
+	if got := file.Profiles["test"]; !reflect.DeepEqual(got, wantTest) {
+		t.Fatalf("test profile in file = %+v, want %+v", got, wantTest)
+	}
+
+	if err := ds.DeleteProfile("test"); err != nil {
+		t.Fatal(err)
+	}
+	file = readRawConfig(t, path)
+	if _, ok := file.Profiles["test"]; ok {
+		t.Fatal("deleted profile still in file")
+	}
+	if file.MySQL.Host != "mysql.prod" || file.ActiveProfile != "" {
+		t.Fatalf("unexpected file after delete: %+v", file)
+	}
+}
+
+func TestSwitchProfileChangedWhileOpening(t *testing.T) {
+	ds, path := newProfilesTestService(t)
+	opened := func(change func()) {
+		ds.open = func(string, DatabaseConfig) (*mysqlConn, *mongoConn, error, error) {
+			change()
+			return nil, nil, nil, nil
+		}
+	}
+
+	// Профиль пересохранили, пока открывались соединения
+	opened(func() {
+		config := ds.Profiles().Profiles["replica"]
+		config.MySQL.Host = "mysql.replica2"
+		if err := ds.SaveProfile("replica", config); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if err := ds.SwitchProfile("replica"); err == nil {
+		t.Fatal("expected error for profile saved during switch")
+	}
+
+	// Профиль удалили, пока открывались соединения
+	opened(func() {
+		if err := ds.DeleteProfile("replica"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if err := ds.SwitchProfile("replica"); err == nil {
+		t.Fatal("expected error for profile deleted during switch")
+	}
+
+	if active := ds.Profiles().Active; active != defaultProfileName {
+		t.Fatalf("active = %q, want %s", active, defaultProfileName)
+	}
+	if file := readRawConfig(t, path); file.ActiveProfile != "" {
+		t.Fatalf("active_profile = %q, want empty", file.ActiveProfile)
+	}
+}
+
+func TestSwitchProfileSaveError(t *testing.T) {
+	ds, path := newProfilesTestService(t)
+	// Каталог конфигурации подменен обычным файлом: сохранить нельзя
+	ds.configPath = filepath.Join(path, "config.json")
+
+	if err := ds.SwitchProfile("replica"); err == nil {
+		t.Fatal("expected error when active profile is not saved")
+	}
+	if active := ds.Profiles().Active; active != defaultProfileName {
+		t.Fatalf("active = %q, want %s", active, defaultProfileName)
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// QueueGroup группа очередей для фильтра дашборда: очереди звонков
+// call_report и каналы чатов, которые отчеты показывают под одним id
+type QueueGroup struct {
+	ID           string   `json:"id" yaml:"id"`
+	Label        string   `json:"label" yaml:"label"`
+	CallQueues   []string `json:"call_queues" yaml:"call_queues,omitempty"`
+	ChatChannels []string `json:"chat_channels" yaml:"chat_channels,omitempty"`
+	// CallSL цель SL звонков: доля отвеченных с ожиданием в очереди не дольше порога
+	CallSL SLTarget `json:"call_sl" yaml:"call_sl,omitempty"`
+	// ChatSL цель SL чатов: доля чатов с FRT не дольше порога
+	ChatSL SLTarget `json:"chat_sl" yaml:"chat_sl,omitempty"`
+	// ShortAbandonSeconds звонки, брошенные быстрее, не входят в брошенные
+	// и в процент брошенных; 0 — учитываются все
+	ShortAbandonSeconds int `json:"short_abandon_seconds" yaml:"short_abandon_seconds,omitempty"`
+}
+
+// SLTarget порог уровня сервиса и целевой процент; нулевые поля берутся
+// из целей по умолчанию
+type SLTarget struct {
+	ThresholdSeconds int     `json:"threshold_seconds" yaml:"threshold_seconds,omitempty"`
+	TargetPercent    float64 `json:"target_percent" yaml:"target_percent,omitempty"`
+}
+
+// Цели SL по умолчанию для звонков и чатов
+var (
+	defaultCallSL = SLTarget{ThresholdSeconds: 20, TargetPercent: 95}
+	defaultChatSL = SLTarget{ThresholdSeconds: 60, TargetPercent: 95}
+)
+
+// orDefault дополняет незаданные поля цели значениями по умолчанию
+func (t SLTarget) orDefault(defaults SLTarget) SLTarget {
+	if t.ThresholdSeconds == 0 {
+		t.ThresholdSeconds = defaults.ThresholdSeconds
+	}
+	if t.TargetPercent == 0 {
+		t.TargetPercent = defaults.TargetPercent
+	}
+	return t
+}
+
+// CallSLTarget цель SL звонков группы
+func (g QueueGroup) CallSLTarget() SLTarget {
+	return g.CallSL.orDefault(defaultCallSL)
+}
+
+// ChatSLTarget цель SL чатов группы
+func (g QueueGroup) ChatSLTarget() SLTarget {
+	return g.ChatSL.orDefault(defaultChatSL)
+}
+
+// CallThresholds пороги группы для метрик звонков
+func (g QueueGroup) CallThresholds() CallThresholds {
+	return CallThresholds{SLSeconds: g.CallSLTarget().ThresholdSeconds, ShortAbandonSeconds: g.ShortAbandonSeconds}
+}
+
+// Каналы чатов основной линии m10
+var m10ChatChannels = []string{"m10 Facebook", "WHATSAPP", "m10 Instagram", "telegram"}
+
+// DefaultQueueGroups возвращает группы очередей, если в конфигурации они не заданы
+func DefaultQueueGroups() []QueueGroup {
+	return []QueueGroup{
+		{ID: "all", Label: "All queues", CallQueues: []string{"m10", "m10-shikayet"}, ChatChannels: append([]string(nil), m10ChatChannels...)},
+		{ID: "m10", Label: "m10", CallQueues: []string{"m10"}, ChatChannels: append([]string(nil), m10ChatChannels...)},
+		{ID: "aml", Label: "AML", CallQueues: []string{"m10-shikayet"}, ChatChannels: []string{}, CallSL: SLTarget{ThresholdSeconds: 30}},
+	}
+}
+
+// Queues возвращает очереди звонков и каналы чатов группы вместе
+func (g QueueGroup) Queues() []string {
+	queues := make([]string, 0, len(g.CallQueues)+len(g.ChatChannels))
+	queues = append(queues, g.CallQueues...)
+	return append(queues, g.ChatChannels...)
+}
+
+// QueueRegistry группы очередей профиля подключения
+type QueueRegistry []QueueGroup
+
+// Groups возвращает группы профиля или группы по умолчанию
+func (r QueueRegistry) Groups() []QueueGroup {
+	if len(r) == 0 {
+		return DefaultQueueGroups()
+	}
+	return r
+}
+
+// Group находит группу по id без учета регистра. Неизвестный id
+// считается именем отдельной очереди звонков или канала чатов.
+func (r QueueRegistry) Group(id string) QueueGroup {
+	for _, group := range r.Groups() {
+		if strings.EqualFold(group.ID, id) {
+			return group
+		}
+	}
+	return QueueGroup{ID: id, Label: id, CallQueues: []string{id}, ChatChannels: []string{id}}
+}
+
+// queueGroupProblems проверяет группы очередей из конфигурации
+func queueGroupProblems(groups []QueueGroup) []string {
+	var problems []string
+	seen := make(map[string]bool)
+	for i, group := range groups {
+		id := strings.ToLower(strings.TrimSpace(group.ID))
+		switch {
+		case id == "":
+			problems = append(problems, fmt.Sprintf("у группы очередей queue_groups[%d] не задан id", i))
+			continue
+		case seen[id]:
+			problems = append(problems, fmt.Sprintf("группа очередей %q указана несколько раз", group.ID))
+		}
+		seen[id] = true
+		if len(group.CallQueues) == 0 && len(group.ChatChannels) == 0 {
+			problems = append(problems, fmt.Sprintf("в группе очередей %q нет ни очередей звонков, ни каналов чатов", group.ID))
+		}
+		problems = append(problems, slTargetProblems(group.ID, "call_sl", group.CallSL)...)
+		problems = append(problems, slTargetProblems(group.ID, "chat_sl", group.ChatSL)...)
+		if group.ShortAbandonSeconds < 0 {
+			problems = append(problems, fmt.Sprintf("в группе очередей %q порог short_abandon_seconds отрицательный: %d сек", group.ID, group.ShortAbandonSeconds))
+		}
+	}
+	return problems
+}
+
+// slTargetProblems проверяет цель SL группы очередей
+func slTargetProblems(groupID, name string, target SLTarget) []string {
+	var problems []string
+	if target.ThresholdSeconds < 0 {
+		problems = append(problems, fmt.Sprintf("в группе очередей %q порог %s отрицательный: %d сек", groupID, name, target.ThresholdSeconds))
+	}
+	if target.TargetPercent < 0 || target.TargetPercent > 100 {
+		problems = append(problems, fmt.Sprintf("в группе очередей %q цель %s вне диапазона 0..100%%: %v", groupID, name, target.TargetPercent))
+	}
+	return problems
+}
+
+// QueueInfo очередь или канал, найденные в данных за период
+type QueueInfo struct {
+	Name      string `json:"name"`
+	Calls     int    `json:"calls"`    // записей в call_report
+	Chats     int    `json:"chats"`    // записей в chat_report
+	Requests  int    `json:"requests"` // обращений в MongoDB
+	FirstSeen string `json:"first_seen"`
+	LastSeen  string `json:"last_seen"`
+	// Groups группы очередей, в которые попадают данные очереди
+	Groups []string `json:"groups"`
+	// Unassigned хотя бы часть данных очереди не попадает ни в одну группу
+	// и не видна на дашборде
+	Unassigned bool `json:"unassigned"`
+}
+
+// QueueStats очереди, найденные за период во всех источниках
+type QueueStats struct {
+	Queues     []QueueInfo `json:"queues"`
+	Unassigned int         `json:"unassigned"`
+	// Sources источники, по которым искались очереди: call_report, chat_report, request
+	Sources []string `json:"sources"`
+}
+
+// discoverQueues объединяет очереди звонков, каналы чатов и очереди обращений
+// и сверяет их с группами реестра. Очередь звонков должна быть в call_queues
+// какой-нибудь группы, канал чатов — в chat_channels, очередь обращений — в любом из них.
+// Первыми идут неразмеченные очереди, дальше — по убыванию количества записей.
+func discoverQueues(registry QueueRegistry, calls, chats, requests []QueueUsage) []QueueInfo {
+	byName := make(map[string]*QueueInfo)
+	add := func(usage []QueueUsage, counter func(*QueueInfo) *int) {
+		for _, queue := range usage {
+			info := byName[queue.QueueName]
+			if info == nil {
+				info = &QueueInfo{Name: queue.QueueName, FirstSeen: queue.FirstSeen, LastSeen: queue.LastSeen}
+				byName[queue.QueueName] = info
+			}
+			*counter(info) += queue.Count
+			if queue.FirstSeen < info.FirstSeen {
+				info.FirstSeen = queue.FirstSeen
+			}
+			if queue.LastSeen > info.LastSeen {
+				info.LastSeen = queue.LastSeen
+			}
+		}
+	}
+	add(calls, func(info *QueueInfo) *int { return &info.Calls })
+	add(chats, func(info *QueueInfo) *int { return &info.Chats })
+	add(requests, func(info *QueueInfo) *int { return &info.Requests })
+
+	groups := registry.Groups()
+	queues := make([]QueueInfo, 0, len(byName))
+	for _, info := range byName {
+		var inCalls, inChats, inRequests bool
+		info.Groups = make([]string, 0)
+		for _, group := range groups {
+			call := slices.Contains(group.CallQueues, info.Name)
+			chat := slices.Contains(group.ChatChannels, info.Name)
+			inCalls = inCalls || call
+			inChats = inChats || chat
+			inRequests = inRequests || call || chat
+			if (info.Calls > 0 && call) || (info.Chats > 0 && chat) || (info.Requests > 0 && (call || chat)) {
+				info.Groups = append(info.Groups, group.ID)
+			}
+		}
+		info.Unassigned = (info.Calls > 0 && !inCalls) || (info.Chats > 0 && !inChats) || (info.Requests > 0 && !inRequests)
+		queues = append(queues, *info)
+	}
+
+	sort.Slice(queues, func(i, j int) bool {
+		a, b := queues[i], queues[j]
+		if a.Unassigned != b.Unassigned {
+			return a.Unassigned
+		}
+		if totalA, totalB := a.Calls+a.Chats+a.Requests, b.Calls+b.Chats+b.Requests; totalA != totalB {
+			return totalA > totalB
+		}
+		return a.Name < b.Name
+	})
+	return queues
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQueueRegistryGroup(t *testing.T) {
+	var defaults QueueRegistry
+
+	tests := []struct {
+		id        string
+		wantCalls []string
+		wantChats []string
+	}{
+		{"all", []string{"m10", "m10-shikayet"}, m10ChatChannels},
+		{"m10", []string{"m10"}, m10ChatChannels},
+		{"aml", []string{"m10-shikayet"}, []string{}},
+		{"AML", []string{"m10-shikayet"}, []string{}},
+		// Неизвестный id — отдельная очередь или канал
+		{"telegram", []string{"telegram"}, []string{"telegram"}},
+	}
+	for _, tt := range tests {
+		group := defaults.Group(tt.id)
+		if !reflect.DeepEqual(group.CallQueues, tt.wantCalls) || !reflect.DeepEqual(group.ChatChannels, tt.wantChats) {
+			t.Errorf("Group(%q) = %+v", tt.id, group)
+		}
+	}
+
+	want := []string{"m10", "m10 Facebook", "WHATSAPP", "m10 Instagram", "telegram"}
+	if got := defaults.Group("m10").Queues(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Queues() = %v, want %v", got, want)
+	}
+}
+
+func TestQueueGroupsConfig(t *testing.T) {
+	base := DatabaseConfig{
+		MySQL:   MySQLConfig{Host: "192.168.46.4", Port: "3306", User: "report_user", Database: "report"},
+		MongoDB: MongoDBConfig{Host: "192.168.46.4", Port: "27017", User: "readonly", Database: "request"},
+	}
+
+	config := base
+	config.QueueGroups = []QueueGroup{
+		{ID: "m10", CallQueues: []string{"m10"}},
+		{ID: "M10", CallQueues: []string{"m10"}},
+		{ID: "", CallQueues: []string{"x"}},
+		{ID: "empty"},
+	}
+	err := config.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{`"M10"`, "queue_groups[2]", `"empty"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+
+	// Группы профиля заменяют группы основы целиком
+	base.QueueGroups = DefaultQueueGroups()
+	profile := DatabaseConfig{QueueGroups: []QueueGroup{{ID: "test", CallQueues: []string{"test-queue"}}}}
+	if got := overlayConfig(base, profile).QueueGroups; len(got) != 1 || got[0].ID != "test" {
+		t.Fatalf("profile queue groups = %+v", got)
+	}
+	if got := overlayConfig(base, DatabaseConfig{}).QueueGroups; len(got) != 3 {
+		t.Fatalf("inherited queue groups = %+v", got)
+	}
+}
+
+func TestQueueGroupSLTarget(t *testing.T) {
+	var defaults QueueRegistry
+	if got := defaults.Group("m10").CallSLTarget(); got != (SLTarget{ThresholdSeconds: 20, TargetPercent: 95}) {
+		t.Errorf("m10 call_sl = %+v", got)
+	}
+	if got := defaults.Group("aml").CallSLTarget(); got != (SLTarget{ThresholdSeconds: 30, TargetPercent: 95}) {
+		t.Errorf("aml call_sl = %+v", got)
+	}
+	if got := defaults.Group("m10").ChatSLTarget(); got != (SLTarget{ThresholdSeconds: 60, TargetPercent: 95}) {
+		t.Errorf("m10 chat_sl = %+v", got)
+	}
+
+	problems := queueGroupProblems([]QueueGroup{
+		{ID: "bad", CallQueues: []string{"m10"}, CallSL: SLTarget{ThresholdSeconds: -5}, ChatSL: SLTarget{TargetPercent: 120}, ShortAbandonSeconds: -1},
+	})
+	if len(problems) != 3 || !strings.Contains(problems[0], "call_sl") || !strings.Contains(problems[1], "chat_sl") ||
+		!strings.Contains(problems[2], "short_abandon_seconds") {
+		t.Errorf("problems = %q", problems)
+	}
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Ответы методов отчетов для frontend. Длительности (AHT, FRT, RT)
+// передаются числом секунд, SL — в процентах с двумя знаками.
+
+// DailyPoint значение метрики за день
+type DailyPoint struct {
+	Date  string  `json:"date"`
+	Value float64 `json:"value"`
+}
+
+// QueryTiming время выполнения одного запроса отчета
+type QueryTiming struct {
+	Query      string `json:"query"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// DailySeries дневные ряды метрик представления Daily
+type DailySeries struct {
+	Calls     []DailyPoint `json:"calls"`     // поступившие звонки
+	AHT       []DailyPoint `json:"aht"`       // средняя длительность звонка, сек
+	SL        []DailyPoint `json:"sl"`        // уровень сервиса, %
+	Abandoned []DailyPoint `json:"abandoned"` // брошенные звонки без коротких
+	// AbandonRate брошенные от поступивших без коротких брошенных, %
+	AbandonRate []DailyPoint `json:"abandon_rate"`
+	ASA         []DailyPoint `json:"asa"`      // среднее ожидание отвеченных звонков, сек
+	MaxWait     []DailyPoint `json:"max_wait"` // максимальное ожидание отвеченного звонка, сек
+	WaitP50     []DailyPoint `json:"wait_p50"` // медиана ожидания отвеченных звонков, сек
+	WaitP90     []DailyPoint `json:"wait_p90"` // 90-й перцентиль ожидания, сек
+	WaitP95     []DailyPoint `json:"wait_p95"` // 95-й перцентиль ожидания, сек
+	Chats       []DailyPoint `json:"chats"`    // входящие чаты
+	FRT         []DailyPoint `json:"frt"`      // время первого ответа в чате, сек
+	RT          []DailyPoint `json:"rt"`       // время решения чата, сек
+	Agents      []DailyPoint `json:"agents"`   // уникальные агенты в звонках и чатах
+	// Occupancy занятость агентов разговорами и чатами в часы их работы, %
+	Occupancy []DailyPoint `json:"occupancy"`
+
+	SLTarget SLTarget      `json:"sl_target"` // цель SL звонков группы очередей
+	Timings  []QueryTiming `json:"timings"`   // время запросов каждой метрики
+}
+
+// HourlyValues значения метрики за день по часам 0..23; час без данных равен нулю
+type HourlyValues struct {
+	Date  string    `json:"date"`
+	Hours []float64 `json:"hours"`
+}
+
+// HourlyMatrix значения одной метрики по дням и часам
+type HourlyMatrix struct {
+	Metric string         `json:"metric"`
+	Rows   []HourlyValues `json:"rows"`
+}
+
+// IntervalValues значения метрики за день по интервалам; интервал без данных равен нулю
+type IntervalValues struct {
+	Date   string    `json:"date"`
+	Values []float64 `json:"values"`
+}
+
+// IntervalMatrix значения одной метрики по дням и интервалам дня
+type IntervalMatrix struct {
+	Metric          string           `json:"metric"`
+	IntervalMinutes int              `json:"interval_minutes"`
+	Timezone        string           `json:"timezone"` // часовой пояс подписей интервалов
+	Labels          []string         `json:"labels"`   // начало интервала, ЧЧ:ММ
+	Rows            []IntervalValues `json:"rows"`
+	Average         []float64        `json:"average"` // средний день периода
+}
+
+// PeriodMetrics метрики за период, пересчитанные из сумм и количеств
+type PeriodMetrics struct {
+	Period    string  `json:"period"` // 2024-03-01, 2024-W09, 2024-03, 2024-Q1, 2024 или начало..конец
+	Start     string  `json:"start"`  // первый день периода в выбранном диапазоне
+	End       string  `json:"end"`    // последний день периода в выбранном диапазоне
+	Calls     int     `json:"calls"`
+	AHT       float64 `json:"aht"` // сек
+	SL        float64 `json:"sl"`  // %
+	Abandoned int     `json:"abandoned"`
+	// AbandonRate брошенные от поступивших без коротких брошенных, %
+	AbandonRate float64 `json:"abandon_rate"`
+	ASA         float64 `json:"asa"`      // сек
+	MaxWait     float64 `json:"max_wait"` // сек
+	WaitP50     float64 `json:"wait_p50"` // сек
+	WaitP90     float64 `json:"wait_p90"` // сек
+	WaitP95     float64 `json:"wait_p95"` // сек
+	Chats       int     `json:"chats"`
+	FRT         float64 `json:"frt"`    // сек
+	RT          float64 `json:"rt"`     // сек
+	Agents      int     `json:"agents"` // уникальные агенты за период
+	// Occupancy занятость агентов разговорами и чатами в часы их работы, %
+	Occupancy float64 `json:"occupancy"`
+}
+
+// PeriodReport метрики по периодам выбранной детализации
+type PeriodReport struct {
+	Granularity string          `json:"granularity"`
+	Periods     []PeriodMetrics `json:"periods"`
+	SLTarget    SLTarget        `json:"sl_target"` // цель SL звонков группы очередей
+	Timings     []QueryTiming   `json:"timings"`   // время запросов сумм и активности
+}
+
+// MonthlyCallDay звонки за день месяца
+type MonthlyCallDay struct {
+	Month           string  `json:"month"` // YYYY-MM
+	Day             int     `json:"day"`
+	TotalCalls      int     `json:"total_calls"`
+	AvgCallDuration float64 `json:"avg_call_duration"` // сек
+	SL              float64 `json:"sl"`
+	TotalAbandoned  int     `json:"total_abandoned"`
+	AbandonRate     float64 `json:"abandon_rate"` // %
+	ASA             float64 `json:"asa"`          // сек
+	MaxWait         float64 `json:"max_wait"`     // сек
+	WaitP50         float64 `json:"wait_p50"`     // сек
+	WaitP90         float64 `json:"wait_p90"`     // сек
+	WaitP95         float64 `json:"wait_p95"`     // сек
+	DistinctAgents  int     `json:"distinct_agents"`
+}
+
+// MonthlyChatDay чаты за день месяца
+type MonthlyChatDay struct {
+	Month             string  `json:"month"` // YYYY-MM
+	Day               int     `json:"day"`
+	TotalChats        int     `json:"total_chats"`
+	AvgChatFRT        float64 `json:"avg_chat_frt"`        // сек
+	ResolutionTimeAvg float64 `json:"resolution_time_avg"` // сек
+}
+
+// MonthlyReport дни месяца со звонками и с чатами
+type MonthlyReport struct {
+	Calls    []MonthlyCallDay `json:"calls"`
+	Chats    []MonthlyChatDay `json:"chats"`
+	SLTarget SLTarget         `json:"sl_target"` // цель SL звонков группы очередей
+}
+
+// ThresholdSL уровень сервиса при пороге Seconds
+type ThresholdSL struct {
+	Seconds int     `json:"seconds"`
+	SL      float64 `json:"sl"` // %
+}
+
+// ServiceLevelDay SL за день или за весь период по нескольким порогам
+type ServiceLevelDay struct {
+	Date       string        `json:"date"`  // пусто для итога периода
+	Total      int           `json:"total"` // отвеченные звонки или чаты с известным FRT
+	Thresholds []ThresholdSL `json:"thresholds"`
+	SL         float64       `json:"sl"`  // при пороге цели, %
+	Met        bool          `json:"met"` // SL не ниже цели; без обращений — false
+}
+
+// ServiceLevelSeries SL звонков или чатов по дням и итог периода
+type ServiceLevelSeries struct {
+	Target SLTarget          `json:"target"`
+	Days   []ServiceLevelDay `json:"days"`
+	Total  ServiceLevelDay   `json:"total"`
+}
+
+// ServiceLevelReport SL звонков по ожиданию в очереди и чатов по FRT
+type ServiceLevelReport struct {
+	Calls   ServiceLevelSeries `json:"calls"`
+	Chats   ServiceLevelSeries `json:"chats"`
+	Timings []QueryTiming      `json:"timings"`
+}
+
+// WaitHistogramRow брошенные звонки периода по интервалам ожидания
+type WaitHistogramRow struct {
+	Period    string `json:"period"` // 2024-03-01, 09:00 или 2024-03; пусто для итога
+	Bins      []int  `json:"bins"`   // все брошенные, включая короткие
+	Abandoned int    `json:"abandoned"`
+	Short     int    `json:"short"` // брошенные быстрее порога коротких
+}
+
+// AbandonWaitReport распределение ожидания брошенных звонков по дням,
+// часам дня или месяцам
+type AbandonWaitReport struct {
+	Granularity         string             `json:"granularity"`
+	ShortAbandonSeconds int                `json:"short_abandon_seconds"`
+	Bins                []string           `json:"bins"` // подписи интервалов ожидания
+	Rows                []WaitHistogramRow `json:"rows"`
+	Total               WaitHistogramRow   `json:"total"`
+}
+
+// DurationBin интервал гистограммы длительности
+type DurationBin struct {
+	Label string `json:"label"` // 0s-30s, ..., 30m+
+	From  int    `json:"from"`  // сек, включительно
+	To    int    `json:"to"`    // сек, не включительно; 0 — без верхней границы
+	Count int    `json:"count"`
+}
+
+// DurationDistribution распределение длительности за период: перцентили
+// и гистограмма вместо одного среднего
+type DurationDistribution struct {
+	Metric     string        `json:"metric"`      // aht, frt или rt
+	CapSeconds int           `json:"cap_seconds"` // порог отсечения выбросов; 0 — без отсечения
+	Count      int           `json:"count"`       // записи в расчете
+	Trimmed    int           `json:"trimmed"`     // отсеченные записи длиннее порога
+	Mean       float64       `json:"mean"`        // сек
+	Median     float64       `json:"median"`      // сек
+	P90        float64       `json:"p90"`         // сек
+	P95        float64       `json:"p95"`         // сек
+	Max        float64       `json:"max"`         // сек
+	Bins       []DurationBin `json:"bins"`
+}
+
+// AgentMetrics показатели агента за период
+type AgentMetrics struct {
+	Calls       int     `json:"calls"`        // отвеченные звонки
+	Chats       int     `json:"chats"`        // чаты, в которых агент ответил
+	Handled     int     `json:"handled"`      // звонки и чаты
+	AHT         float64 `json:"aht"`          // сек
+	FRT         float64 `json:"frt"`          // первый ответ агента в чате, сек
+	RT          float64 `json:"rt"`           // сек
+	ActiveHours float64 `json:"active_hours"` // часы с ответами в звонках или чатах
+}
+
+// AgentPerformance строка рейтинга агентов
+type AgentPerformance struct {
+	Rank    int          `json:"rank"` // место по метрике сортировки; 0 — у агента нет этой метрики
+	UserID  string       `json:"user_id"`
+	Name    string       `json:"name"` // full_name; user_id, если имени нет
+	Metrics AgentMetrics `json:"metrics"`
+	// VsMedian отклонение метрики сортировки от медианы команды, %
+	VsMedian float64 `json:"vs_median"`
+	// BetterThanMedian метрика сортировки лучше медианы команды: больше для
+	// объемов и часов, меньше для длительностей
+	BetterThanMedian bool `json:"better_than_median"`
+}
+
+// AgentMedian медиана команды по метрикам агентов; длительности — по агентам,
+// у которых они есть
+type AgentMedian struct {
+	Calls       float64 `json:"calls"`
+	Chats       float64 `json:"chats"`
+	Handled     float64 `json:"handled"`
+	AHT         float64 `json:"aht"`
+	FRT         float64 `json:"frt"`
+	RT          float64 `json:"rt"`
+	ActiveHours float64 `json:"active_hours"`
+}
+
+// AgentPerformanceReport рейтинг агентов группы очередей за период
+type AgentPerformanceReport struct {
+	SortBy  string             `json:"sort_by"`
+	Agents  []AgentPerformance `json:"agents"`
+	Median  AgentMedian        `json:"median"`  // медиана команды по каждой метрике
+	Timings []QueryTiming      `json:"timings"` // время запросов сумм, активности и имен
+}
+
+// AgentDay звонки и чаты агента за день
+type AgentDay struct {
+	Date  string `json:"date"`
+	Calls int    `json:"calls"`
+	Chats int    `json:"chats"`
+}
+
+// AgentHour звонки и чаты агента за час суток, суммарно за период
+type AgentHour struct {
+	Hour      int     `json:"hour"`
+	Calls     int     `json:"calls"`
+	Chats     int     `json:"chats"`
+	Occupancy float64 `json:"occupancy"` // занятость в этот час в дни работы, %
+}
+
+// AgentConcurrency одновременные чаты агента; чат занимает агента от
+// назначения до решения, чаты без времени решения не учитываются
+type AgentConcurrency struct {
+	Chats   int     `json:"chats"`   // чаты с известным временем решения
+	Max     int     `json:"max"`     // наибольшее число одновременных чатов
+	PeakAt  string  `json:"peak_at"` // первый момент максимума, YYYY-MM-DD HH:MM:SS
+	Average float64 `json:"average"` // среднее число чатов, пока открыт хотя бы один
+	// OverlapShare доля времени с открытыми чатами, когда их два и больше, %
+	OverlapShare float64 `json:"overlap_share"`
+}
+
+// AgentTopic топик обращений агента и его доля от всех обращений агента
+type AgentTopic struct {
+	Topic string  `json:"topic"`
+	Total int     `json:"total"`
+	Ratio float64 `json:"ratio"` // %
+}
+
+// AgentDetail показатели одного агента в группе очередей за период
+type AgentDetail struct {
+	UserID      string               `json:"user_id"`
+	Name        string               `json:"name"` // full_name; user_id, если имени нет
+	Metrics     AgentMetrics         `json:"metrics"`
+	Daily       []AgentDay           `json:"daily"`
+	Hourly      []AgentHour          `json:"hourly"` // все 24 часа
+	AHT         DurationDistribution `json:"aht"`
+	FRT         DurationDistribution `json:"frt"` // первый ответ агента
+	RT          DurationDistribution `json:"rt"`
+	Concurrency AgentConcurrency     `json:"concurrency"`
+	Occupancy   float64              `json:"occupancy"` // занятость в часы работы за период, %
+	Topics      []AgentTopic         `json:"topics"`    // пусто без MongoDB
+	Timings     []QueryTiming        `json:"timings"`   // время запросов звонков, чатов, топиков и имени
+}
+
+// ClassifierReport классификаторы по дням, топикам и субтопикам.
+// Type — call_classifiers, chat_classifiers, overall_classifiers или subtopics_daily.
+type ClassifierReport struct {
+	Type string             `json:"type"`
+	Data []ClassifierResult `json:"data"`
+}
+
+// TopicReport топики по дням с долей от всех обращений дня
+type TopicReport struct {
+	Type string        `json:"type"`
+	Data []TopicResult `json:"data"`
+}
+
+// roundTo округляет значение до указанного числа знаков после запятой
+func roundTo(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
+
+// Округление значений метрик в ответах frontend
+func asCount(value float64) float64   { return math.Round(value) }
+func asSeconds(value float64) float64 { return math.Round(value) }
+func asPercent(value float64) float64 { return roundTo(value, 2) }
+
+// dailyPoints округляет дневные значения репозитория для ответа
+func dailyPoints(values []DailyValue, round func(float64) float64) []DailyPoint {
+	points := make([]DailyPoint, 0, len(values))
+	for _, value := range values {
+		points = append(points, DailyPoint{Date: value.Date, Value: round(value.Value)})
+	}
+	return points
+}
+
+// intervalValues округляет значения по интервалам для ответа
+func intervalValues(rows []BucketRow, round func(float64) float64) []IntervalValues {
+	result := make([]IntervalValues, 0, len(rows))
+	for _, row := range rows {
+		values := make([]float64, len(row.Values))
+		for i, value := range row.Values {
+			if value != nil {
+				values[i] = round(*value)
+			}
+		}
+		result = append(result, IntervalValues{Date: row.Date, Values: values})
+	}
+	return result
+}
+
+// hourlyValues округляет почасовые значения для ответа
+func hourlyValues(rows []BucketRow, round func(float64) float64) []HourlyValues {
+	result := make([]HourlyValues, 0, len(rows))
+	for _, row := range intervalValues(rows, round) {
+		result = append(result, HourlyValues{Date: row.Date, Hours: row.Values})
+	}
+	return result
+}
+
+// periodDays число дней периода YYYY-MM-DD включительно
+func periodDays(startDate, endDate string) (int, error) {
+	start, err := time.Parse(dateLayout, startDate)
+	if err != nil {
+		return 0, fmt.Errorf("некорректная дата начала %q: %v", startDate, err)
+	}
+	end, err := time.Parse(dateLayout, endDate)
+	if err != nil {
+		return 0, fmt.Errorf("некорректная дата окончания %q: %v", endDate, err)
+	}
+	if end.Before(start) {
+		return 0, fmt.Errorf("дата окончания %s раньше даты начала %s", endDate, startDate)
+	}
+	return int(end.Sub(start).Hours()/24) + 1, nil
+}
+
+// monthDay разбирает дату отчета на месяц (YYYY-MM) и день месяца
+func monthDay(date string) (string, int, error) {
+	day, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return "", 0, fmt.Errorf("некорректная дата отчета %q: %v", date, err)
+	}
+	return day.Format("2006-01"), day.Day(), nil
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// dateLayout формат дат отчетов (YYYY-MM-DD), общий для MySQL и MongoDB
+const dateLayout = "2006-01-02"
+
+// reportTimezone часовой пояс отчетов: время в call_report и chat_report
+// хранится местным, даты обращений MongoDB переводятся в этот пояс
+const reportTimezone = "Asia/Baku"
+
+// slotMinutes ширина интервала в минутах, с которой репозитории отдают
+// активность агентов и ожидание звонков; интервалы отчетов кратны ей
+const slotMinutes = 15
+
+// DailyValue значение метрики за день. Value — количество, среднее
+// в секундах или процент, в зависимости от метода репозитория.
+type DailyValue struct {
+	Date  string
+	Value float64
+}
+
+// CallThresholds пороги группы очередей для метрик звонков, сек
+type CallThresholds struct {
+	SLSeconds           int // отвеченные с ожиданием не дольше входят в SL
+	ShortAbandonSeconds int // брошенные быстрее не считаются брошенными; 0 — считаются все
+}
+
+// CallTotals суммы и количества звонков за день. Из них метрики пересчитываются
+// для любого периода: AHT = DurationSum / DurationCount, SL = WithinSL / Answered,
+// процент брошенных = Abandoned / (Calls - ShortAbandoned).
+type CallTotals struct {
+	Date           string
+	Calls          int     // поступившие: отвеченные и брошенные
+	Answered       int     // отвеченные (type = 'in')
+	DurationSum    float64 // сумма длительности отвеченных, сек
+	DurationCount  int     // отвеченные с известной длительностью
+	WithinSL       int     // отвеченные с ожиданием не дольше порога SL
+	Abandoned      int     // брошенные, кроме коротких
+	ShortAbandoned int     // брошенные быстрее порога коротких
+}
+
+// add прибавляет суммы другого дня
+func (t *CallTotals) add(other CallTotals) {
+	t.Calls += other.Calls
+	t.Answered += other.Answered
+	t.DurationSum += other.DurationSum
+	t.DurationCount += other.DurationCount
+	t.WithinSL += other.WithinSL
+	t.Abandoned += other.Abandoned
+	t.ShortAbandoned += other.ShortAbandoned
+}
+
+// ChatTotals суммы и количества входящих чатов за день по дате назначения
+type ChatTotals struct {
+	Date     string
+	Chats    int
+	FRTSum   float64 // сумма времени первого ответа, сек
+	FRTCount int     // чаты с известным временем первого ответа
+	RTSum    float64 // сумма времени решения, сек
+	RTCount  int     // чаты с известным временем решения
+}
+
+// add прибавляет суммы другого дня
+func (t *ChatTotals) add(other ChatTotals) {
+	t.Chats += other.Chats
+	t.FRTSum += other.FRTSum
+	t.FRTCount += other.FRTCount
+	t.RTSum += other.RTSum
+	t.RTCount += other.RTCount
+}
+
+// SLCounts обращения за день и сколько из них уложились в каждый порог SL
+type SLCounts struct {
+	Date   string
+	Total  int   // отвеченные звонки или чаты с известным FRT
+	Within []int // уложившиеся в пороги, в порядке запрошенных порогов
+}
+
+// WaitCount отвеченные звонки интервала дня с одинаковым ожиданием в очереди
+type WaitCount struct {
+	Date   string
+	Minute int     // начало интервала шириной slotMinutes в минутах от полуночи
+	Wait   float64 // ожидание в очереди, сек
+	Count  int
+}
+
+// ValueCount записи с одинаковым значением длительности
+type ValueCount struct {
+	Value float64 // сек
+	Count int
+}
+
+// WaitBinCount брошенные звонки дня и часа поступления с ожиданием в интервале Bin
+type WaitBinCount struct {
+	Date  string
+	Hour  int
+	Bin   int // номер интервала ожидания по границам запроса
+	Count int
+}
+
+// AgentActivity факт работы агента в интервале дня шириной slotMinutes
+type AgentActivity struct {
+	Date   string
+	Minute int // начало интервала в минутах от полуночи
+	UserID string
+}
+
+// AgentCallTotals суммы звонков, на которые ответил агент, за период
+type AgentCallTotals struct {
+	UserID        string
+	Calls         int     // отвеченные звонки
+	DurationSum   float64 // сумма длительности разговора, сек
+	DurationCount int     // звонки с известной длительностью
+}
+
+// AgentChatTotals суммы чатов, в которых ответил агент, за период
+type AgentChatTotals struct {
+	UserID   string
+	Chats    int
+	FRTSum   float64 // сумма времени первого ответа агента, сек
+	FRTCount int     // чаты с известным временем первого ответа агента
+	RTSum    float64 // сумма времени решения, сек
+	RTCount  int     // чаты с известным временем решения
+}
+
+// AgentCall звонок, на который ответил агент; время ответа — дата и секунды от полуночи
+type AgentCall struct {
+	Date        string
+	Second      int
+	Duration    float64 // длительность разговора, сек
+	HasDuration bool    // длительность известна
+}
+
+// AgentChat чат, в котором ответил агент; время назначения — дата и секунды от полуночи
+type AgentChat struct {
+	Date   string
+	Second int
+	FRT    float64 // первый ответ агента, сек
+	RT     float64 // время решения, сек
+	HasRT  bool    // время решения известно
+}
+
+// HandleSpan обработка звонка или чата агентом: начало — дата и секунды
+// от полуночи, длительность — время разговора или открытого чата
+type HandleSpan struct {
+	UserID   string
+	Date     string
+	Second   int
+	Duration float64 // сек
+}
+
+// TopicCount количество обращений топика за период
+type TopicCount struct {
+	Topic string `bson:"topic"`
+	Total int    `bson:"total"`
+}
+
+// QueueUsage очередь или канал, встреченные за период, с количеством
+// записей и датами первой и последней записи (YYYY-MM-DD)
+type QueueUsage struct {
+	QueueName string
+	Count     int
+	FirstSeen string
+	LastSeen  string
+}
+
+// CallReportRepository агрегаты по таблице звонков call_report.
+// Даты периода — YYYY-MM-DD включительно, queues — имена очередей.
+type CallReportRepository interface {
+	// DailyCalls поступившие звонки (отвеченные и брошенные) по дням
+	DailyCalls(ctx context.Context, startDate, endDate string, queues []string) ([]DailyValue, error)
+	// DailyAHT средняя длительность отвеченного звонка в секундах по дням
+	DailyAHT(ctx context.Context, startDate, endDate string, queues []string) ([]DailyValue, error)
+	// DailySL процент отвеченных звонков, ожидавших не дольше thresholdSeconds, по дням
+	DailySL(ctx context.Context, startDate, endDate string, thresholdSeconds int, queues []string) ([]DailyValue, error)
+	// DailyAbandoned брошенные звонки по дням, кроме брошенных быстрее shortAbandonSeconds
+	DailyAbandoned(ctx context.Context, startDate, endDate string, shortAbandonSeconds int, queues []string) ([]DailyValue, error)
+	// DailyAbandonRate процент брошенных от поступивших по дням; короткие
+	// брошенные (быстрее shortAbandonSeconds) не входят ни в одно из чисел
+	DailyAbandonRate(ctx context.Context, startDate, endDate string, shortAbandonSeconds int, queues []string) ([]DailyValue, error)
+	// DailyTotals суммы и количества звонков по дням поступления в очередь
+	// с порогами группы очередей
+	DailyTotals(ctx context.Context, startDate, endDate string, thresholds CallThresholds, queues []string) ([]CallTotals, error)
+	// DailySLCounts отвеченные звонки по дням и сколько из них ожидали
+	// не дольше каждого из порогов thresholds (сек)
+	DailySLCounts(ctx context.Context, startDate, endDate string, thresholds []int, queues []string) ([]SLCounts, error)
+
+	// Buckets метрика по дням и интервалам шириной width минут:
+	//   calls — отвеченные звонки по времени поступления в очередь;
+	//   aht — средняя длительность звонка по времени ответа;
+	//   sl — процент звонков, ожидавших не дольше порога SL, по времени поступления;
+	//   abandoned — брошенные звонки, кроме коротких, по времени поступления;
+	//   abandon_rate — процент брошенных от поступивших без коротких брошенных.
+	// Интервалы без записей не возвращаются.
+	Buckets(ctx context.Context, metric, startDate, endDate string, width int, thresholds CallThresholds, queues []string) ([]BucketValue, error)
+
+	// AnswerWaits отвеченные звонки по дням, интервалам времени поступления
+	// и ожиданию в очереди; из них считаются ASA, максимум и перцентили ожидания
+	AnswerWaits(ctx context.Context, startDate, endDate string, queues []string) ([]WaitCount, error)
+
+	// Durations отвеченные звонки за период по длительности разговора
+	Durations(ctx context.Context, startDate, endDate string, queues []string) ([]ValueCount, error)
+
+	// AbandonWaits брошенные звонки по дням, часам поступления и интервалам
+	// ожидания: интервал i — ожидание от edges[i-1] включительно до edges[i]
+	// секунд, последний — от последней границы и дольше (как histogramBin)
+	AbandonWaits(ctx context.Context, startDate, endDate string, edges []int, queues []string) ([]WaitBinCount, error)
+
+	// AgentActivity агенты, отвечавшие на звонки, по дням и интервалам времени ответа
+	AgentActivity(ctx context.Context, startDate, endDate string, queues []string) ([]AgentActivity, error)
+	// AgentTotals суммы отвеченных звонков по агентам за период, по дате ответа
+	AgentTotals(ctx context.Context, startDate, endDate string, queues []string) ([]AgentCallTotals, error)
+	// HandleSpans разговоры агентов по отвеченным звонкам: от ответа
+	// в течение длительности звонка, по дате ответа
+	HandleSpans(ctx context.Context, startDate, endDate string, queues []string) ([]HandleSpan, error)
+	// AgentCalls звонки, на которые ответил агент userID, по дате ответа
+	AgentCalls(ctx context.Context, userID, startDate, endDate string, queues []string) ([]AgentCall, error)
+
+	// QueueUsage все очереди звонков за период, по дате поступления в очередь
+	QueueUsage(ctx context.Context, startDate, endDate string) ([]QueueUsage, error)
+}
+
+// ChatReportRepository агрегаты по таблице чатов chat_report.
+// channels — каналы чатов (столбец queue_name), как у очередей звонков.
+type ChatReportRepository interface {
+	// DailyChats входящие чаты по дням назначения
+	DailyChats(ctx context.Context, startDate, endDate string, channels []string) ([]DailyValue, error)
+	// DailyFRT среднее время первого ответа в секундах по дням назначения
+	DailyFRT(ctx context.Context, startDate, endDate string, channels []string) ([]DailyValue, error)
+	// DailyRT среднее время решения в секундах по дням назначения
+	DailyRT(ctx context.Context, startDate, endDate string, channels []string) ([]DailyValue, error)
+	// DailyTotals суммы и количества входящих чатов по дням назначения
+	DailyTotals(ctx context.Context, startDate, endDate string, channels []string) ([]ChatTotals, error)
+	// DailySLCounts входящие чаты с известным FRT по дням назначения и сколько
+	// из них получили первый ответ не позже каждого из порогов thresholds (сек)
+	DailySLCounts(ctx context.Context, startDate, endDate string, thresholds []int, channels []string) ([]SLCounts, error)
+
+	// Buckets метрика входящих чатов по дням и интервалам шириной width минут:
+	//   chats — чаты по времени создания;
+	//   frt — среднее время первого ответа по времени назначения;
+	//   rt — среднее время решения по времени назначения.
+	Buckets(ctx context.Context, metric, startDate, endDate string, width int, channels []string) ([]BucketValue, error)
+
+	// Durations входящие чаты за период по дате назначения и значению
+	// длительности: frt — время первого ответа, rt — время решения
+	Durations(ctx context.Context, metric, startDate, endDate string, channels []string) ([]ValueCount, error)
+
+	// AgentActivity агенты, ответившие в чатах, по дням и интервалам времени назначения
+	AgentActivity(ctx context.Context, startDate, endDate string, channels []string) ([]AgentActivity, error)
+	// AgentTotals суммы чатов, в которых ответил агент, за период, по дате назначения
+	AgentTotals(ctx context.Context, startDate, endDate string, channels []string) ([]AgentChatTotals, error)
+	// HandleSpans открытые чаты агентов: от назначения до решения
+	// (resolution_time_total), по дате назначения
+	HandleSpans(ctx context.Context, startDate, endDate string, channels []string) ([]HandleSpan, error)
+	// AgentChats чаты, в которых ответил агент userID, по дате назначения
+	AgentChats(ctx context.Context, userID, startDate, endDate string, channels []string) ([]AgentChat, error)
+
+	// QueueUsage все каналы чатов за период, по дате создания
+	QueueUsage(ctx context.Context, startDate, endDate string) ([]QueueUsage, error)
+}
+
+// ClassifierRepository агрегаты классификаторов обращений из MongoDB.
+// Дата обращения считается в часовом поясе Asia/Baku.
+type ClassifierRepository interface {
+	// Classifiers количество обращений по дням, топикам и субтопикам
+	Classifiers(ctx context.Context, startDate, endDate string, queues []string) ([]ClassifierResult, error)
+	// Topics количество обращений по топикам с долей от всех обращений дня
+	Topics(ctx context.Context, startDate, endDate string, queues []string) ([]TopicResult, error)
+	// AvailableTopics отсортированный список топиков за период
+	AvailableTopics(ctx context.Context, startDate, endDate string, queues []string) ([]string, error)
+	// Subtopics субтопики выбранного топика по дням
+	Subtopics(ctx context.Context, startDate, endDate string, queues []string, topic string) ([]ClassifierResult, error)
+	// AgentTopics количество обращений агента userID (поле userId) по топикам
+	// за период, по убыванию количества
+	AgentTopics(ctx context.Context, userID, startDate, endDate string, queues []string) ([]TopicCount, error)
+	// QueueUsage все значения queueName обращений за период
+	QueueUsage(ctx context.Context, startDate, endDate string) ([]QueueUsage, error)
+}
+
+// AgentRepository справочник агентов
+type AgentRepository interface {
+	// Names отображаемые имена агентов по user_id; агентов без имени в ответе нет
+	Names(ctx context.Context, userIDs []string) (map[string]string, error)
+}
+
+// Repositories репозитории одной сессии подключения и группы очередей ее профиля.
+// Репозиторий равен nil, если соответствующая база не подключена.
+type Repositories struct {
+	Calls       CallReportRepository
+	Chats       ChatReportRepository
+	Classifiers ClassifierRepository
+	Agents      AgentRepository
+	Queues      QueueRegistry
+	// MaxQueries сколько запросов к MySQL можно выполнять параллельно
+	// (размер пула соединений); 0 — без ограничения
+	MaxQueries int
+}
+
+// RepositorySource выдает репозитории и функцию их освобождения
+type RepositorySource interface {
+	Repositories() (Repositories, func())
+}
+
+// Repositories возвращает репозитории поверх соединений сессии
+func (s *Session) Repositories() Repositories {
+	repos := Repositories{Queues: s.Queues}
+	if s.MySQL != nil {
+		repos.Calls = &mysqlCallReports{db: s.MySQL}
+		repos.Chats = &mysqlChatReports{db: s.MySQL}
+		repos.Agents = &mysqlAgents{db: s.MySQL}
+		repos.MaxQueries = s.MySQL.Stats().MaxOpenConnections
+	}
+	if s.MongoDB != nil {
+		repos.Classifiers = &mongoClassifiers{db: s.MongoDB}
+	}
+	return repos
+}
+
+// Repositories закрепляет текущие соединения и возвращает репозитории над ними.
+// Все репозитории принадлежат одному профилю, пока не вызвана функция освобождения.
+func (ds *DatabaseService) Repositories() (Repositories, func()) {
+	session := ds.Session()
+	return session.Repositories(), session.Close
+}
+
+// requireMySQL проверяет, что репозитории MySQL доступны
+func (r Repositories) requireMySQL() error {
+	if r.Calls == nil || r.Chats == nil {
+		return fmt.Errorf("MySQL соединение не установлено")
+	}
+	return nil
+}
+
+// requireMongoDB проверяет, что репозиторий MongoDB доступен
+func (r Repositories) requireMongoDB() error {
+	if r.Classifiers == nil {
+		return fmt.Errorf("MongoDB соединение не установлено")
+	}
+	return nil
+}
+
+// periodBounds переводит даты периода в границы для BETWEEN
+func periodBounds(startDate, endDate string) (string, string) {
+	return startDate + " 00:00:00", endDate + " 23:59:59"
+}
+
+// inCondition строит условие column IN (?, ...) и его параметры
+func inCondition(column string, values []string) (string, []interface{}) {
+	if len(values) == 0 {
+		return "FALSE", nil
+	}
+	params := make([]interface{}, len(values))
+	for i, value := range values {
+		params[i] = value
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	return fmt.Sprintf("%s IN (%s)", column, placeholders), params
+}
+
+// namedQuery запрос отчета с именем для замера времени
+type namedQuery struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// runQueries выполняет запросы параллельно, не больше limit одновременно
+// (limit <= 0 — без ограничения). Первая ошибка отменяет контекст остальных
+// запросов. Время выполнения возвращается в порядке queries.
+func runQueries(ctx context.Context, limit int, queries []namedQuery) ([]QueryTiming, error) {
+	group, ctx := errgroup.WithContext(ctx)
+	if limit > 0 {
+		group.SetLimit(limit)
+	}
+
+	timings := make([]QueryTiming, len(queries))
+	for i, query := range queries {
+		group.Go(func() error {
+			started := time.Now()
+			err := query.run(ctx)
+			timings[i] = QueryTiming{Query: query.name, DurationMs: time.Since(started).Milliseconds()}
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return timings, nil
+}
@@ -0,0 +1,775 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Фейковые репозитории считают те же агрегаты, что и SQL/MongoDB,
+// но по записям в памяти
+
+// fakeCall строка call_report
+type fakeCall struct {
+	EnterQueue time.Time
+	Answer     time.Time // нулевое время — звонок не отвечен
+	Type       string    // in | abandon
+	Queue      string
+	Duration   float64
+	Wait       float64
+	UserID     string
+}
+
+// fakeChat строка chat_report
+type fakeChat struct {
+	Created  time.Time
+	Assign   time.Time
+	Type     string
+	Channel  string
+	FRT      float64
+	RT       float64
+	AgentFRT float64
+	UserID   string
+}
+
+// fakeRequest документ коллекции request; дата уже в часовом поясе Asia/Baku
+type fakeRequest struct {
+	ReportDate string
+	Type       string
+	Queue      string
+	UserID     string
+	Paths      []string
+}
+
+// fakeSource отдает одни и те же репозитории на каждый запрос
+type fakeSource Repositories
+
+func (f fakeSource) Repositories() (Repositories, func()) {
+	return Repositories(f), func() {}
+}
+
+func newFakeSource(calls []fakeCall, chats []fakeChat, requests []fakeRequest) fakeSource {
+	return fakeSource{
+		Calls:       &fakeCallReports{calls: calls},
+		Chats:       &fakeChatReports{chats: chats},
+		Classifiers: &fakeClassifiers{requests: requests},
+		Agents:      &fakeAgents{names: testAgentNames},
+	}
+}
+
+func inPeriod(t time.Time, startDate, endDate string) bool {
+	if t.IsZero() {
+		return false
+	}
+	date := t.Format(dateLayout)
+	return date >= startDate && date <= endDate
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Агрегаты группы записей; false означает NULL
+func count[T any](records []T) (float64, bool) { return float64(len(records)), true }
+
+func average[T any](value func(T) float64) func([]T) (float64, bool) {
+	return func(records []T) (float64, bool) {
+		if len(records) == 0 {
+			return 0, false
+		}
+		var sum float64
+		for _, record := range records {
+			sum += value(record)
+		}
+		return sum / float64(len(records)), true
+	}
+}
+
+func serviceLevel(thresholdSeconds int) func([]fakeCall) (float64, bool) {
+	return func(calls []fakeCall) (float64, bool) {
+		if len(calls) == 0 {
+			return 0, false
+		}
+		var fast float64
+		for _, call := range calls {
+			if call.Wait <= float64(thresholdSeconds) {
+				fast++
+			}
+		}
+		return fast / float64(len(calls)) * 100, true
+	}
+}
+
+// slCounts аналог SUM(CASE WHEN value <= порог ...) по дням
+func slCounts[T any](records []T, date func(T) time.Time, value func(T) float64, thresholds []int) []SLCounts {
+	byDate := make(map[string]*SLCounts)
+	for _, record := range records {
+		day := date(record).Format(dateLayout)
+		if byDate[day] == nil {
+			byDate[day] = &SLCounts{Date: day, Within: make([]int, len(thresholds))}
+		}
+		counts := byDate[day]
+		counts.Total++
+		for i, threshold := range thresholds {
+			if value(record) <= float64(threshold) {
+				counts.Within[i]++
+			}
+		}
+	}
+
+	result := make([]SLCounts, 0, len(byDate))
+	for _, day := range sortedKeys(byDate) {
+		result = append(result, *byDate[day])
+	}
+	return result
+}
+
+// valueCounts аналог GROUP BY value ORDER BY value
+func valueCounts[T any](records []T, value func(T) float64) []ValueCount {
+	counts := make(map[float64]int)
+	for _, record := range records {
+		counts[value(record)]++
+	}
+	result := make([]ValueCount, 0, len(counts))
+	for v, count := range counts {
+		result = append(result, ValueCount{Value: v, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Value < result[j].Value })
+	return result
+}
+
+// groupDaily аналог GROUP BY DATE(...) ORDER BY DATE(...)
+func groupDaily[T any](records []T, date func(T) time.Time, aggregate func([]T) (float64, bool)) []DailyValue {
+	byDate := make(map[string][]T)
+	for _, record := range records {
+		day := date(record).Format(dateLayout)
+		byDate[day] = append(byDate[day], record)
+	}
+
+	values := make([]DailyValue, 0)
+	for _, day := range sortedKeys(byDate) {
+		if value, ok := aggregate(byDate[day]); ok {
+			values = append(values, DailyValue{Date: day, Value: value})
+		}
+	}
+	return values
+}
+
+// groupBuckets аналог GROUP BY DATE(...), интервал шириной width минут
+func groupBuckets[T any](records []T, date func(T) time.Time, width int, aggregate func([]T) (float64, bool)) []BucketValue {
+	type dayBucket struct {
+		date   string
+		bucket int
+	}
+	groups := make(map[dayBucket][]T)
+	for _, record := range records {
+		t := date(record)
+		key := dayBucket{t.Format(dateLayout), (t.Hour()*60 + t.Minute()) / width}
+		groups[key] = append(groups[key], record)
+	}
+
+	keys := make([]dayBucket, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].date != keys[j].date {
+			return keys[i].date < keys[j].date
+		}
+		return keys[i].bucket < keys[j].bucket
+	})
+
+	values := make([]BucketValue, 0, len(keys))
+	for _, key := range keys {
+		if value, ok := aggregate(groups[key]); ok {
+			values = append(values, BucketValue{Date: key.date, Bucket: key.bucket, Value: value})
+		}
+	}
+	return values
+}
+
+func distinctActivity[T any](records []T, date func(T) time.Time, user func(T) string) []AgentActivity {
+	seen := make(map[AgentActivity]bool)
+	activity := make([]AgentActivity, 0)
+	for _, record := range records {
+		t := date(record)
+		minute := t.Hour()*60 + t.Minute()
+		entry := AgentActivity{Date: t.Format(dateLayout), Minute: minute - minute%slotMinutes, UserID: user(record)}
+		if !seen[entry] {
+			seen[entry] = true
+			activity = append(activity, entry)
+		}
+	}
+	return activity
+}
+
+type fakeCallReports struct {
+	calls []fakeCall
+}
+
+func (r *fakeCallReports) filter(types []string, queues []string, date func(fakeCall) time.Time, startDate, endDate string) []fakeCall {
+	var result []fakeCall
+	for _, call := range r.calls {
+		if contains(types, call.Type) && contains(queues, call.Queue) && inPeriod(date(call), startDate, endDate) {
+			result = append(result, call)
+		}
+	}
+	return result
+}
+
+func enterQueue(c fakeCall) time.Time { return c.EnterQueue }
+func answer(c fakeCall) time.Time     { return c.Answer }
+
+func (r *fakeCallReports) DailyCalls(_ context.Context, startDate, endDate string, queues []string) ([]DailyValue, error) {
+	calls := r.filter([]string{"in", "abandon"}, queues, enterQueue, startDate, endDate)
+	return groupDaily(calls, enterQueue, count[fakeCall]), nil
+}
+
+func (r *fakeCallReports) DailyAHT(_ context.Context, startDate, endDate string, queues []string) ([]DailyValue, error) {
+	calls := r.filter([]string{"in"}, queues, enterQueue, startDate, endDate)
+	return groupDaily(calls, enterQueue, average(func(c fakeCall) float64 { return c.Duration })), nil
+}
+
+func (r *fakeCallReports) DailySL(_ context.Context, startDate, endDate string, thresholdSeconds int, queues []string) ([]DailyValue, error) {
+	calls := r.filter([]string{"in"}, queues, enterQueue, startDate, endDate)
+	return groupDaily(calls, enterQueue, serviceLevel(thresholdSeconds)), nil
+}
+
+// offered поступившие звонки без брошенных быстрее shortAbandonSeconds
+func (r *fakeCallReports) offered(types []string, queues []string, shortAbandonSeconds int, startDate, endDate string) []fakeCall {
+	var result []fakeCall
+	for _, call := range r.filter(types, queues, enterQueue, startDate, endDate) {
+		if call.Type != "abandon" || call.Wait >= float64(shortAbandonSeconds) {
+			result = append(result, call)
+		}
+	}
+	return result
+}
+
+func abandonRate(calls []fakeCall) (float64, bool) {
+	if len(calls) == 0 {
+		return 0, false
+	}
+	var abandoned float64
+	for _, call := range calls {
+		if call.Type == "abandon" {
+			abandoned++
+		}
+	}
+	return abandoned / float64(len(calls)) * 100, true
+}
+
+func (r *fakeCallReports) DailyAbandoned(_ context.Context, startDate, endDate string, shortAbandonSeconds int, queues []string) ([]DailyValue, error) {
+	calls := r.offered([]string{"abandon"}, queues, shortAbandonSeconds, startDate, endDate)
+	return groupDaily(calls, enterQueue, count[fakeCall]), nil
+}
+
+func (r *fakeCallReports) DailyAbandonRate(_ context.Context, startDate, endDate string, shortAbandonSeconds int, queues []string) ([]DailyValue, error) {
+	calls := r.offered([]string{"in", "abandon"}, queues, shortAbandonSeconds, startDate, endDate)
+	return groupDaily(calls, enterQueue, abandonRate), nil
+}
+
+func (r *fakeCallReports) DailyTotals(_ context.Context, startDate, endDate string, thresholds CallThresholds, queues []string) ([]CallTotals, error) {
+	byDate := make(map[string]*CallTotals)
+	for _, call := range r.filter([]string{"in", "abandon"}, queues, enterQueue, startDate, endDate) {
+		date := day(call.EnterQueue)
+		if byDate[date] == nil {
+			byDate[date] = &CallTotals{Date: date}
+		}
+		totals := byDate[date]
+		totals.Calls++
+		if call.Type == "abandon" {
+			if call.Wait < float64(thresholds.ShortAbandonSeconds) {
+				totals.ShortAbandoned++
+			} else {
+				totals.Abandoned++
+			}
+			continue
+		}
+		totals.Answered++
+		totals.DurationSum += call.Duration
+		totals.DurationCount++
+		if call.Wait <= float64(thresholds.SLSeconds) {
+			totals.WithinSL++
+		}
+	}
+
+	result := make([]CallTotals, 0, len(byDate))
+	for _, date := range sortedKeys(byDate) {
+		result = append(result, *byDate[date])
+	}
+	return result, nil
+}
+
+func (r *fakeCallReports) DailySLCounts(_ context.Context, startDate, endDate string, thresholds []int, queues []string) ([]SLCounts, error) {
+	calls := r.filter([]string{"in"}, queues, enterQueue, startDate, endDate)
+	return slCounts(calls, enterQueue, func(c fakeCall) float64 { return c.Wait }, thresholds), nil
+}
+
+func (r *fakeCallReports) Buckets(_ context.Context, metric, startDate, endDate string, width int, thresholds CallThresholds, queues []string) ([]BucketValue, error) {
+	if err := checkBucketWidth(width); err != nil {
+		return nil, err
+	}
+	switch metric {
+	case "calls":
+		return groupBuckets(r.filter([]string{"in"}, queues, enterQueue, startDate, endDate), enterQueue, width, count[fakeCall]), nil
+	case "aht":
+		return groupBuckets(r.filter([]string{"in"}, queues, answer, startDate, endDate), answer, width, average(func(c fakeCall) float64 { return c.Duration })), nil
+	case "sl":
+		return groupBuckets(r.filter([]string{"in"}, queues, enterQueue, startDate, endDate), enterQueue, width, serviceLevel(thresholds.SLSeconds)), nil
+	case "abandoned":
+		return groupBuckets(r.offered([]string{"abandon"}, queues, thresholds.ShortAbandonSeconds, startDate, endDate), enterQueue, width, count[fakeCall]), nil
+	case "abandon_rate":
+		return groupBuckets(r.offered([]string{"in", "abandon"}, queues, thresholds.ShortAbandonSeconds, startDate, endDate), enterQueue, width, abandonRate), nil
+	}
+	return nil, fmt.Errorf("неподдерживаемая метрика звонков: %s", metric)
+}
+
+func (r *fakeCallReports) Durations(_ context.Context, startDate, endDate string, queues []string) ([]ValueCount, error) {
+	calls := r.filter([]string{"in"}, queues, enterQueue, startDate, endDate)
+	return valueCounts(calls, func(c fakeCall) float64 { return c.Duration }), nil
+}
+
+func (r *fakeCallReports) AgentTotals(_ context.Context, startDate, endDate string, queues []string) ([]AgentCallTotals, error) {
+	byAgent := make(map[string]*AgentCallTotals)
+	for _, call := range r.filter([]string{"in"}, queues, answer, startDate, endDate) {
+		if call.UserID == "" {
+			continue
+		}
+		agent := byAgent[call.UserID]
+		if agent == nil {
+			agent = &AgentCallTotals{UserID: call.UserID}
+			byAgent[call.UserID] = agent
+		}
+		agent.Calls++
+		agent.DurationSum += call.Duration
+		agent.DurationCount++
+	}
+
+	totals := make([]AgentCallTotals, 0, len(byAgent))
+	for _, userID := range sortedKeys(byAgent) {
+		totals = append(totals, *byAgent[userID])
+	}
+	return totals, nil
+}
+
+// secondOfDay секунды от полуночи, как TIME_TO_SEC
+func secondOfDay(t time.Time) int {
+	return t.Hour()*3600 + t.Minute()*60 + t.Second()
+}
+
+func (r *fakeCallReports) HandleSpans(_ context.Context, startDate, endDate string, queues []string) ([]HandleSpan, error) {
+	spans := make([]HandleSpan, 0)
+	for _, call := range r.filter([]string{"in"}, queues, answer, startDate, endDate) {
+		if call.UserID != "" && call.Duration > 0 {
+			spans = append(spans, HandleSpan{UserID: call.UserID, Date: call.Answer.Format(dateLayout), Second: secondOfDay(call.Answer), Duration: call.Duration})
+		}
+	}
+	return spans, nil
+}
+
+func (r *fakeCallReports) AgentCalls(_ context.Context, userID, startDate, endDate string, queues []string) ([]AgentCall, error) {
+	calls := make([]AgentCall, 0)
+	for _, call := range r.filter([]string{"in"}, queues, answer, startDate, endDate) {
+		if call.UserID == userID {
+			calls = append(calls, AgentCall{Date: call.Answer.Format(dateLayout), Second: secondOfDay(call.Answer), Duration: call.Duration, HasDuration: true})
+		}
+	}
+	return calls, nil
+}
+
+func (r *fakeCallReports) AnswerWaits(_ context.Context, startDate, endDate string, queues []string) ([]WaitCount, error) {
+	type key struct {
+		date   string
+		minute int
+		wait   float64
+	}
+	counts := make(map[key]int)
+	for _, call := range r.filter([]string{"in"}, queues, enterQueue, startDate, endDate) {
+		minute := call.EnterQueue.Hour()*60 + call.EnterQueue.Minute()
+		counts[key{day(call.EnterQueue), minute - minute%slotMinutes, call.Wait}]++
+	}
+
+	result := make([]WaitCount, 0, len(counts))
+	for k, count := range counts {
+		result = append(result, WaitCount{Date: k.date, Minute: k.minute, Wait: k.wait, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Minute != b.Minute {
+			return a.Minute < b.Minute
+		}
+		return a.Wait < b.Wait
+	})
+	return result, nil
+}
+
+func (r *fakeCallReports) AbandonWaits(_ context.Context, startDate, endDate string, edges []int, queues []string) ([]WaitBinCount, error) {
+	type key struct {
+		date      string
+		hour, bin int
+	}
+	counts := make(map[key]int)
+	for _, call := range r.filter([]string{"abandon"}, queues, enterQueue, startDate, endDate) {
+		counts[key{day(call.EnterQueue), call.EnterQueue.Hour(), histogramBin(edges, call.Wait)}]++
+	}
+
+	result := make([]WaitBinCount, 0, len(counts))
+	for k, count := range counts {
+		result = append(result, WaitBinCount{Date: k.date, Hour: k.hour, Bin: k.bin, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Hour != b.Hour {
+			return a.Hour < b.Hour
+		}
+		return a.Bin < b.Bin
+	})
+	return result, nil
+}
+
+func (r *fakeCallReports) AgentActivity(_ context.Context, startDate, endDate string, queues []string) ([]AgentActivity, error) {
+	calls := r.filter([]string{"in"}, queues, answer, startDate, endDate)
+	return distinctActivity(calls, answer, func(c fakeCall) string { return c.UserID }), nil
+}
+
+// queueUsage считает записи очередей за период и их первую и последнюю дату
+func queueUsage[T any](records []T, queue func(T) string, date func(T) string, startDate, endDate string) []QueueUsage {
+	byQueue := make(map[string]*QueueUsage)
+	for _, record := range records {
+		day := date(record)
+		if day == "" || day < startDate || day > endDate {
+			continue
+		}
+		usage := byQueue[queue(record)]
+		if usage == nil {
+			usage = &QueueUsage{QueueName: queue(record), FirstSeen: day, LastSeen: day}
+			byQueue[queue(record)] = usage
+		}
+		usage.Count++
+		usage.FirstSeen = min(usage.FirstSeen, day)
+		usage.LastSeen = max(usage.LastSeen, day)
+	}
+
+	result := make([]QueueUsage, 0, len(byQueue))
+	for _, name := range sortedKeys(byQueue) {
+		result = append(result, *byQueue[name])
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Count > result[j].Count })
+	return result
+}
+
+// day дата записи в формате отчетов; пустая для нулевого времени
+func day(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+func (r *fakeCallReports) QueueUsage(_ context.Context, startDate, endDate string) ([]QueueUsage, error) {
+	return queueUsage(r.calls, func(c fakeCall) string { return c.Queue },
+		func(c fakeCall) string { return day(c.EnterQueue) }, startDate, endDate), nil
+}
+
+type fakeChatReports struct {
+	chats []fakeChat
+}
+
+func created(c fakeChat) time.Time  { return c.Created }
+func assigned(c fakeChat) time.Time { return c.Assign }
+
+// incoming входящие чаты каналов, у которых дата попадает в период
+func (r *fakeChatReports) incoming(channels []string, date func(fakeChat) time.Time, startDate, endDate string) []fakeChat {
+	var result []fakeChat
+	for _, chat := range r.chats {
+		if chat.Type == "in" && contains(channels, chat.Channel) && inPeriod(date(chat), startDate, endDate) {
+			result = append(result, chat)
+		}
+	}
+	return result
+}
+
+func (r *fakeChatReports) DailyChats(_ context.Context, startDate, endDate string, channels []string) ([]DailyValue, error) {
+	return groupDaily(r.incoming(channels, assigned, startDate, endDate), assigned, count[fakeChat]), nil
+}
+
+func (r *fakeChatReports) DailyFRT(_ context.Context, startDate, endDate string, channels []string) ([]DailyValue, error) {
+	return groupDaily(r.incoming(channels, assigned, startDate, endDate), assigned, average(func(c fakeChat) float64 { return c.FRT })), nil
+}
+
+func (r *fakeChatReports) DailyRT(_ context.Context, startDate, endDate string, channels []string) ([]DailyValue, error) {
+	return groupDaily(r.incoming(channels, assigned, startDate, endDate), assigned, average(func(c fakeChat) float64 { return c.RT })), nil
+}
+
+func (r *fakeChatReports) DailyTotals(_ context.Context, startDate, endDate string, channels []string) ([]ChatTotals, error) {
+	byDate := make(map[string]*ChatTotals)
+	for _, chat := range r.incoming(channels, assigned, startDate, endDate) {
+		date := day(chat.Assign)
+		if byDate[date] == nil {
+			byDate[date] = &ChatTotals{Date: date}
+		}
+		totals := byDate[date]
+		totals.Chats++
+		totals.FRTSum += chat.FRT
+		totals.FRTCount++
+		totals.RTSum += chat.RT
+		totals.RTCount++
+	}
+
+	result := make([]ChatTotals, 0, len(byDate))
+	for _, date := range sortedKeys(byDate) {
+		result = append(result, *byDate[date])
+	}
+	return result, nil
+}
+
+func (r *fakeChatReports) DailySLCounts(_ context.Context, startDate, endDate string, thresholds []int, channels []string) ([]SLCounts, error) {
+	chats := r.incoming(channels, assigned, startDate, endDate)
+	return slCounts(chats, assigned, func(c fakeChat) float64 { return c.FRT }, thresholds), nil
+}
+
+func (r *fakeChatReports) Buckets(_ context.Context, metric, startDate, endDate string, width int, channels []string) ([]BucketValue, error) {
+	if err := checkBucketWidth(width); err != nil {
+		return nil, err
+	}
+	switch metric {
+	case "chats":
+		return groupBuckets(r.incoming(channels, created, startDate, endDate), created, width, count[fakeChat]), nil
+	case "frt":
+		return groupBuckets(r.incoming(channels, assigned, startDate, endDate), assigned, width, average(func(c fakeChat) float64 { return c.FRT })), nil
+	case "rt":
+		return groupBuckets(r.incoming(channels, assigned, startDate, endDate), assigned, width, average(func(c fakeChat) float64 { return c.RT })), nil
+	}
+	return nil, fmt.Errorf("неподдерживаемая метрика чатов: %s", metric)
+}
+
+func (r *fakeChatReports) Durations(_ context.Context, metric, startDate, endDate string, channels []string) ([]ValueCount, error) {
+	chats := r.incoming(channels, assigned, startDate, endDate)
+	switch metric {
+	case "frt":
+		return valueCounts(chats, func(c fakeChat) float64 { return c.FRT }), nil
+	case "rt":
+		return valueCounts(chats, func(c fakeChat) float64 { return c.RT }), nil
+	}
+	return nil, fmt.Errorf("неподдерживаемая длительность чатов: %s", metric)
+}
+
+func (r *fakeChatReports) AgentActivity(_ context.Context, startDate, endDate string, channels []string) ([]AgentActivity, error) {
+	var answered []fakeChat
+	for _, chat := range r.chats {
+		if chat.AgentFRT > 0 && contains(channels, chat.Channel) && inPeriod(chat.Assign, startDate, endDate) {
+			answered = append(answered, chat)
+		}
+	}
+	return distinctActivity(answered, assigned, func(c fakeChat) string { return c.UserID }), nil
+}
+
+func (r *fakeChatReports) AgentTotals(_ context.Context, startDate, endDate string, channels []string) ([]AgentChatTotals, error) {
+	byAgent := make(map[string]*AgentChatTotals)
+	for _, chat := range r.chats {
+		if chat.AgentFRT <= 0 || chat.UserID == "" || !contains(channels, chat.Channel) || !inPeriod(chat.Assign, startDate, endDate) {
+			continue
+		}
+		agent := byAgent[chat.UserID]
+		if agent == nil {
+			agent = &AgentChatTotals{UserID: chat.UserID}
+			byAgent[chat.UserID] = agent
+		}
+		agent.Chats++
+		agent.FRTSum += chat.AgentFRT
+		agent.FRTCount++
+		agent.RTSum += chat.RT
+		agent.RTCount++
+	}
+
+	totals := make([]AgentChatTotals, 0, len(byAgent))
+	for _, userID := range sortedKeys(byAgent) {
+		totals = append(totals, *byAgent[userID])
+	}
+	return totals, nil
+}
+
+func (r *fakeChatReports) HandleSpans(_ context.Context, startDate, endDate string, channels []string) ([]HandleSpan, error) {
+	spans := make([]HandleSpan, 0)
+	for _, chat := range r.chats {
+		if chat.AgentFRT > 0 && chat.UserID != "" && chat.RT > 0 && contains(channels, chat.Channel) && inPeriod(chat.Assign, startDate, endDate) {
+			spans = append(spans, HandleSpan{UserID: chat.UserID, Date: chat.Assign.Format(dateLayout), Second: secondOfDay(chat.Assign), Duration: chat.RT})
+		}
+	}
+	return spans, nil
+}
+
+func (r *fakeChatReports) AgentChats(_ context.Context, userID, startDate, endDate string, channels []string) ([]AgentChat, error) {
+	chats := make([]AgentChat, 0)
+	for _, chat := range r.chats {
+		if chat.AgentFRT > 0 && chat.UserID == userID && contains(channels, chat.Channel) && inPeriod(chat.Assign, startDate, endDate) {
+			chats = append(chats, AgentChat{Date: chat.Assign.Format(dateLayout), Second: secondOfDay(chat.Assign), FRT: chat.AgentFRT, RT: chat.RT, HasRT: true})
+		}
+	}
+	return chats, nil
+}
+
+func (r *fakeChatReports) QueueUsage(_ context.Context, startDate, endDate string) ([]QueueUsage, error) {
+	return queueUsage(r.chats, func(c fakeChat) string { return c.Channel },
+		func(c fakeChat) string { return day(c.Created) }, startDate, endDate), nil
+}
+
+type fakeClassifiers struct {
+	requests []fakeRequest
+}
+
+// splitClassifierPath делит путь классификатора так же, как агрегация MongoDB
+func splitClassifierPath(path string) (topic, subtopic string) {
+	pieces := strings.Split(path, "/")
+	switch {
+	case len(pieces) >= 3:
+		return strings.TrimSpace(pieces[1]), strings.TrimSpace(strings.Join(pieces[2:], "/"))
+	case len(pieces) == 2:
+		return strings.TrimSpace(pieces[1]), ""
+	default:
+		return strings.TrimSpace(pieces[0]), ""
+	}
+}
+
+// classified развернутые классификаторы входящих обращений за период
+func (r *fakeClassifiers) classified(startDate, endDate string, queues []string) []ClassifierResult {
+	var results []ClassifierResult
+	for _, request := range r.requests {
+		if request.Type != "in" || !contains(queues, request.Queue) ||
+			request.ReportDate < startDate || request.ReportDate > endDate {
+			continue
+		}
+		for _, path := range request.Paths {
+			topic, subtopic := splitClassifierPath(path)
+			results = append(results, ClassifierResult{ReportDate: request.ReportDate, Topic: topic, Subtopic: subtopic, Total: 1})
+		}
+	}
+	return results
+}
+
+// groupSubtopics суммирует обращения по дню, топику и субтопику
+func groupSubtopics(classified []ClassifierResult) []ClassifierResult {
+	byKey := make(map[string]*ClassifierResult)
+	for _, c := range classified {
+		key := c.ReportDate + "\x00" + c.Topic + "\x00" + c.Subtopic
+		if byKey[key] == nil {
+			grouped := c
+			grouped.Total = 0
+			byKey[key] = &grouped
+		}
+		byKey[key].Total++
+	}
+
+	results := make([]ClassifierResult, 0, len(byKey))
+	for _, key := range sortedKeys(byKey) {
+		results = append(results, *byKey[key])
+	}
+	return results
+}
+
+func (r *fakeClassifiers) Classifiers(_ context.Context, startDate, endDate string, queues []string) ([]ClassifierResult, error) {
+	return groupSubtopics(r.classified(startDate, endDate, queues)), nil
+}
+
+func (r *fakeClassifiers) Topics(_ context.Context, startDate, endDate string, queues []string) ([]TopicResult, error) {
+	totals := make(map[string]map[string]int)
+	for _, c := range r.classified(startDate, endDate, queues) {
+		if totals[c.ReportDate] == nil {
+			totals[c.ReportDate] = make(map[string]int)
+		}
+		totals[c.ReportDate][c.Topic]++
+	}
+
+	results := make([]TopicResult, 0)
+	for _, date := range sortedKeys(totals) {
+		grandTotal := 0
+		for _, total := range totals[date] {
+			grandTotal += total
+		}
+		day := make([]TopicResult, 0, len(totals[date]))
+		for _, topic := range sortedKeys(totals[date]) {
+			total := totals[date][topic]
+			day = append(day, TopicResult{
+				ReportDate: date,
+				Topic:      topic,
+				Total:      total,
+				Ratio:      float64(total) / float64(grandTotal) * 100,
+			})
+		}
+		sort.SliceStable(day, func(i, j int) bool { return day[i].Total > day[j].Total })
+		results = append(results, day...)
+	}
+	return results, nil
+}
+
+func (r *fakeClassifiers) AvailableTopics(_ context.Context, startDate, endDate string, queues []string) ([]string, error) {
+	topics := make(map[string]bool)
+	for _, c := range r.classified(startDate, endDate, queues) {
+		topics[c.Topic] = true
+	}
+	return sortedKeys(topics), nil
+}
+
+func (r *fakeClassifiers) Subtopics(_ context.Context, startDate, endDate string, queues []string, topic string) ([]ClassifierResult, error) {
+	var selected []ClassifierResult
+	for _, c := range r.classified(startDate, endDate, queues) {
+		if c.Topic == topic {
+			selected = append(selected, c)
+		}
+	}
+	return groupSubtopics(selected), nil
+}
+
+func (r *fakeClassifiers) AgentTopics(_ context.Context, userID, startDate, endDate string, queues []string) ([]TopicCount, error) {
+	own := &fakeClassifiers{}
+	for _, request := range r.requests {
+		if request.UserID == userID {
+			own.requests = append(own.requests, request)
+		}
+	}
+	totals := make(map[string]int)
+	for _, c := range own.classified(startDate, endDate, queues) {
+		totals[c.Topic]++
+	}
+
+	counts := make([]TopicCount, 0, len(totals))
+	for _, topic := range sortedKeys(totals) {
+		counts = append(counts, TopicCount{Topic: topic, Total: totals[topic]})
+	}
+	sort.SliceStable(counts, func(i, j int) bool { return counts[i].Total > counts[j].Total })
+	return counts, nil
+}
+
+func (r *fakeClassifiers) QueueUsage(_ context.Context, startDate, endDate string) ([]QueueUsage, error) {
+	return queueUsage(r.requests, func(request fakeRequest) string { return request.Queue },
+		func(request fakeRequest) string { return request.ReportDate }, startDate, endDate), nil
+}
+
+// fakeAgents справочник имен агентов
+type fakeAgents struct {
+	names map[string]string
+}
+
+func (r *fakeAgents) Names(_ context.Context, userIDs []string) (map[string]string, error) {
+	names := make(map[string]string)
+	for _, userID := range userIDs {
+		if name, ok := r.names[userID]; ok {
+			names[userID] = name
+		}
+	}
+	return names, nil
+}
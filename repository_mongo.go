@@ -0,0 +1,405 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoClassifiers ClassifierRepository поверх коллекции обращений request
+type mongoClassifiers struct {
+	db *mongo.Database
+}
+
+// requestAgentField поле обращения с user_id агента, как в call_report и chat_report
+const requestAgentField = "userId"
+
+// requestFilter отбирает обращения за период по индексируемым полям type,
+// queueName и createdDate, чтобы не разворачивать классификаторы всей коллекции.
+// Границы createdDate шире периода на сутки и не зависят от часового пояса:
+// точный отбор по дате отчета остается в classifierStages.
+func requestFilter(startDate, endDate string, queues []string) map[string]interface{} {
+	filter := map[string]interface{}{
+		"type":      "in",
+		"queueName": map[string]interface{}{"$in": queues},
+	}
+	if created, ok := createdDateRange(startDate, endDate); ok {
+		filter["createdDate"] = created
+	}
+	return filter
+}
+
+// createdDateRange границы createdDate для периода отчета, шире его на сутки
+// с каждой стороны, чтобы покрыть смещение Asia/Baku; false для неразобранных дат
+func createdDateRange(startDate, endDate string) (map[string]interface{}, bool) {
+	start, startErr := time.Parse(dateLayout, startDate)
+	end, endErr := time.Parse(dateLayout, endDate)
+	if startErr != nil || endErr != nil {
+		return nil, false
+	}
+	return map[string]interface{}{
+		"$gte": start.AddDate(0, 0, -1),
+		"$lt":  end.AddDate(0, 0, 2),
+	}, true
+}
+
+// classifierStages общие стадии агрегаций классификаторов: разворачивает
+// классификаторы обращений за период и делит путь на Topic и Subtopic.
+// Путь "Root/Topic/Sub/Sub2" дает топик "Topic" и субтопик "Sub/Sub2".
+func classifierStages(startDate, endDate string, queues []string) []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"$unwind": "$classifiers",
+		},
+		{
+			"$addFields": map[string]interface{}{
+				"report_date": map[string]interface{}{
+					"$dateToString": map[string]interface{}{
+						"format":   "%Y-%m-%d",
+						"date":     "$createdDate",
+						"timezone": reportTimezone,
+					},
+				},
+			},
+		},
+		{
+			"$match": map[string]interface{}{
+				"type":      "in",
+				"queueName": map[string]interface{}{"$in": queues},
+				"report_date": map[string]interface{}{
+					"$gte": startDate,
+					"$lte": endDate,
+				},
+			},
+		},
+		{
+			"$project": map[string]interface{}{
+				"report_date": 1,
+				"queueName":   1,
+				"pieces":      map[string]interface{}{"$split": []interface{}{"$classifiers.path", "/"}},
+				"len":         map[string]interface{}{"$size": map[string]interface{}{"$split": []interface{}{"$classifiers.path", "/"}}},
+			},
+		},
+		{
+			"$project": map[string]interface{}{
+				"report_date": 1,
+				"queueName":   1,
+				"Topic": map[string]interface{}{
+					"$cond": []interface{}{
+						map[string]interface{}{"$gte": []interface{}{"$len", 3}},
+						map[string]interface{}{"$trim": map[string]interface{}{"input": map[string]interface{}{"$arrayElemAt": []interface{}{"$pieces", 1}}}},
+						map[string]interface{}{
+							"$cond": []interface{}{
+								map[string]interface{}{"$eq": []interface{}{"$len", 2}},
+								map[string]interface{}{"$trim": map[string]interface{}{"input": map[string]interface{}{"$arrayElemAt": []interface{}{"$pieces", 1}}}},
+								map[string]interface{}{"$trim": map[string]interface{}{"input": map[string]interface{}{"$arrayElemAt": []interface{}{"$pieces", 0}}}},
+							},
+						},
+					},
+				},
+				"Subtopic": map[string]interface{}{
+					"$cond": []interface{}{
+						map[string]interface{}{"$gte": []interface{}{"$len", 3}},
+						map[string]interface{}{
+							"$trim": map[string]interface{}{
+								"input": map[string]interface{}{
+									"$reduce": map[string]interface{}{
+										"input":        map[string]interface{}{"$slice": []interface{}{"$pieces", 2, map[string]interface{}{"$subtract": []interface{}{"$len", 2}}}},
+										"initialValue": "",
+										"in": map[string]interface{}{
+											"$cond": []interface{}{
+												map[string]interface{}{"$eq": []interface{}{"$$value", ""}},
+												"$$this",
+												map[string]interface{}{"$concat": []interface{}{"$$value", "/", "$$this"}},
+											},
+										},
+									},
+								},
+							},
+						},
+						"",
+					},
+				},
+			},
+		},
+	}
+}
+
+// subtopicStages группируют обращения по дню, топику и субтопику
+var subtopicStages = []map[string]interface{}{
+	{
+		"$group": map[string]interface{}{
+			"_id": map[string]interface{}{
+				"report_date": "$report_date",
+				"topic":       "$Topic",
+				"subtopic":    "$Subtopic",
+			},
+			"total": map[string]interface{}{"$sum": 1},
+		},
+	},
+	{
+		"$project": map[string]interface{}{
+			"_id":         0,
+			"report_date": "$_id.report_date",
+			"topic":       "$_id.topic",
+			"subtopic":    "$_id.subtopic",
+			"total":       1,
+		},
+	},
+	{
+		"$sort": map[string]interface{}{
+			"report_date": 1,
+			"topic":       1,
+			"subtopic":    1,
+		},
+	},
+}
+
+// aggregate выполняет агрегацию по коллекции request и читает все результаты
+func (r *mongoClassifiers) aggregate(ctx context.Context, pipeline []map[string]interface{}, results interface{}) error {
+	cursor, err := r.db.Collection("request").Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, results)
+}
+
+func (r *mongoClassifiers) Classifiers(ctx context.Context, startDate, endDate string, queues []string) ([]ClassifierResult, error) {
+	pipeline := append(classifierStages(startDate, endDate, queues), subtopicStages...)
+
+	results := make([]ClassifierResult, 0)
+	if err := r.aggregate(ctx, pipeline, &results); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения агрегации классификаторов: %v", err)
+	}
+	return results, nil
+}
+
+func (r *mongoClassifiers) Topics(ctx context.Context, startDate, endDate string, queues []string) ([]TopicResult, error) {
+	pipeline := append(classifierStages(startDate, endDate, queues),
+		map[string]interface{}{
+			"$group": map[string]interface{}{
+				"_id": map[string]interface{}{
+					"report_date": "$report_date",
+					"topic":       "$Topic",
+				},
+				"total": map[string]interface{}{"$sum": 1},
+			},
+		},
+		map[string]interface{}{
+			"$group": map[string]interface{}{
+				"_id": "$_id.report_date",
+				"topics": map[string]interface{}{
+					"$push": map[string]interface{}{
+						"topic": "$_id.topic",
+						"total": "$total",
+					},
+				},
+				"grandTotal": map[string]interface{}{"$sum": "$total"},
+			},
+		},
+		map[string]interface{}{
+			"$unwind": "$topics",
+		},
+		map[string]interface{}{
+			"$project": map[string]interface{}{
+				"_id":         0,
+				"report_date": "$_id",
+				"topic":       "$topics.topic",
+				"total":       "$topics.total",
+				"ratio": map[string]interface{}{
+					"$multiply": []interface{}{
+						map[string]interface{}{"$divide": []interface{}{"$topics.total", "$grandTotal"}},
+						100,
+					},
+				},
+			},
+		},
+		map[string]interface{}{
+			"$sort": map[string]interface{}{
+				"report_date": 1,
+				"total":       -1, // Сортируем по количеству по убыванию
+			},
+		},
+	)
+
+	results := make([]TopicResult, 0)
+	if err := r.aggregate(ctx, pipeline, &results); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения агрегации для топиков: %v", err)
+	}
+	return results, nil
+}
+
+func (r *mongoClassifiers) AvailableTopics(ctx context.Context, startDate, endDate string, queues []string) ([]string, error) {
+	pipeline := append(classifierStages(startDate, endDate, queues),
+		map[string]interface{}{
+			"$group": map[string]interface{}{
+				"_id": "$Topic",
+			},
+		},
+		map[string]interface{}{
+			"$project": map[string]interface{}{
+				"_id":   0,
+				"topic": "$_id",
+			},
+		},
+		map[string]interface{}{
+			"$sort": map[string]interface{}{
+				"topic": 1,
+			},
+		},
+	)
+
+	var results []struct {
+		Topic string `bson:"topic"`
+	}
+	if err := r.aggregate(ctx, pipeline, &results); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения агрегации для списка топиков: %v", err)
+	}
+
+	topics := make([]string, 0, len(results))
+	for _, result := range results {
+		topics = append(topics, result.Topic)
+	}
+	return topics, nil
+}
+
+func (r *mongoClassifiers) Subtopics(ctx context.Context, startDate, endDate string, queues []string, topic string) ([]ClassifierResult, error) {
+	pipeline := append(classifierStages(startDate, endDate, queues),
+		map[string]interface{}{
+			"$match": map[string]interface{}{
+				"Topic": topic,
+			},
+		},
+	)
+	pipeline = append(pipeline, subtopicStages...)
+
+	results := make([]ClassifierResult, 0)
+	if err := r.aggregate(ctx, pipeline, &results); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения агрегации для субтопиков: %v", err)
+	}
+	return results, nil
+}
+
+func (r *mongoClassifiers) AgentTopics(ctx context.Context, userID, startDate, endDate string, queues []string) ([]TopicCount, error) {
+	// Если поля агента в обращениях нет, пустой список топиков вводил бы в заблуждение
+	probe := requestFilter(startDate, endDate, queues)
+	probe[requestAgentField] = map[string]interface{}{"$exists": true}
+	err := r.db.Collection("request").FindOne(ctx, probe, options.FindOne().SetProjection(map[string]interface{}{"_id": 1})).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("в обращениях за период нет поля %s, топики агента недоступны", requestAgentField)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки поля агента в обращениях: %v", err)
+	}
+
+	results := make([]TopicCount, 0)
+	if err := r.aggregate(ctx, agentTopicsPipeline(userID, startDate, endDate, queues), &results); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения агрегации топиков агента: %v", err)
+	}
+	return results, nil
+}
+
+// agentTopicsPipeline агрегация топиков обращений агента. Обращения агента
+// отбираются по индексируемым полям до разворачивания классификаторов.
+func agentTopicsPipeline(userID, startDate, endDate string, queues []string) []map[string]interface{} {
+	filter := requestFilter(startDate, endDate, queues)
+	filter[requestAgentField] = userID
+	pipeline := []map[string]interface{}{
+		{"$match": filter},
+	}
+	pipeline = append(pipeline, classifierStages(startDate, endDate, queues)...)
+	pipeline = append(pipeline,
+		map[string]interface{}{
+			"$group": map[string]interface{}{
+				"_id":   "$Topic",
+				"total": map[string]interface{}{"$sum": 1},
+			},
+		},
+		map[string]interface{}{
+			"$project": map[string]interface{}{
+				"_id":   0,
+				"topic": "$_id",
+				"total": 1,
+			},
+		},
+		map[string]interface{}{
+			"$sort": map[string]interface{}{
+				"total": -1,
+			},
+		},
+	)
+
+	return pipeline
+}
+
+func (r *mongoClassifiers) QueueUsage(ctx context.Context, startDate, endDate string) ([]QueueUsage, error) {
+	var results []struct {
+		QueueName *string `bson:"_id"`
+		Count     int     `bson:"count"`
+		FirstSeen string  `bson:"first_seen"`
+		LastSeen  string  `bson:"last_seen"`
+	}
+	if err := r.aggregate(ctx, queueUsagePipeline(startDate, endDate), &results); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения агрегации очередей обращений: %v", err)
+	}
+
+	usage := make([]QueueUsage, 0, len(results))
+	for _, result := range results {
+		queue := QueueUsage{Count: result.Count, FirstSeen: result.FirstSeen, LastSeen: result.LastSeen}
+		if result.QueueName != nil {
+			queue.QueueName = *result.QueueName
+		}
+		usage = append(usage, queue)
+	}
+	return usage, nil
+}
+
+// queueUsagePipeline агрегация очередей обращений за период. Сначала отбор
+// по индексу createdDate, затем точный отбор по дате отчета в Asia/Baku.
+func queueUsagePipeline(startDate, endDate string) []map[string]interface{} {
+	var pipeline []map[string]interface{}
+	if created, ok := createdDateRange(startDate, endDate); ok {
+		pipeline = append(pipeline, map[string]interface{}{
+			"$match": map[string]interface{}{"createdDate": created},
+		})
+	}
+	return append(pipeline, []map[string]interface{}{
+		{
+			"$addFields": map[string]interface{}{
+				"report_date": map[string]interface{}{
+					"$dateToString": map[string]interface{}{
+						"format":   "%Y-%m-%d",
+						"date":     "$createdDate",
+						"timezone": reportTimezone,
+					},
+				},
+			},
+		},
+		{
+			"$match": map[string]interface{}{
+				"report_date": map[string]interface{}{
+					"$gte": startDate,
+					"$lte": endDate,
+				},
+			},
+		},
+		{
+			"$group": map[string]interface{}{
+				"_id":        "$queueName",
+				"count":      map[string]interface{}{"$sum": 1},
+				"first_seen": map[string]interface{}{"$min": "$report_date"},
+				"last_seen":  map[string]interface{}{"$max": "$report_date"},
+			},
+		},
+		{
+			"$sort": map[string]interface{}{
+				"count": -1,
+			},
+		},
+	}...)
+}
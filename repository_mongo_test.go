@@ -0,0 +1,86 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Обращение из коллекции request в формате mongoexport: чат агента a1,
+// созданный в 00:30 по Баку (накануне по UTC)
+const requestFixture = `{
+  "_id": {"$oid": "65e1204f8a1b2c3d4e5f6a7b"},
+  "type": "in",
+  "queueName": "WHATSAPP",
+  "createdDate": {"$date": "2024-02-29T20:30:00Z"},
+  "userId": "a1",
+  "classifiers": [{"path": "M10/Billing/Refund/Card"}]
+}`
+
+func TestAgentTopicsPipeline(t *testing.T) {
+	var document bson.M
+	if err := bson.UnmarshalExtJSON([]byte(requestFixture), false, &document); err != nil {
+		t.Fatal(err)
+	}
+
+	queues := QueueRegistry(nil).Group("all").ChatChannels
+	pipeline := agentTopicsPipeline("a1", "2024-03-01", "2024-03-01", queues)
+
+	// Отбор по индексируемым полям стоит до разворачивания классификаторов
+	match, ok := pipeline[0]["$match"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("first stage = %v, want $match", pipeline[0])
+	}
+	if _, ok := pipeline[1]["$unwind"]; !ok {
+		t.Fatalf("second stage = %v, want $unwind", pipeline[1])
+	}
+
+	// Все поля отбора есть в документе и документ им удовлетворяет
+	for key := range match {
+		if _, ok := document[key]; !ok {
+			t.Errorf("field %s from $match is missing in request document", key)
+		}
+	}
+	if match["type"] != document["type"] || match[requestAgentField] != document["userId"] {
+		t.Errorf("type/%s: match %v, document %v", requestAgentField, match, document)
+	}
+	in := match["queueName"].(map[string]interface{})["$in"].([]string)
+	if !slices.Contains(in, document["queueName"].(string)) {
+		t.Errorf("queueName %v not in %v", document["queueName"], in)
+	}
+	created := document["createdDate"].(primitive.DateTime).Time()
+	bounds := match["createdDate"].(map[string]interface{})
+	if created.Before(bounds["$gte"].(time.Time)) || !created.Before(bounds["$lt"].(time.Time)) {
+		t.Errorf("createdDate %v outside %v", created, bounds)
+	}
+}
+
+func TestQueueUsagePipeline(t *testing.T) {
+	var document bson.M
+	if err := bson.UnmarshalExtJSON([]byte(requestFixture), false, &document); err != nil {
+		t.Fatal(err)
+	}
+
+	pipeline := queueUsagePipeline("2024-03-01", "2024-03-01")
+
+	// Отбор по индексу createdDate идет до вычисления даты отчета
+	match, ok := pipeline[0]["$match"].(map[string]interface{})
+	if !ok || len(match) != 1 {
+		t.Fatalf("first stage = %v, want $match on createdDate", pipeline[0])
+	}
+	created := document["createdDate"].(primitive.DateTime).Time()
+	bounds := match["createdDate"].(map[string]interface{})
+	if created.Before(bounds["$gte"].(time.Time)) || !created.Before(bounds["$lt"].(time.Time)) {
+		t.Errorf("createdDate %v outside %v", created, bounds)
+	}
+	if _, ok := pipeline[1]["$addFields"]; !ok {
+		t.Fatalf("second stage = %v, want $addFields", pipeline[1])
+	}
+	exact, ok := pipeline[2]["$match"].(map[string]interface{})
+	if _, hasDate := exact["report_date"]; !ok || !hasDate {
+		t.Fatalf("third stage = %v, want $match on report_date", pipeline[2])
+	}
+}
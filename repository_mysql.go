@@ -0,0 +1,997 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// mysqlCallReports CallReportRepository поверх таблицы call_report
+type mysqlCallReports struct {
+	db *sql.DB
+}
+
+// mysqlChatReports ChatReportRepository поверх таблицы chat_report
+type mysqlChatReports struct {
+	db *sql.DB
+}
+
+// mysqlAgents AgentRepository поверх omni.conversation, где у диалогов
+// хранится full_name агента. Столбец user_id в этой таблице не подтвержден,
+// поэтому перед запросом имен он ищется в information_schema.
+type mysqlAgents struct {
+	db *sql.DB
+}
+
+// queryDaily выполняет запрос вида (дата, значение). Дни, где значение NULL, пропускаются.
+func queryDaily(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]DailyValue, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]DailyValue, 0)
+	for rows.Next() {
+		var date time.Time
+		var value sql.NullFloat64
+		if err := rows.Scan(&date, &value); err != nil {
+			return nil, err
+		}
+		if !value.Valid {
+			continue
+		}
+		values = append(values, DailyValue{Date: date.Format(dateLayout), Value: value.Float64})
+	}
+	return values, rows.Err()
+}
+
+// bucketQuery определение метрики для запроса по интервалам
+type bucketQuery struct {
+	dateColumn string // время, по которому запись попадает в интервал
+	value      string // агрегат по записям интервала
+	filter     string // отбор записей
+	// params параметры выражений value и filter из порогов группы очередей
+	params func(t CallThresholds) (value, filter []interface{})
+}
+
+// Условия на брошенные звонки: короткие брошенные ожидали меньше порога.
+// Параметр — порог коротких брошенных в секундах.
+const (
+	longAbandon    = "COALESCE(queue_wait_time, 0) >= ?"
+	offeredFilter  = "type IN ('in', 'abandon') AND (type = 'in' OR " + longAbandon + ")"
+	abandonedValue = "SUM(CASE WHEN type = 'abandon' THEN 1 ELSE 0 END)"
+)
+
+// shortAbandonParams параметр фильтра брошенных звонков
+func shortAbandonParams(t CallThresholds) (value, filter []interface{}) {
+	return nil, []interface{}{t.ShortAbandonSeconds}
+}
+
+// callBucketQueries метрики call_report по интервалам
+var callBucketQueries = map[string]bucketQuery{
+	"calls": {dateColumn: "enter_queue_date", value: "COUNT(*)", filter: "type = 'in'"},
+	"aht":   {dateColumn: "answer_date", value: "AVG(call_duration)", filter: "type = 'in'"},
+	"sl": {dateColumn: "enter_queue_date", value: "SUM(CASE WHEN queue_wait_time <= ? THEN 1 ELSE 0 END) / COUNT(*) * 100", filter: "type = 'in'",
+		params: func(t CallThresholds) (value, filter []interface{}) { return []interface{}{t.SLSeconds}, nil }},
+	"abandoned":    {dateColumn: "enter_queue_date", value: "COUNT(*)", filter: "type = 'abandon' AND " + longAbandon, params: shortAbandonParams},
+	"abandon_rate": {dateColumn: "enter_queue_date", value: abandonedValue + " / COUNT(*) * 100", filter: offeredFilter, params: shortAbandonParams},
+}
+
+// chatBucketQueries метрики chat_report по интервалам
+var chatBucketQueries = map[string]bucketQuery{
+	"chats": {dateColumn: "created_date", value: "COUNT(*)", filter: "type = 'in'"},
+	"frt":   {dateColumn: "assign_date", value: "AVG(chat_frt)", filter: "type = 'in'"},
+	"rt":    {dateColumn: "assign_date", value: "AVG(resolution_time_total)", filter: "type = 'in'"},
+}
+
+// chatDurationColumns колонки длительностей чатов
+var chatDurationColumns = map[string]string{
+	"frt": "chat_frt",
+	"rt":  "resolution_time_total",
+}
+
+// queryBuckets выполняет запрос метрики с группировкой по дню и интервалу
+// шириной width минут; интервалы, где значение NULL, пропускаются
+func queryBuckets(ctx context.Context, db *sql.DB, table string, definition bucketQuery, startDate, endDate string, width int, thresholds CallThresholds, queues []string) ([]BucketValue, error) {
+	if err := checkBucketWidth(width); err != nil {
+		return nil, err
+	}
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT
+		  DATE(%[1]s) AS Day,
+		  FLOOR((HOUR(%[1]s) * 60 + MINUTE(%[1]s)) / %[2]d) AS Bucket,
+		  %[3]s AS value
+		FROM %[4]s
+		WHERE %[1]s >= ? AND %[1]s <= ?
+		  AND %[5]s
+		  AND %[6]s
+		GROUP BY Day, Bucket
+		ORDER BY Day, Bucket
+	`, definition.dateColumn, width, definition.value, table, definition.filter, queueCondition)
+
+	var valueParams, filterParams []interface{}
+	if definition.params != nil {
+		valueParams, filterParams = definition.params(thresholds)
+	}
+	from, to := periodBounds(startDate, endDate)
+	args := append(valueParams, from, to)
+	args = append(args, filterParams...)
+	rows, err := db.QueryContext(ctx, query, append(args, queueParams...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]BucketValue, 0)
+	for rows.Next() {
+		var date time.Time
+		var bucket int
+		var value sql.NullFloat64
+		if err := rows.Scan(&date, &bucket, &value); err != nil {
+			return nil, err
+		}
+		if !value.Valid {
+			continue
+		}
+		values = append(values, BucketValue{Date: date.Format(dateLayout), Bucket: bucket, Value: value.Float64})
+	}
+	return values, rows.Err()
+}
+
+// slCountsQuery запрос количества записей по дням и количества уложившихся
+// в каждый порог: value <= порог. Параметры: пороги, границы периода, очереди.
+func slCountsQuery(table, dateColumn, value, filter, queueCondition string, thresholds int) string {
+	var within strings.Builder
+	for i := 0; i < thresholds; i++ {
+		fmt.Fprintf(&within, ",\n\t\t  SUM(CASE WHEN %s <= ? THEN 1 ELSE 0 END) AS within_%d", value, i)
+	}
+	return fmt.Sprintf(`
+		SELECT
+		  DATE(%[1]s) AS report_date,
+		  COUNT(*) AS total%[2]s
+		FROM %[3]s
+		WHERE %[1]s BETWEEN ? AND ?
+		  AND %[4]s
+		  AND %[5]s
+		GROUP BY report_date
+		ORDER BY report_date
+	`, dateColumn, within.String(), table, filter, queueCondition)
+}
+
+// querySLCounts выполняет запрос slCountsQuery
+func querySLCounts(ctx context.Context, db *sql.DB, query string, thresholds []int, startDate, endDate string, queueParams []interface{}) ([]SLCounts, error) {
+	args := make([]interface{}, 0, len(thresholds)+2+len(queueParams))
+	for _, threshold := range thresholds {
+		args = append(args, threshold)
+	}
+	rows, err := db.QueryContext(ctx, query, append(args, callArgs(startDate, endDate, queueParams)...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]SLCounts, 0)
+	for rows.Next() {
+		var date time.Time
+		day := SLCounts{Within: make([]int, len(thresholds))}
+		dest := []interface{}{&date, &day.Total}
+		for i := range day.Within {
+			dest = append(dest, &day.Within[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		day.Date = date.Format(dateLayout)
+		counts = append(counts, day)
+	}
+	return counts, rows.Err()
+}
+
+// queryValueCounts выполняет запрос вида (значение, количество)
+func queryValueCounts(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]ValueCount, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]ValueCount, 0)
+	for rows.Next() {
+		var count ValueCount
+		if err := rows.Scan(&count.Value, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// minuteSlot выражение начала интервала шириной slotMinutes в минутах от полуночи
+func minuteSlot(column string) string {
+	return fmt.Sprintf("FLOOR((HOUR(%[1]s) * 60 + MINUTE(%[1]s)) / %[2]d) * %[2]d", column, slotMinutes)
+}
+
+// queryAgentActivity выполняет запрос вида (дата, начало интервала, user_id)
+func queryAgentActivity(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]AgentActivity, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activity := make([]AgentActivity, 0)
+	for rows.Next() {
+		var date time.Time
+		var entry AgentActivity
+		if err := rows.Scan(&date, &entry.Minute, &entry.UserID); err != nil {
+			return nil, err
+		}
+		entry.Date = date.Format(dateLayout)
+		activity = append(activity, entry)
+	}
+	return activity, rows.Err()
+}
+
+// abandonArgs параметры запроса с фильтром коротких брошенных между
+// границами периода и очередями
+func abandonArgs(startDate, endDate string, shortAbandonSeconds int, queueParams []interface{}) []interface{} {
+	from, to := periodBounds(startDate, endDate)
+	return append([]interface{}{from, to, shortAbandonSeconds}, queueParams...)
+}
+
+// callArgs собирает параметры запроса: границы периода и очереди (каналы)
+func callArgs(startDate, endDate string, queueParams []interface{}) []interface{} {
+	from, to := periodBounds(startDate, endDate)
+	return append([]interface{}{from, to}, queueParams...)
+}
+
+func (r *mysqlCallReports) DailyCalls(ctx context.Context, startDate, endDate string, queues []string) ([]DailyValue, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT
+		  DATE(enter_queue_date) AS report_date,
+		  COUNT(*) AS total_calls
+		FROM call_report
+		WHERE enter_queue_date BETWEEN ? AND ?
+		  AND type IN ('in', 'abandon')
+		  AND %s
+		GROUP BY report_date
+		ORDER BY report_date
+	`, queueCondition)
+
+	values, err := queryDaily(ctx, r.db, query, callArgs(startDate, endDate, queueParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса звонков: %v", err)
+	}
+	return values, nil
+}
+
+func (r *mysqlCallReports) DailyAHT(ctx context.Context, startDate, endDate string, queues []string) ([]DailyValue, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT
+		  DATE(enter_queue_date) AS report_date,
+		  AVG(call_duration) AS avg_call_duration
+		FROM call_report
+		WHERE enter_queue_date BETWEEN ? AND ?
+		  AND type = 'in'
+		  AND %s
+		GROUP BY report_date
+		ORDER BY report_date
+	`, queueCondition)
+
+	values, err := queryDaily(ctx, r.db, query, callArgs(startDate, endDate, queueParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса AHT: %v", err)
+	}
+	return values, nil
+}
+
+func (r *mysqlCallReports) DailySL(ctx context.Context, startDate, endDate string, thresholdSeconds int, queues []string) ([]DailyValue, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT DATE(enter_queue_date) AS report_date,
+		       SUM(CASE WHEN queue_wait_time <= ? THEN 1 ELSE 0 END) / COUNT(*) * 100 AS sl
+		FROM call_report
+		WHERE enter_queue_date BETWEEN ? AND ?
+		  AND type = 'in'
+		  AND %s
+		GROUP BY report_date
+		ORDER BY report_date
+	`, queueCondition)
+
+	args := append([]interface{}{thresholdSeconds}, callArgs(startDate, endDate, queueParams)...)
+	values, err := queryDaily(ctx, r.db, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса SL: %v", err)
+	}
+	return values, nil
+}
+
+func (r *mysqlCallReports) DailyAbandoned(ctx context.Context, startDate, endDate string, shortAbandonSeconds int, queues []string) ([]DailyValue, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT DATE(enter_queue_date) AS report_date, COUNT(*) AS total_abandoned
+		FROM call_report
+		WHERE enter_queue_date BETWEEN ? AND ?
+		  AND type = 'abandon'
+		  AND %s
+		  AND %s
+		GROUP BY report_date
+		ORDER BY report_date
+	`, longAbandon, queueCondition)
+
+	values, err := queryDaily(ctx, r.db, query, abandonArgs(startDate, endDate, shortAbandonSeconds, queueParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса заброшенных звонков: %v", err)
+	}
+	return values, nil
+}
+
+func (r *mysqlCallReports) DailyAbandonRate(ctx context.Context, startDate, endDate string, shortAbandonSeconds int, queues []string) ([]DailyValue, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT DATE(enter_queue_date) AS report_date,
+		       %s / COUNT(*) * 100 AS abandon_rate
+		FROM call_report
+		WHERE enter_queue_date BETWEEN ? AND ?
+		  AND %s
+		  AND %s
+		GROUP BY report_date
+		ORDER BY report_date
+	`, abandonedValue, offeredFilter, queueCondition)
+
+	values, err := queryDaily(ctx, r.db, query, abandonArgs(startDate, endDate, shortAbandonSeconds, queueParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса процента брошенных звонков: %v", err)
+	}
+	return values, nil
+}
+
+func (r *mysqlCallReports) DailyTotals(ctx context.Context, startDate, endDate string, thresholds CallThresholds, queues []string) ([]CallTotals, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT
+		  DATE(enter_queue_date) AS report_date,
+		  COUNT(*) AS calls,
+		  SUM(CASE WHEN type = 'in' THEN 1 ELSE 0 END) AS answered,
+		  COALESCE(SUM(CASE WHEN type = 'in' THEN call_duration END), 0) AS duration_sum,
+		  COUNT(CASE WHEN type = 'in' THEN call_duration END) AS duration_count,
+		  SUM(CASE WHEN type = 'in' AND queue_wait_time <= ? THEN 1 ELSE 0 END) AS within_sl,
+		  SUM(CASE WHEN type = 'abandon' AND COALESCE(queue_wait_time, 0) >= ? THEN 1 ELSE 0 END) AS abandoned,
+		  SUM(CASE WHEN type = 'abandon' AND COALESCE(queue_wait_time, 0) < ? THEN 1 ELSE 0 END) AS short_abandoned
+		FROM call_report
+		WHERE enter_queue_date BETWEEN ? AND ?
+		  AND type IN ('in', 'abandon')
+		  AND %s
+		GROUP BY report_date
+		ORDER BY report_date
+	`, queueCondition)
+
+	selectParams := []interface{}{thresholds.SLSeconds, thresholds.ShortAbandonSeconds, thresholds.ShortAbandonSeconds}
+	rows, err := r.db.QueryContext(ctx, query, append(selectParams, callArgs(startDate, endDate, queueParams)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса итогов звонков: %v", err)
+	}
+	defer rows.Close()
+
+	totals := make([]CallTotals, 0)
+	for rows.Next() {
+		var date time.Time
+		var day CallTotals
+		if err := rows.Scan(&date, &day.Calls, &day.Answered, &day.DurationSum, &day.DurationCount, &day.WithinSL, &day.Abandoned, &day.ShortAbandoned); err != nil {
+			return nil, fmt.Errorf("ошибка чтения итогов звонков: %v", err)
+		}
+		day.Date = date.Format(dateLayout)
+		totals = append(totals, day)
+	}
+	return totals, rows.Err()
+}
+
+func (r *mysqlCallReports) DailySLCounts(ctx context.Context, startDate, endDate string, thresholds []int, queues []string) ([]SLCounts, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := slCountsQuery("call_report", "enter_queue_date", "queue_wait_time", "type = 'in'", queueCondition, len(thresholds))
+
+	counts, err := querySLCounts(ctx, r.db, query, thresholds, startDate, endDate, queueParams)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса SL по порогам: %v", err)
+	}
+	return counts, nil
+}
+
+func (r *mysqlCallReports) Buckets(ctx context.Context, metric, startDate, endDate string, width int, thresholds CallThresholds, queues []string) ([]BucketValue, error) {
+	definition, ok := callBucketQueries[metric]
+	if !ok {
+		return nil, fmt.Errorf("неподдерживаемая метрика звонков: %s", metric)
+	}
+	values, err := queryBuckets(ctx, r.db, "call_report", definition, startDate, endDate, width, thresholds, queues)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса %s по интервалам: %v", metric, err)
+	}
+	return values, nil
+}
+
+func (r *mysqlCallReports) AnswerWaits(ctx context.Context, startDate, endDate string, queues []string) ([]WaitCount, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT
+		  DATE(enter_queue_date) AS Day,
+		  %s AS Minute,
+		  queue_wait_time AS wait,
+		  COUNT(*) AS answered
+		FROM call_report
+		WHERE enter_queue_date BETWEEN ? AND ?
+		  AND type = 'in'
+		  AND queue_wait_time IS NOT NULL
+		  AND %s
+		GROUP BY Day, Minute, wait
+		ORDER BY Day, Minute, wait
+	`, minuteSlot("enter_queue_date"), queueCondition)
+
+	rows, err := r.db.QueryContext(ctx, query, callArgs(startDate, endDate, queueParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса ожидания отвеченных звонков: %v", err)
+	}
+	defer rows.Close()
+
+	counts := make([]WaitCount, 0)
+	for rows.Next() {
+		var date time.Time
+		var count WaitCount
+		if err := rows.Scan(&date, &count.Minute, &count.Wait, &count.Count); err != nil {
+			return nil, fmt.Errorf("ошибка чтения ожидания отвеченных звонков: %v", err)
+		}
+		count.Date = date.Format(dateLayout)
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+func (r *mysqlCallReports) Durations(ctx context.Context, startDate, endDate string, queues []string) ([]ValueCount, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT
+		  call_duration AS value,
+		  COUNT(*) AS records
+		FROM call_report
+		WHERE enter_queue_date BETWEEN ? AND ?
+		  AND type = 'in'
+		  AND call_duration IS NOT NULL
+		  AND %s
+		GROUP BY value
+		ORDER BY value
+	`, queueCondition)
+
+	counts, err := queryValueCounts(ctx, r.db, query, callArgs(startDate, endDate, queueParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса длительностей звонков: %v", err)
+	}
+	return counts, nil
+}
+
+func (r *mysqlCallReports) AgentTotals(ctx context.Context, startDate, endDate string, queues []string) ([]AgentCallTotals, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT
+		  user_id,
+		  COUNT(*) AS calls,
+		  COALESCE(SUM(call_duration), 0) AS duration_sum,
+		  COUNT(call_duration) AS duration_count
+		FROM call_report
+		WHERE answer_date BETWEEN ? AND ?
+		  AND type = 'in'
+		  AND user_id IS NOT NULL
+		  AND %s
+		GROUP BY user_id
+		ORDER BY user_id
+	`, queueCondition)
+
+	rows, err := r.db.QueryContext(ctx, query, callArgs(startDate, endDate, queueParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса звонков агентов: %v", err)
+	}
+	defer rows.Close()
+
+	totals := make([]AgentCallTotals, 0)
+	for rows.Next() {
+		var agent AgentCallTotals
+		if err := rows.Scan(&agent.UserID, &agent.Calls, &agent.DurationSum, &agent.DurationCount); err != nil {
+			return nil, fmt.Errorf("ошибка чтения звонков агентов: %v", err)
+		}
+		totals = append(totals, agent)
+	}
+	return totals, rows.Err()
+}
+
+// queryHandleSpans выполняет запрос вида (user_id, дата, секунды от полуночи, длительность)
+func queryHandleSpans(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]HandleSpan, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	spans := make([]HandleSpan, 0)
+	for rows.Next() {
+		var date time.Time
+		var span HandleSpan
+		if err := rows.Scan(&span.UserID, &date, &span.Second, &span.Duration); err != nil {
+			return nil, err
+		}
+		span.Date = date.Format(dateLayout)
+		spans = append(spans, span)
+	}
+	return spans, rows.Err()
+}
+
+func (r *mysqlCallReports) HandleSpans(ctx context.Context, startDate, endDate string, queues []string) ([]HandleSpan, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT
+		  user_id,
+		  DATE(answer_date) AS Day,
+		  TIME_TO_SEC(answer_date) AS Second,
+		  call_duration
+		FROM call_report
+		WHERE answer_date BETWEEN ? AND ?
+		  AND type = 'in'
+		  AND user_id IS NOT NULL
+		  AND call_duration > 0
+		  AND %s
+	`, queueCondition)
+
+	spans, err := queryHandleSpans(ctx, r.db, query, callArgs(startDate, endDate, queueParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса разговоров агентов: %v", err)
+	}
+	return spans, nil
+}
+
+func (r *mysqlCallReports) AgentCalls(ctx context.Context, userID, startDate, endDate string, queues []string) ([]AgentCall, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT
+		  DATE(answer_date) AS Day,
+		  TIME_TO_SEC(answer_date) AS Second,
+		  call_duration
+		FROM call_report
+		WHERE answer_date BETWEEN ? AND ?
+		  AND type = 'in'
+		  AND %s
+		  AND user_id = ?
+		ORDER BY answer_date
+	`, queueCondition)
+
+	rows, err := r.db.QueryContext(ctx, query, append(callArgs(startDate, endDate, queueParams), userID)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса звонков агента: %v", err)
+	}
+	defer rows.Close()
+
+	calls := make([]AgentCall, 0)
+	for rows.Next() {
+		var date time.Time
+		var duration sql.NullFloat64
+		var call AgentCall
+		if err := rows.Scan(&date, &call.Second, &duration); err != nil {
+			return nil, fmt.Errorf("ошибка чтения звонков агента: %v", err)
+		}
+		call.Date = date.Format(dateLayout)
+		call.Duration, call.HasDuration = duration.Float64, duration.Valid
+		calls = append(calls, call)
+	}
+	return calls, rows.Err()
+}
+
+func (r *mysqlCallReports) AbandonWaits(ctx context.Context, startDate, endDate string, edges []int, queues []string) ([]WaitBinCount, error) {
+	var bins strings.Builder
+	for i, edge := range edges {
+		fmt.Fprintf(&bins, "WHEN COALESCE(queue_wait_time, 0) < %d THEN %d ", edge, i)
+	}
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT
+		  DATE(enter_queue_date) AS Day,
+		  HOUR(enter_queue_date) AS Hour,
+		  CASE %sELSE %d END AS Bin,
+		  COUNT(*) AS abandoned
+		FROM call_report
+		WHERE enter_queue_date BETWEEN ? AND ?
+		  AND type = 'abandon'
+		  AND %s
+		GROUP BY Day, Hour, Bin
+		ORDER BY Day, Hour, Bin
+	`, bins.String(), len(edges), queueCondition)
+
+	rows, err := r.db.QueryContext(ctx, query, callArgs(startDate, endDate, queueParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса ожидания брошенных звонков: %v", err)
+	}
+	defer rows.Close()
+
+	counts := make([]WaitBinCount, 0)
+	for rows.Next() {
+		var date time.Time
+		var count WaitBinCount
+		if err := rows.Scan(&date, &count.Hour, &count.Bin, &count.Count); err != nil {
+			return nil, fmt.Errorf("ошибка чтения ожидания брошенных звонков: %v", err)
+		}
+		count.Date = date.Format(dateLayout)
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+func (r *mysqlCallReports) AgentActivity(ctx context.Context, startDate, endDate string, queues []string) ([]AgentActivity, error) {
+	queueCondition, queueParams := inCondition("queue_name", queues)
+	query := fmt.Sprintf(`
+		SELECT DISTINCT
+		  DATE(answer_date) AS Day,
+		  %s AS Minute,
+		  user_id
+		FROM call_report
+		WHERE answer_date BETWEEN ? AND ?
+		  AND type = 'in'
+		  AND user_id IS NOT NULL
+		  AND %s
+	`, minuteSlot("answer_date"), queueCondition)
+
+	activity, err := queryAgentActivity(ctx, r.db, query, callArgs(startDate, endDate, queueParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса агентов: %v", err)
+	}
+	return activity, nil
+}
+
+func (r *mysqlCallReports) QueueUsage(ctx context.Context, startDate, endDate string) ([]QueueUsage, error) {
+	usage, err := queryQueueUsage(ctx, r.db, "call_report", "enter_queue_date", startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса очередей: %v", err)
+	}
+	return usage, nil
+}
+
+// queryQueueUsage очереди таблицы отчета за период с количеством записей
+// и датами первой и последней записи
+func queryQueueUsage(ctx context.Context, db *sql.DB, table, dateColumn, startDate, endDate string) ([]QueueUsage, error) {
+	query := fmt.Sprintf(`
+		SELECT
+		  queue_name,
+		  COUNT(*) AS count,
+		  DATE(MIN(%[2]s)) AS first_seen,
+		  DATE(MAX(%[2]s)) AS last_seen
+		FROM %[1]s
+		WHERE %[2]s BETWEEN ? AND ?
+		GROUP BY queue_name
+		ORDER BY count DESC
+	`, table, dateColumn)
+
+	from, to := periodBounds(startDate, endDate)
+	rows, err := db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := make([]QueueUsage, 0)
+	for rows.Next() {
+		var queue QueueUsage
+		var queueName sql.NullString
+		var firstSeen, lastSeen time.Time
+		if err := rows.Scan(&queueName, &queue.Count, &firstSeen, &lastSeen); err != nil {
+			return nil, err
+		}
+		queue.QueueName = queueName.String
+		queue.FirstSeen = firstSeen.Format(dateLayout)
+		queue.LastSeen = lastSeen.Format(dateLayout)
+		usage = append(usage, queue)
+	}
+	return usage, rows.Err()
+}
+
+func (r *mysqlChatReports) DailyChats(ctx context.Context, startDate, endDate string, channels []string) ([]DailyValue, error) {
+	values, err := r.daily(ctx, "COUNT(*)", startDate, endDate, channels)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса чатов: %v", err)
+	}
+	return values, nil
+}
+
+func (r *mysqlChatReports) DailyFRT(ctx context.Context, startDate, endDate string, channels []string) ([]DailyValue, error) {
+	values, err := r.daily(ctx, "AVG(chat_frt)", startDate, endDate, channels)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса FRT: %v", err)
+	}
+	return values, nil
+}
+
+func (r *mysqlChatReports) DailyRT(ctx context.Context, startDate, endDate string, channels []string) ([]DailyValue, error) {
+	values, err := r.daily(ctx, "AVG(resolution_time_total)", startDate, endDate, channels)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса RT: %v", err)
+	}
+	return values, nil
+}
+
+// daily дневная метрика входящих чатов по дате назначения
+func (r *mysqlChatReports) daily(ctx context.Context, column, startDate, endDate string, channels []string) ([]DailyValue, error) {
+	channelCondition, channelParams := inCondition("queue_name", channels)
+	query := fmt.Sprintf(`
+		SELECT
+		  DATE(assign_date) AS report_date,
+		  %s AS value
+		FROM chat_report
+		WHERE type = 'in'
+		  AND assign_date BETWEEN ? AND ?
+		  AND %s
+		GROUP BY report_date
+		ORDER BY report_date
+	`, column, channelCondition)
+
+	return queryDaily(ctx, r.db, query, callArgs(startDate, endDate, channelParams)...)
+}
+
+func (r *mysqlChatReports) DailyTotals(ctx context.Context, startDate, endDate string, channels []string) ([]ChatTotals, error) {
+	channelCondition, channelParams := inCondition("queue_name", channels)
+	query := fmt.Sprintf(`
+		SELECT
+		  DATE(assign_date) AS report_date,
+		  COUNT(*) AS chats,
+		  COALESCE(SUM(chat_frt), 0) AS frt_sum,
+		  COUNT(chat_frt) AS frt_count,
+		  COALESCE(SUM(resolution_time_total), 0) AS rt_sum,
+		  COUNT(resolution_time_total) AS rt_count
+		FROM chat_report
+		WHERE type = 'in'
+		  AND assign_date BETWEEN ? AND ?
+		  AND %s
+		GROUP BY report_date
+		ORDER BY report_date
+	`, channelCondition)
+
+	rows, err := r.db.QueryContext(ctx, query, callArgs(startDate, endDate, channelParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса итогов чатов: %v", err)
+	}
+	defer rows.Close()
+
+	totals := make([]ChatTotals, 0)
+	for rows.Next() {
+		var date time.Time
+		var day ChatTotals
+		if err := rows.Scan(&date, &day.Chats, &day.FRTSum, &day.FRTCount, &day.RTSum, &day.RTCount); err != nil {
+			return nil, fmt.Errorf("ошибка чтения итогов чатов: %v", err)
+		}
+		day.Date = date.Format(dateLayout)
+		totals = append(totals, day)
+	}
+	return totals, rows.Err()
+}
+
+func (r *mysqlChatReports) DailySLCounts(ctx context.Context, startDate, endDate string, thresholds []int, channels []string) ([]SLCounts, error) {
+	channelCondition, channelParams := inCondition("queue_name", channels)
+	query := slCountsQuery("chat_report", "assign_date", "chat_frt", "type = 'in' AND chat_frt IS NOT NULL", channelCondition, len(thresholds))
+
+	counts, err := querySLCounts(ctx, r.db, query, thresholds, startDate, endDate, channelParams)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса SL чатов по порогам: %v", err)
+	}
+	return counts, nil
+}
+
+func (r *mysqlChatReports) Buckets(ctx context.Context, metric, startDate, endDate string, width int, channels []string) ([]BucketValue, error) {
+	definition, ok := chatBucketQueries[metric]
+	if !ok {
+		return nil, fmt.Errorf("неподдерживаемая метрика чатов: %s", metric)
+	}
+	values, err := queryBuckets(ctx, r.db, "chat_report", definition, startDate, endDate, width, CallThresholds{}, channels)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса %s по интервалам: %v", metric, err)
+	}
+	return values, nil
+}
+
+func (r *mysqlChatReports) Durations(ctx context.Context, metric, startDate, endDate string, channels []string) ([]ValueCount, error) {
+	column, ok := chatDurationColumns[metric]
+	if !ok {
+		return nil, fmt.Errorf("неподдерживаемая длительность чатов: %s", metric)
+	}
+	channelCondition, channelParams := inCondition("queue_name", channels)
+	query := fmt.Sprintf(`
+		SELECT
+		  %[1]s AS value,
+		  COUNT(*) AS records
+		FROM chat_report
+		WHERE type = 'in'
+		  AND assign_date BETWEEN ? AND ?
+		  AND %[1]s IS NOT NULL
+		  AND %[2]s
+		GROUP BY value
+		ORDER BY value
+	`, column, channelCondition)
+
+	counts, err := queryValueCounts(ctx, r.db, query, callArgs(startDate, endDate, channelParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса %s по длительности: %v", metric, err)
+	}
+	return counts, nil
+}
+
+func (r *mysqlChatReports) AgentActivity(ctx context.Context, startDate, endDate string, channels []string) ([]AgentActivity, error) {
+	channelCondition, channelParams := inCondition("queue_name", channels)
+	query := fmt.Sprintf(`
+		SELECT DISTINCT
+		  DATE(assign_date) AS Day,
+		  %s AS Minute,
+		  user_id
+		FROM chat_report
+		WHERE assign_date BETWEEN ? AND ?
+		  AND agent_frt > 0
+		  AND user_id IS NOT NULL
+		  AND %s
+	`, minuteSlot("assign_date"), channelCondition)
+
+	activity, err := queryAgentActivity(ctx, r.db, query, callArgs(startDate, endDate, channelParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса агентов в чатах: %v", err)
+	}
+	return activity, nil
+}
+
+func (r *mysqlChatReports) AgentTotals(ctx context.Context, startDate, endDate string, channels []string) ([]AgentChatTotals, error) {
+	channelCondition, channelParams := inCondition("queue_name", channels)
+	query := fmt.Sprintf(`
+		SELECT
+		  user_id,
+		  COUNT(*) AS chats,
+		  COALESCE(SUM(agent_frt), 0) AS frt_sum,
+		  COUNT(agent_frt) AS frt_count,
+		  COALESCE(SUM(resolution_time_total), 0) AS rt_sum,
+		  COUNT(resolution_time_total) AS rt_count
+		FROM chat_report
+		WHERE assign_date BETWEEN ? AND ?
+		  AND agent_frt > 0
+		  AND user_id IS NOT NULL
+		  AND %s
+		GROUP BY user_id
+		ORDER BY user_id
+	`, channelCondition)
+
+	rows, err := r.db.QueryContext(ctx, query, callArgs(startDate, endDate, channelParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса чатов агентов: %v", err)
+	}
+	defer rows.Close()
+
+	totals := make([]AgentChatTotals, 0)
+	for rows.Next() {
+		var agent AgentChatTotals
+		if err := rows.Scan(&agent.UserID, &agent.Chats, &agent.FRTSum, &agent.FRTCount, &agent.RTSum, &agent.RTCount); err != nil {
+			return nil, fmt.Errorf("ошибка чтения чатов агентов: %v", err)
+		}
+		totals = append(totals, agent)
+	}
+	return totals, rows.Err()
+}
+
+func (r *mysqlChatReports) HandleSpans(ctx context.Context, startDate, endDate string, channels []string) ([]HandleSpan, error) {
+	channelCondition, channelParams := inCondition("queue_name", channels)
+	query := fmt.Sprintf(`
+		SELECT
+		  user_id,
+		  DATE(assign_date) AS Day,
+		  TIME_TO_SEC(assign_date) AS Second,
+		  resolution_time_total
+		FROM chat_report
+		WHERE assign_date BETWEEN ? AND ?
+		  AND agent_frt > 0
+		  AND user_id IS NOT NULL
+		  AND resolution_time_total > 0
+		  AND %s
+	`, channelCondition)
+
+	spans, err := queryHandleSpans(ctx, r.db, query, callArgs(startDate, endDate, channelParams)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса открытых чатов агентов: %v", err)
+	}
+	return spans, nil
+}
+
+func (r *mysqlChatReports) AgentChats(ctx context.Context, userID, startDate, endDate string, channels []string) ([]AgentChat, error) {
+	channelCondition, channelParams := inCondition("queue_name", channels)
+	query := fmt.Sprintf(`
+		SELECT
+		  DATE(assign_date) AS Day,
+		  TIME_TO_SEC(assign_date) AS Second,
+		  agent_frt,
+		  resolution_time_total
+		FROM chat_report
+		WHERE assign_date BETWEEN ? AND ?
+		  AND agent_frt > 0
+		  AND %s
+		  AND user_id = ?
+		ORDER BY assign_date
+	`, channelCondition)
+
+	rows, err := r.db.QueryContext(ctx, query, append(callArgs(startDate, endDate, channelParams), userID)...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса чатов агента: %v", err)
+	}
+	defer rows.Close()
+
+	chats := make([]AgentChat, 0)
+	for rows.Next() {
+		var date time.Time
+		var rt sql.NullFloat64
+		var chat AgentChat
+		if err := rows.Scan(&date, &chat.Second, &chat.FRT, &rt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения чатов агента: %v", err)
+		}
+		chat.Date = date.Format(dateLayout)
+		chat.RT, chat.HasRT = rt.Float64, rt.Valid
+		chats = append(chats, chat)
+	}
+	return chats, rows.Err()
+}
+
+func (r *mysqlChatReports) QueueUsage(ctx context.Context, startDate, endDate string) ([]QueueUsage, error) {
+	usage, err := queryQueueUsage(ctx, r.db, "chat_report", "created_date", startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса каналов чатов: %v", err)
+	}
+	return usage, nil
+}
+
+func (r *mysqlAgents) Names(ctx context.Context, userIDs []string) (map[string]string, error) {
+	names := make(map[string]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return names, nil
+	}
+
+	// Без user_id имя не связать с агентом: вызывающий покажет user_id
+	var columns int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM information_schema.columns
+		WHERE table_schema = 'omni'
+		  AND table_name = 'conversation'
+		  AND column_name IN ('user_id', 'full_name')
+	`).Scan(&columns)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки справочника имен агентов: %v", err)
+	}
+	if columns < 2 {
+		return nil, fmt.Errorf("в omni.conversation нет столбца user_id или full_name, справочник имен агентов недоступен")
+	}
+
+	userCondition, userParams := inCondition("user_id", userIDs)
+	query := fmt.Sprintf(`
+		SELECT
+		  user_id,
+		  MAX(full_name) AS full_name
+		FROM omni.conversation
+		WHERE full_name IS NOT NULL
+		  AND full_name <> ''
+		  AND %s
+		GROUP BY user_id
+	`, userCondition)
+
+	rows, err := r.db.QueryContext(ctx, query, userParams...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса имен агентов: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID, name string
+		if err := rows.Scan(&userID, &name); err != nil {
+			return nil, fmt.Errorf("ошибка чтения имен агентов: %v", err)
+		}
+		names[userID] = name
+	}
+	return names, rows.Err()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// requestRegistry контексты выполняющихся запросов frontend по идентификатору.
+// Нулевое значение готово к работе.
+type requestRegistry struct {
+	mu       sync.Mutex
+	requests map[string]*runningRequest
+}
+
+// runningRequest выполняющийся запрос
+type runningRequest struct {
+	cancel context.CancelFunc
+}
+
+// begin регистрирует запрос и возвращает его контекст. Если запрос с тем же
+// идентификатором еще выполняется, он отменяется: новый запрос его заменяет.
+// Пустой идентификатор не регистрируется, такой запрос нельзя отменить.
+// Возвращаемую функцию нужно вызвать по завершении запроса.
+func (r *requestRegistry) begin(parent context.Context, id string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	if id == "" {
+		return ctx, cancel
+	}
+
+	request := &runningRequest{cancel: cancel}
+	r.mu.Lock()
+	if r.requests == nil {
+		r.requests = make(map[string]*runningRequest)
+	}
+	if previous, ok := r.requests[id]; ok {
+		log.Printf("Запрос %s заменен новым, предыдущий отменен", id)
+		previous.cancel()
+	}
+	r.requests[id] = request
+	r.mu.Unlock()
+
+	return ctx, func() {
+		r.mu.Lock()
+		if r.requests[id] == request {
+			delete(r.requests, id)
+		}
+		r.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancel отменяет запрос; false, если такой запрос не выполняется
+func (r *requestRegistry) cancel(id string) bool {
+	r.mu.Lock()
+	request, ok := r.requests[id]
+	delete(r.requests, id)
+	r.mu.Unlock()
+
+	if ok {
+		request.cancel()
+	}
+	return ok
+}
+
+// startRequest создает контекст запроса frontend с идентификатором id
+func (a *App) startRequest(id string) (context.Context, func()) {
+	parent := a.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	return a.requests.begin(parent, id)
+}
+
+// CancelRequest отменяет выполняющийся запрос: его запросы к MySQL и MongoDB
+// прерываются, метод возвращает ошибку отмены. Возвращает false, если запрос
+// уже завершен или не найден.
+func (a *App) CancelRequest(requestID string) bool {
+	if !a.requests.cancel(requestID) {
+		return false
+	}
+	log.Printf("Запрос %s отменен", requestID)
+	return true
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingTopics ждет отмены контекста в Topics и сообщает о начале запроса
+type blockingTopics struct {
+	ClassifierRepository
+	started chan struct{}
+}
+
+func (b blockingTopics) Topics(ctx context.Context, startDate, endDate string, queues []string) ([]TopicResult, error) {
+	close(b.started)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(5 * time.Second):
+		return nil, errors.New("запрос не отменен")
+	}
+}
+
+func TestCancelRequest(t *testing.T) {
+	source := newFakeSource(testCalls, testChats, testRequests)
+	started := make(chan struct{})
+	source.Classifiers = blockingTopics{source.Classifiers, started}
+	app := &App{repos: source}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := app.GetTopics("topics-1", "2024-03-01", "2024-03-02", "all")
+		done <- err
+	}()
+
+	<-started
+	if !app.CancelRequest("topics-1") {
+		t.Fatal("CancelRequest не нашел выполняющийся запрос")
+	}
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("ожидалась ошибка отмены, получено %v", err)
+	}
+	if app.CancelRequest("topics-1") {
+		t.Error("завершенный запрос не должен отменяться повторно")
+	}
+}
+
+func TestRequestRegistry(t *testing.T) {
+	var registry requestRegistry
+
+	first, endFirst := registry.begin(context.Background(), "daily")
+	second, endSecond := registry.begin(context.Background(), "daily")
+	if first.Err() == nil {
+		t.Error("запрос с тем же идентификатором должен отменять предыдущий")
+	}
+
+	// Завершение замененного запроса не снимает регистрацию нового
+	endFirst()
+	if second.Err() != nil {
+		t.Fatal("новый запрос отменен раньше времени")
+	}
+	if !registry.cancel("daily") || second.Err() == nil {
+		t.Error("новый запрос должен отменяться по идентификатору")
+	}
+	endSecond()
+
+	anonymous, end := registry.begin(context.Background(), "")
+	if registry.cancel("") {
+		t.Error("запрос без идентификатора не регистрируется")
+	}
+	end()
+	if anonymous.Err() == nil {
+		t.Error("контекст запроса должен отменяться по завершении")
+	}
+}
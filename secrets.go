@@ -0,0 +1,321 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Ключи секретов в хранилище
+const (
+	secretMySQLPassword   = "mysql.password"
+	secretMongoDBPassword = "mongodb.password"
+)
+
+// Имя файла зашифрованного хранилища в каталоге конфигурации
+const secretsFileName = "secrets.enc"
+
+// Имя сервиса, под которым секреты сохраняются в системном хранилище
+const keyringService = "cc-dashboard"
+
+var (
+	// ErrSecretNotFound секрет с таким ключом не сохранен
+	ErrSecretNotFound = errors.New("секрет не найден")
+	// ErrSecretStoreLocked хранилище не разблокировано мастер-фразой
+	ErrSecretStoreLocked = errors.New("хранилище секретов заблокировано")
+)
+
+// SecretStore хранилище паролей баз данных.
+// Реализации никогда не должны логировать значения секретов.
+type SecretStore interface {
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// KeyringProvider абстракция системного хранилища учетных данных
+// (Windows Credential Manager, macOS Keychain, Secret Service)
+type KeyringProvider interface {
+	Get(service, user string) (string, error)
+	Set(service, user, password string) error
+	Delete(service, user string) error
+}
+
+// Зарегистрированный провайдер системного хранилища
+var keyringProvider KeyringProvider
+
+// SetKeyringProvider подключает провайдер системного хранилища учетных данных.
+// Вызывается при запуске до OpenSecretStore (main.go).
+func SetKeyringProvider(provider KeyringProvider) {
+	keyringProvider = provider
+}
+
+// OpenSecretStore открывает хранилище секретов согласно CCDASH_SECRET_STORE:
+// "file" (по умолчанию) - зашифрованный файл, "keyring" - системное хранилище.
+// Файловое хранилище разблокируется из CCDASH_MASTER_PASSPHRASE, если она задана,
+// иначе остается заблокированным до вызова Unlock.
+func OpenSecretStore() (SecretStore, error) {
+	switch kind := os.Getenv(envPrefix + "SECRET_STORE"); kind {
+	case "", "file":
+		path := os.Getenv(envPrefix + "SECRETS_FILE")
+		if path == "" {
+			dir, err := DefaultConfigDir()
+			if err != nil {
+				return nil, err
+			}
+			path = filepath.Join(dir, secretsFileName)
+		}
+
+		store := NewEncryptedFileStore(path)
+		if passphrase := os.Getenv(envPrefix + "MASTER_PASSPHRASE"); passphrase != "" {
+			if err := store.Unlock(passphrase); err != nil {
+				return store, err
+			}
+		}
+		return store, nil
+	case "keyring":
+		if keyringProvider == nil {
+			return nil, fmt.Errorf("системное хранилище учетных данных недоступно на этой платформе")
+		}
+		return &KeyringStore{service: keyringService, provider: keyringProvider}, nil
+	default:
+		return nil, fmt.Errorf("неизвестный тип хранилища секретов: %s", kind)
+	}
+}
+
+// KeyringStore хранит секреты в системном хранилище учетных данных
+type KeyringStore struct {
+	service  string
+	provider KeyringProvider
+}
+
+// Get возвращает секрет из системного хранилища
+func (s *KeyringStore) Get(key string) (string, error) {
+	value, err := s.provider.Get(s.service, key)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// Set сохраняет секрет в системном хранилище
+func (s *KeyringStore) Set(key, value string) error {
+	return s.provider.Set(s.service, key, value)
+}
+
+// Delete удаляет секрет из системного хранилища
+func (s *KeyringStore) Delete(key string) error {
+	return s.provider.Delete(s.service, key)
+}
+
+// Параметры scrypt для получения ключа из мастер-фразы
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 16
+)
+
+// encryptedFile формат файла хранилища на диске
+type encryptedFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// EncryptedFileStore хранит секреты в файле, зашифрованном AES-256-GCM.
+// Ключ получается из мастер-фразы через scrypt, поэтому без нее файл бесполезен.
+type EncryptedFileStore struct {
+	path    string
+	mu      sync.Mutex
+	key     []byte
+	salt    []byte
+	secrets map[string]string
+}
+
+// NewEncryptedFileStore создает заблокированное хранилище для указанного файла
+func NewEncryptedFileStore(path string) *EncryptedFileStore {
+	return &EncryptedFileStore{path: path}
+}
+
+// Unlock получает ключ из мастер-фразы и расшифровывает файл.
+// Если файла еще нет, он будет создан при первом сохранении.
+func (s *EncryptedFileStore) Unlock(passphrase string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		salt := make([]byte, saltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("ошибка генерации соли: %v", err)
+		}
+		key, err := deriveKey(passphrase, salt)
+		if err != nil {
+			return err
+		}
+		s.key, s.salt, s.secrets = key, salt, make(map[string]string)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать хранилище секретов: %v", err)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return fmt.Errorf("поврежден файл хранилища секретов %s: %v", s.path, err)
+	}
+	key, err := deriveKey(passphrase, file.Salt)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	plain, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return fmt.Errorf("неверная мастер-фраза или поврежден файл хранилища секретов")
+	}
+
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return fmt.Errorf("поврежден файл хранилища секретов %s: %v", s.path, err)
+	}
+	s.key, s.salt, s.secrets = key, file.Salt, secrets
+	return nil
+}
+
+// Locked сообщает, требуется ли мастер-фраза
+func (s *EncryptedFileStore) Locked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.key == nil
+}
+
+// Get возвращает расшифрованный секрет
+func (s *EncryptedFileStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key == nil {
+		return "", ErrSecretStoreLocked
+	}
+	value, ok := s.secrets[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// Set сохраняет секрет и перешифровывает файл
+func (s *EncryptedFileStore) Set(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key == nil {
+		return ErrSecretStoreLocked
+	}
+	s.secrets[key] = value
+	return s.save()
+}
+
+// Delete удаляет секрет и перешифровывает файл
+func (s *EncryptedFileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key == nil {
+		return ErrSecretStoreLocked
+	}
+	delete(s.secrets, key)
+	return s.save()
+}
+
+// save шифрует секреты новым nonce и атомарно заменяет файл
+func (s *EncryptedFileStore) save() error {
+	plain, err := json.Marshal(s.secrets)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("ошибка генерации nonce: %v", err)
+	}
+
+	raw, err := json.Marshal(encryptedFile{
+		Version: 1,
+		Salt:    s.salt,
+		Nonce:   nonce,
+		Data:    gcm.Seal(nil, nonce, plain, nil),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("не удалось создать каталог хранилища секретов: %v", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("не удалось записать хранилище секретов: %v", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("не удалось записать хранилище секретов: %v", err)
+	}
+	return nil
+}
+
+// deriveKey получает ключ AES-256 из мастер-фразы
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("мастер-фраза не задана")
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ключа: %v", err)
+	}
+	return key, nil
+}
+
+// newGCM создает AEAD-шифр AES-GCM для ключа
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// redactSecrets вырезает значения секретов (в том числе URL-кодированные)
+// из текста, который может попасть в лог или во frontend
+func redactSecrets(text string, secrets ...string) string {
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		text = strings.ReplaceAll(text, secret, "***")
+		text = strings.ReplaceAll(text, url.QueryEscape(secret), "***")
+	}
+	return text
+}
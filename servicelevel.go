@@ -0,0 +1,60 @@
+package main
+
+import "slices"
+
+// Уровень сервиса по нескольким порогам. Звонки считаются по ожиданию
+// в очереди, чаты — по времени первого ответа (FRT). SL за период
+// пересчитывается из количеств за дни, а не усредняется по дням.
+
+// slThresholds пороги SL в секундах, которые отчет показывает всегда
+var slThresholds = []int{10, 20, 30, 60}
+
+// reportThresholds пороги отчета: стандартные и порог цели, по возрастанию
+func reportThresholds(target SLTarget) []int {
+	return insertSorted(slThresholds, target.ThresholdSeconds)
+}
+
+// insertSorted копия упорядоченного списка с добавленным значением
+func insertSorted(values []int, value int) []int {
+	result := slices.Clone(values)
+	if i, found := slices.BinarySearch(result, value); !found {
+		result = slices.Insert(result, i, value)
+	}
+	return result
+}
+
+// serviceLevelSeries SL по дням и за период из количеств по порогам thresholds
+func serviceLevelSeries(target SLTarget, thresholds []int, counts []SLCounts) ServiceLevelSeries {
+	series := ServiceLevelSeries{
+		Target: target,
+		Days:   make([]ServiceLevelDay, 0, len(counts)),
+	}
+	total := SLCounts{Within: make([]int, len(thresholds))}
+	for _, day := range counts {
+		series.Days = append(series.Days, serviceLevelDay(target, thresholds, day))
+		total.Total += day.Total
+		for i, within := range day.Within {
+			total.Within[i] += within
+		}
+	}
+	series.Total = serviceLevelDay(target, thresholds, total)
+	return series
+}
+
+// serviceLevelDay SL по каждому порогу и сравнение с целью
+func serviceLevelDay(target SLTarget, thresholds []int, counts SLCounts) ServiceLevelDay {
+	day := ServiceLevelDay{
+		Date:       counts.Date,
+		Total:      counts.Total,
+		Thresholds: make([]ThresholdSL, len(thresholds)),
+	}
+	for i, seconds := range thresholds {
+		sl := ratio(float64(counts.Within[i]), counts.Total) * 100
+		day.Thresholds[i] = ThresholdSL{Seconds: seconds, SL: asPercent(sl)}
+		if seconds == target.ThresholdSeconds {
+			day.SL = asPercent(sl)
+			day.Met = counts.Total > 0 && sl >= target.TargetPercent
+		}
+	}
+	return day
+}
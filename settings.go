@@ -0,0 +1,83 @@
+package main
+
+import (
+	"log"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ConnectionSettings текущие настройки подключения для редактора в приложении.
+// Пароли во frontend не передаются: вместо них только признаки наличия.
+type ConnectionSettings struct {
+	Profile            string         `json:"profile"`
+	Config             DatabaseConfig `json:"config"`
+	ConfigPath         string         `json:"config_path"`
+	MySQLPasswordSet   bool           `json:"mysql_password_set"`
+	MongoDBPasswordSet bool           `json:"mongodb_password_set"`
+	SecretStoreLocked  bool           `json:"secret_store_locked"`
+}
+
+// ConnectionTestResult результат проверки предложенной конфигурации
+type ConnectionTestResult struct {
+	OK      bool   `json:"ok"`
+	MySQL   string `json:"mysql"`
+	MongoDB string `json:"mongodb"`
+}
+
+// GetConnectionSettings возвращает текущие настройки подключения без паролей
+func (a *App) GetConnectionSettings() ConnectionSettings {
+	profiles := a.dbService.Profiles()
+	config := profiles.Config()
+	settings := ConnectionSettings{
+		Profile:            profiles.Active,
+		ConfigPath:         a.dbService.ConfigPath(),
+		MySQLPasswordSet:   a.dbService.hasPassword(profiles.Active, secretMySQLPassword, config.MySQL.Password),
+		MongoDBPasswordSet: a.dbService.hasPassword(profiles.Active, secretMongoDBPassword, config.MongoDB.configuredPassword()),
+	}
+	if store, ok := a.dbService.secrets.(*EncryptedFileStore); ok {
+		settings.SecretStoreLocked = store.Locked()
+	}
+
+	config.MySQL.Password = ""
+	config.MongoDB.Password = ""
+	config.MongoDB.URI = withoutURIPassword(config.MongoDB.URI)
+	settings.Config = config
+	return settings
+}
+
+// TestConnectionSettings проверяет предложенные настройки, не меняя текущие
+// соединения. Пустой пароль означает "оставить текущий".
+func (a *App) TestConnectionSettings(config DatabaseConfig) ConnectionTestResult {
+	result := ConnectionTestResult{OK: true, MySQL: "Подключено", MongoDB: "Подключено"}
+
+	if err := config.Validate(); err != nil {
+		return ConnectionTestResult{MySQL: err.Error(), MongoDB: err.Error()}
+	}
+
+	mysqlErr, mongoErr := a.dbService.TestConfig(a.dbService.ActiveProfile(), config)
+	if mysqlErr != nil {
+		result.OK = false
+		result.MySQL = mysqlErr.Error()
+	}
+	if mongoErr != nil {
+		result.OK = false
+		result.MongoDB = mongoErr.Error()
+	}
+	return result
+}
+
+// SaveConnectionSettings проверяет, сохраняет и применяет настройки активного
+// профиля без перезапуска приложения. Уже выполняющиеся запросы дорабатывают
+// на старых соединениях.
+func (a *App) SaveConnectionSettings(config DatabaseConfig) error {
+	profile := a.dbService.ActiveProfile()
+	if err := a.dbService.SaveProfile(profile, config); err != nil {
+		log.Printf("Не удалось применить настройки подключения: %v", err)
+		return err
+	}
+	a.supervisor.Wake()
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "db:profile", profile)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Порт SSH по умолчанию и таймаут подключения к jump-host
+const (
+	defaultSSHPort = "22"
+	sshDialTimeout = 10 * time.Second
+)
+
+// SSHConfig настройки SSH jump-host, через который приложение ходит к базам
+// из-за пределов офисной сети. Если Host не задан, базы доступны напрямую.
+type SSHConfig struct {
+	Host           string `json:"host,omitempty" yaml:"host,omitempty"`
+	Port           string `json:"port,omitempty" yaml:"port,omitempty"`
+	User           string `json:"user,omitempty" yaml:"user,omitempty"`
+	KeyFile        string `json:"key_file,omitempty" yaml:"key_file,omitempty"`
+	KnownHostsFile string `json:"known_hosts,omitempty" yaml:"known_hosts,omitempty"`
+}
+
+// Enabled сообщает, нужно ли ходить к базам через SSH
+func (c SSHConfig) Enabled() bool {
+	return c.Host != ""
+}
+
+// addr возвращает адрес jump-host с портом по умолчанию
+func (c SSHConfig) addr() string {
+	port := c.Port
+	if port == "" {
+		port = defaultSSHPort
+	}
+	return net.JoinHostPort(c.Host, port)
+}
+
+// String описывает jump-host для логов
+func (c SSHConfig) String() string {
+	return c.User + "@" + c.addr()
+}
+
+// problems проверяет настройки SSH. Ключ хоста проверяется всегда,
+// поэтому known_hosts обязателен.
+func (c SSHConfig) problems() []string {
+	if !c.Enabled() {
+		return nil
+	}
+
+	var problems []string
+	for _, field := range []struct{ key, value string }{
+		{"ssh.user", c.User},
+		{"ssh.key_file", c.KeyFile},
+		{"ssh.known_hosts", c.KnownHostsFile},
+	} {
+		if field.value == "" {
+			problems = append(problems, missingField(field.key))
+		}
+	}
+	if c.Port != "" {
+		if n, err := strconv.Atoi(c.Port); err != nil || n < 1 || n > 65535 {
+			problems = append(problems, fmt.Sprintf("некорректный порт ssh.port: %q", c.Port))
+		}
+	}
+	return problems
+}
+
+// clientConfig собирает настройки SSH-клиента: ключ пользователя и проверку
+// ключа сервера по known_hosts
+func (c SSHConfig) clientConfig() (*ssh.ClientConfig, error) {
+	key, err := os.ReadFile(c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать SSH-ключ %s: %v", c.KeyFile, err)
+	}
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось разобрать SSH-ключ %s: %v", c.KeyFile, err)
+	}
+
+	hostKeyCallback, err := knownhosts.New(c.KnownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать known_hosts %s: %v", c.KnownHostsFile, err)
+	}
+
+	return &ssh.ClientConfig{
+		User:            c.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sshDialTimeout,
+	}, nil
+}
+
+// sshForward локальный проброс порта через SSH jump-host.
+// Драйвер базы подключается к Addr(), а каждое соединение уходит
+// через SSH на удаленный адрес базы.
+type sshForward struct {
+	client   *ssh.Client
+	listener net.Listener
+	remote   string
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+}
+
+// openSSHForward подключается к jump-host и открывает на 127.0.0.1
+// локальный порт, проброшенный на remote
+func openSSHForward(config SSHConfig, remote string) (*sshForward, error) {
+	if err := configProblems(config.problems()); err != nil {
+		return nil, err
+	}
+	clientConfig, err := config.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := ssh.Dial("tcp", config.addr(), clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось подключиться к SSH %s: %v", config, err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("не удалось открыть локальный порт для SSH-туннеля: %v", err)
+	}
+
+	forward := &sshForward{
+		client:   client,
+		listener: listener,
+		remote:   remote,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	go forward.serve()
+
+	log.Printf("SSH-туннель %s -> %s через %s", listener.Addr(), remote, config)
+	return forward, nil
+}
+
+// Addr возвращает локальный адрес проброса
+func (f *sshForward) Addr() string {
+	return f.listener.Addr().String()
+}
+
+// serve принимает локальные соединения до закрытия проброса
+func (f *sshForward) serve() {
+	for {
+		local, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(local)
+	}
+}
+
+// handle соединяет локальное соединение с базой через SSH
+func (f *sshForward) handle(local net.Conn) {
+	remote, err := f.client.Dial("tcp", f.remote)
+	if err != nil {
+		log.Printf("SSH-туннель: не удалось подключиться к %s: %v", f.remote, err)
+		local.Close()
+		return
+	}
+	if !f.track(local, remote) {
+		local.Close()
+		remote.Close()
+		return
+	}
+	defer f.untrack(local, remote)
+
+	done := make(chan struct{})
+	go func() {
+		io.Copy(remote, local)
+		remote.Close()
+		close(done)
+	}()
+	io.Copy(local, remote)
+	local.Close()
+	<-done
+}
+
+// track запоминает активные соединения, чтобы закрыть их вместе с пробросом
+func (f *sshForward) track(conns ...net.Conn) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return false
+	}
+	for _, conn := range conns {
+		f.conns[conn] = struct{}{}
+	}
+	return true
+}
+
+// untrack забывает завершенные соединения
+func (f *sshForward) untrack(conns ...net.Conn) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, conn := range conns {
+		delete(f.conns, conn)
+	}
+}
+
+// Close закрывает локальный порт, активные соединения и SSH-клиент
+func (f *sshForward) Close() {
+	if f == nil {
+		return
+	}
+
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return
+	}
+	f.closed = true
+	conns := f.conns
+	f.conns = nil
+	f.mu.Unlock()
+
+	f.listener.Close()
+	for conn := range conns {
+		conn.Close()
+	}
+	f.client.Close()
+	log.Printf("SSH-туннель %s -> %s закрыт", f.listener.Addr(), f.remote)
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// testSSHServer SSH-сервер в процессе теста, который пускает только
+// авторизованный ключ и обслуживает пробросы direct-tcpip
+type testSSHServer struct {
+	addr    string
+	hostKey ssh.Signer
+}
+
+func newTestSigner(t *testing.T) (ssh.Signer, ed25519.PrivateKey) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer, key
+}
+
+func startTestSSHServer(t *testing.T, authorized ssh.PublicKey) *testSSHServer {
+	t.Helper()
+	hostKey, _ := newTestSigner(t)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "tunnel" && bytes.Equal(key.Marshal(), authorized.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unauthorized")
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSSHConn(conn, config)
+		}
+	}()
+	return &testSSHServer{addr: listener.Addr().String(), hostKey: hostKey}
+}
+
+func serveTestSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	serverConn, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer serverConn.Close()
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			newChannel.Reject(ssh.UnknownChannelType, "only direct-tcpip")
+			continue
+		}
+		var target struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		if err := ssh.Unmarshal(newChannel.ExtraData(), &target); err != nil {
+			newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		remote, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+		if err != nil {
+			newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			remote.Close()
+			continue
+		}
+		go ssh.DiscardRequests(channelRequests)
+		go func() {
+			defer channel.Close()
+			defer remote.Close()
+			go io.Copy(remote, channel)
+			io.Copy(channel, remote)
+		}()
+	}
+}
+
+// startEchoServer изображает базу данных: возвращает все, что получил
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// newTestSSHConfig пишет ключ клиента и known_hosts и возвращает настройки jump-host
+func newTestSSHConfig(t *testing.T, server *testSSHServer, clientKey ed25519.PrivateKey, knownKey ssh.PublicKey) SSHConfig {
+	t.Helper()
+	dir := t.TempDir()
+
+	block, err := ssh.MarshalPrivateKey(clientKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	knownHostsFile := filepath.Join(dir, "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(server.addr)}, knownKey) + "\n"
+	if err := os.WriteFile(knownHostsFile, []byte(line), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	host, port, err := net.SplitHostPort(server.addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return SSHConfig{Host: host, Port: port, User: "tunnel", KeyFile: keyFile, KnownHostsFile: knownHostsFile}
+}
+
+func roundTrip(addr, message string) (string, error) {
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if _, err := conn.Write([]byte(message)); err != nil {
+		return "", err
+	}
+	reply := make([]byte, len(message))
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return "", err
+	}
+	return string(reply), nil
+}
+
+func TestSSHForward(t *testing.T) {
+	clientSigner, clientKey := newTestSigner(t)
+	server := startTestSSHServer(t, clientSigner.PublicKey())
+	database := startEchoServer(t)
+	config := newTestSSHConfig(t, server, clientKey, server.hostKey.PublicKey())
+
+	forward, err := openSSHForward(config, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Несколько соединений через один проброс, как у пула драйвера
+	for _, message := range []string{"SELECT 1", "ping"} {
+		reply, err := roundTrip(forward.Addr(), message)
+		if err != nil {
+			t.Fatalf("round trip %q: %v", message, err)
+		}
+		if reply != message {
+			t.Fatalf("reply = %q, want %q", reply, message)
+		}
+	}
+
+	forward.Close()
+	if _, err := roundTrip(forward.Addr(), "after close"); err == nil {
+		t.Fatal("forward must not accept connections after Close")
+	}
+	forward.Close() // повторное закрытие безопасно
+}
+
+func TestSSHForwardRejectsUnknownHostKey(t *testing.T) {
+	clientSigner, clientKey := newTestSigner(t)
+	server := startTestSSHServer(t, clientSigner.PublicKey())
+	otherKey, _ := newTestSigner(t)
+	config := newTestSSHConfig(t, server, clientKey, otherKey.PublicKey())
+
+	_, err := openSSHForward(config, startEchoServer(t))
+	if err == nil || !strings.Contains(err.Error(), "не удалось подключиться к SSH") {
+		t.Fatalf("expected host key mismatch error, got %v", err)
+	}
+}
+
+func TestSSHForwardRejectsUnauthorizedKey(t *testing.T) {
+	authorized, _ := newTestSigner(t)
+	server := startTestSSHServer(t, authorized.PublicKey())
+	_, clientKey := newTestSigner(t)
+	config := newTestSSHConfig(t, server, clientKey, server.hostKey.PublicKey())
+
+	if _, err := openSSHForward(config, startEchoServer(t)); err == nil {
+		t.Fatal("expected authentication error")
+	}
+}
+
+func TestDatabaseConfigValidateSSH(t *testing.T) {
+	base := DatabaseConfig{
+		MySQL:   MySQLConfig{Host: "192.168.46.4", Port: "3306", User: "report_user", Database: "report"},
+		MongoDB: MongoDBConfig{Host: "192.168.46.4", Port: "27017", User: "readonly", Database: "request"},
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(c *DatabaseConfig)
+		wantErr []string
+	}{
+		{"no ssh", func(c *DatabaseConfig) {}, nil},
+		{"complete ssh", func(c *DatabaseConfig) {
+			c.SSH = SSHConfig{Host: "jump.example.com", User: "tunnel", KeyFile: "id_ed25519", KnownHostsFile: "known_hosts"}
+		}, nil},
+		{"missing key and known_hosts", func(c *DatabaseConfig) {
+			c.SSH = SSHConfig{Host: "jump.example.com", User: "tunnel", Port: "0"}
+		}, []string{"ssh.key_file", "ssh.known_hosts", "ssh.port"}},
+		{"ssh with mongodb uri", func(c *DatabaseConfig) {
+			c.SSH = SSHConfig{Host: "jump.example.com", User: "tunnel", KeyFile: "id_ed25519", KnownHostsFile: "known_hosts"}
+			c.MongoDB.URI = "mongodb://db1,db2/request?replicaSet=rs0"
+		}, []string{"mongodb.uri"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := base
+			tt.modify(&config)
+			err := config.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %s", err, want)
+				}
+			}
+		})
+	}
+}
+
+// Без полной проверки DatabaseConfig туннель не открывается к mongodb.host
+// при заданной строке подключения
+func TestOpenMongoDBRejectsURIOverSSH(t *testing.T) {
+	config := MongoDBConfig{URI: "mongodb://readonly@db1,db2/request?replicaSet=rs0"}
+	tunnel := SSHConfig{Host: "127.0.0.1", Port: "1", User: "tunnel", KeyFile: "id_ed25519", KnownHostsFile: "known_hosts"}
+
+	_, err := openMongoDB(config, tunnel, "secret")
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || !strings.Contains(err.Error(), "mongodb.uri") {
+		t.Fatalf("expected ConfigError about mongodb.uri, got %v", err)
+	}
+}
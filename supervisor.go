@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Параметры наблюдения за соединениями
+const (
+	// Как часто проверять живые соединения
+	supervisorInterval = 15 * time.Second
+	// Таймаут одной проверки
+	supervisorPingTimeout = 5 * time.Second
+	// Первая и максимальная задержка между попытками переподключения
+	reconnectMinDelay = 2 * time.Second
+	reconnectMaxDelay = 2 * time.Minute
+)
+
+// Состояния соединения, которые видит frontend
+const (
+	statusConnected    = "Подключено"
+	statusDisconnected = "Не подключено"
+	statusReconnecting = "Переподключение"
+)
+
+// DatabaseStatus состояние соединения с одной базой
+type DatabaseStatus struct {
+	State     string `json:"state"`
+	Error     string `json:"error,omitempty"`
+	Attempts  int    `json:"attempts"`
+	NextRetry string `json:"next_retry,omitempty"`
+}
+
+// ConnectionStatus состояние обоих соединений активного профиля.
+// Отправляется во frontend событием db:status при каждом изменении.
+type ConnectionStatus struct {
+	Profile string         `json:"profile"`
+	MySQL   DatabaseStatus `json:"mysql"`
+	MongoDB DatabaseStatus `json:"mongodb"`
+}
+
+// supervisedDB соединение, за которым следит супервизор
+type supervisedDB struct {
+	name    string
+	ping    func(ctx context.Context) error
+	connect func() error
+	status  func(s *ConnectionStatus) *DatabaseStatus
+	wake    chan struct{}
+}
+
+// Supervisor периодически проверяет соединения с MySQL и MongoDB и
+// переподключается с экспоненциальной задержкой, если база недоступна.
+// Каждая база обслуживается своей горутиной, чтобы долгое подключение
+// к одной не задерживало проверку другой.
+type Supervisor struct {
+	ds     *DatabaseService
+	notify func(ConnectionStatus)
+	dbs    []*supervisedDB
+	// now текущее время; в тестах подменяется
+	now func() time.Time
+
+	mu     sync.Mutex
+	status ConnectionStatus
+}
+
+// NewSupervisor создает супервизор соединений. notify вызывается при каждом
+// изменении состояния и может быть nil.
+func NewSupervisor(ds *DatabaseService, notify func(ConnectionStatus)) *Supervisor {
+	s := &Supervisor{
+		ds:     ds,
+		notify: notify,
+		now:    time.Now,
+		status: ConnectionStatus{
+			Profile: ds.ActiveProfile(),
+			MySQL:   DatabaseStatus{State: statusDisconnected},
+			MongoDB: DatabaseStatus{State: statusDisconnected},
+		},
+	}
+	s.dbs = []*supervisedDB{
+		{
+			name:    "MySQL",
+			ping:    ds.PingMySQL,
+			connect: ds.ConnectMySQL,
+			status:  func(s *ConnectionStatus) *DatabaseStatus { return &s.MySQL },
+			wake:    make(chan struct{}, 1),
+		},
+		{
+			name:    "MongoDB",
+			ping:    ds.PingMongoDB,
+			connect: ds.ConnectMongoDB,
+			status:  func(s *ConnectionStatus) *DatabaseStatus { return &s.MongoDB },
+			wake:    make(chan struct{}, 1),
+		},
+	}
+	return s
+}
+
+// Run запускает наблюдение и возвращается сразу; горутины завершаются
+// вместе с ctx
+func (s *Supervisor) Run(ctx context.Context) {
+	for _, db := range s.dbs {
+		go s.watch(ctx, db)
+	}
+}
+
+// Status возвращает последнее известное состояние соединений
+func (s *Supervisor) Status() ConnectionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// Wake запускает внеочередную проверку без ожидания задержки,
+// например после смены профиля или разблокировки хранилища паролей
+func (s *Supervisor) Wake() {
+	for _, db := range s.dbs {
+		select {
+		case db.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// reconnectState задержка и попытки переподключения одной базы
+type reconnectState struct {
+	delay    time.Duration
+	attempts int
+	retryAt  time.Time
+}
+
+// watch проверяет одну базу до отмены ctx
+func (s *Supervisor) watch(ctx context.Context, db *supervisedDB) {
+	state := reconnectState{delay: reconnectMinDelay}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-db.wake:
+			// Явный запрос пользователя: пробуем сразу и сбрасываем задержку
+			state.delay, state.retryAt = reconnectMinDelay, time.Time{}
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		case <-timer.C:
+		}
+
+		next := s.check(ctx, db, &state)
+		if ctx.Err() != nil {
+			return
+		}
+		timer.Reset(next)
+	}
+}
+
+// check проверяет базу один раз и, если соединение потеряно и задержка
+// истекла, переподключается. Возвращает время до следующей проверки.
+func (s *Supervisor) check(ctx context.Context, db *supervisedDB, state *reconnectState) time.Duration {
+	pingCtx, cancel := context.WithTimeout(ctx, supervisorPingTimeout)
+	err := db.ping(pingCtx)
+	cancel()
+
+	if err != nil && !s.now().Before(state.retryAt) {
+		if state.attempts == 0 {
+			log.Printf("Соединение с %s потеряно: %v", db.name, err)
+		}
+		if err = db.connect(); err == nil {
+			log.Printf("Соединение с %s восстановлено", db.name)
+		}
+		if ctx.Err() != nil {
+			return 0
+		}
+		if err != nil {
+			state.attempts++
+			state.retryAt = s.now().Add(state.delay)
+			log.Printf("Переподключение к %s не удалось (попытка %d), следующая через %v: %v",
+				db.name, state.attempts, state.delay, err)
+			state.delay = min(state.delay*2, reconnectMaxDelay)
+		}
+	}
+
+	next := supervisorInterval
+	status := DatabaseStatus{State: statusConnected}
+	if err == nil {
+		*state = reconnectState{delay: reconnectMinDelay}
+	} else {
+		status = DatabaseStatus{
+			State:     statusReconnecting,
+			Error:     err.Error(),
+			Attempts:  state.attempts,
+			NextRetry: state.retryAt.Format(time.RFC3339),
+		}
+		next = min(next, state.retryAt.Sub(s.now()))
+	}
+	s.update(db, status)
+	return next
+}
+
+// update сохраняет состояние базы и оповещает frontend, если оно изменилось
+func (s *Supervisor) update(db *supervisedDB, status DatabaseStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile := s.ds.ActiveProfile()
+	current := db.status(&s.status)
+	if *current == status && s.status.Profile == profile {
+		return
+	}
+	*current = status
+	s.status.Profile = profile
+	if s.notify != nil {
+		s.notify(s.status)
+	}
+}
+
+// PingMySQL проверяет текущее соединение MySQL
+func (ds *DatabaseService) PingMySQL(ctx context.Context) error {
+	session := ds.Session()
+	defer session.Close()
+
+	if session.MySQL == nil {
+		return fmt.Errorf("MySQL соединение не установлено")
+	}
+	return session.MySQL.PingContext(ctx)
+}
+
+// PingMongoDB проверяет текущее соединение MongoDB
+func (ds *DatabaseService) PingMongoDB(ctx context.Context) error {
+	session := ds.Session()
+	defer session.Close()
+
+	if session.MongoDB == nil {
+		return fmt.Errorf("MongoDB соединение не установлено")
+	}
+	return session.MongoDB.Client().Ping(ctx, nil)
+}
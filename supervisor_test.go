@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeDB проверка и подключение базы, которыми управляет тест
+type fakeDB struct {
+	pingErr    error
+	connectErr error
+	connects   int
+}
+
+// newTestSupervisor супервизор с подмененными часами и одной базой MySQL
+func newTestSupervisor(db *fakeDB, now *time.Time) (*Supervisor, *supervisedDB, *[]ConnectionStatus) {
+	ds := NewDatabaseService(ConnectionProfiles{
+		Active:   defaultProfileName,
+		Profiles: map[string]DatabaseConfig{defaultProfileName: DefaultDatabaseConfig()},
+	}, "", nil)
+
+	var notified []ConnectionStatus
+	s := NewSupervisor(ds, func(status ConnectionStatus) { notified = append(notified, status) })
+	s.now = func() time.Time { return *now }
+
+	mysql := s.dbs[0]
+	mysql.ping = func(context.Context) error { return db.pingErr }
+	mysql.connect = func() error {
+		db.connects++
+		if db.connectErr == nil {
+			db.pingErr = nil
+		}
+		return db.connectErr
+	}
+	return s, mysql, &notified
+}
+
+func TestSupervisorBackoff(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	db := &fakeDB{pingErr: errors.New("connection refused"), connectErr: errors.New("connection refused")}
+	s, mysql, _ := newTestSupervisor(db, &now)
+	state := reconnectState{delay: reconnectMinDelay}
+
+	// Задержка удваивается от 2 с до 2 мин
+	wantDelays := []time.Duration{
+		2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second,
+		32 * time.Second, 64 * time.Second, 2 * time.Minute, 2 * time.Minute,
+	}
+	for i, want := range wantDelays {
+		next := s.check(context.Background(), mysql, &state)
+		if delay := state.retryAt.Sub(now); delay != want {
+			t.Fatalf("attempt %d: retry in %v, want %v", i+1, delay, want)
+		}
+		// Живое соединение проверяется не реже supervisorInterval
+		if next != min(want, supervisorInterval) {
+			t.Fatalf("attempt %d: next check in %v", i+1, next)
+		}
+		if state.attempts != i+1 || db.connects != i+1 {
+			t.Fatalf("attempt %d: attempts = %d, connects = %d", i+1, state.attempts, db.connects)
+		}
+		if got := s.Status().MySQL; got.State != statusReconnecting || got.Attempts != i+1 {
+			t.Fatalf("attempt %d: status = %+v", i+1, got)
+		}
+		if i < len(wantDelays)-1 {
+			now = state.retryAt
+		}
+	}
+
+	// Пока задержка не истекла, переподключения нет
+	now = now.Add(time.Minute)
+	s.check(context.Background(), mysql, &state)
+	if db.connects != len(wantDelays) {
+		t.Fatalf("connects before retry time = %d, want %d", db.connects, len(wantDelays))
+	}
+
+	// Успешное подключение сбрасывает задержку и попытки
+	now = state.retryAt
+	db.connectErr = nil
+	if next := s.check(context.Background(), mysql, &state); next != supervisorInterval {
+		t.Fatalf("after reconnect: next check in %v, want %v", next, supervisorInterval)
+	}
+	if state.attempts != 0 || state.delay != reconnectMinDelay {
+		t.Fatalf("state after reconnect = %+v", state)
+	}
+	if got := s.Status().MySQL; got != (DatabaseStatus{State: statusConnected}) {
+		t.Fatalf("status after reconnect = %+v", got)
+	}
+
+	// Новая потеря соединения снова начинается с 2 с
+	db.pingErr, db.connectErr = errors.New("broken pipe"), errors.New("connection refused")
+	if next := s.check(context.Background(), mysql, &state); next != reconnectMinDelay {
+		t.Fatalf("after new failure: next check in %v, want %v", next, reconnectMinDelay)
+	}
+}
+
+func TestSupervisorNotifiesOnChange(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	db := &fakeDB{}
+	s, mysql, notified := newTestSupervisor(db, &now)
+	state := reconnectState{delay: reconnectMinDelay}
+
+	// Подключено: одно событие, повторные проверки без изменений молчат
+	for i := 0; i < 3; i++ {
+		s.check(context.Background(), mysql, &state)
+		now = now.Add(supervisorInterval)
+	}
+	if len(*notified) != 1 || (*notified)[0].MySQL.State != statusConnected {
+		t.Fatalf("notified = %+v, want one connected status", *notified)
+	}
+
+	// Неудачная попытка меняет состояние, проверка до срока повтора - нет
+	db.pingErr, db.connectErr = errors.New("connection refused"), errors.New("connection refused")
+	s.check(context.Background(), mysql, &state)
+	now = now.Add(time.Second)
+	s.check(context.Background(), mysql, &state)
+	if len(*notified) != 2 {
+		t.Fatalf("notified %d times, want 2: %+v", len(*notified), *notified)
+	}
+	if got := (*notified)[1].MySQL; got.State != statusReconnecting || got.Attempts != 1 ||
+		got.NextRetry != "2024-03-01T09:00:47Z" {
+		t.Fatalf("reconnecting status = %+v", got)
+	}
+
+	// Следующая попытка увеличивает счетчик - новое событие
+	now = now.Add(time.Second)
+	s.check(context.Background(), mysql, &state)
+	if len(*notified) != 3 || (*notified)[2].MySQL.Attempts != 2 {
+		t.Fatalf("notified = %+v, want third status with 2 attempts", *notified)
+	}
+
+	// Восстановление - событие, дальше снова тишина
+	db.connectErr = nil
+	now = now.Add(4 * time.Second)
+	s.check(context.Background(), mysql, &state)
+	s.check(context.Background(), mysql, &state)
+	if len(*notified) != 4 || (*notified)[3].MySQL.State != statusConnected {
+		t.Fatalf("notified = %+v, want fourth connected status", *notified)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// Режимы TLS для соединений с базами
+const (
+	tlsModeDisabled   = "disabled"
+	tlsModeVerify     = "verify"
+	tlsModeSkipVerify = "skip-verify"
+)
+
+// TLSConfig настройки TLS соединения с базой.
+// Mode: "disabled" (по умолчанию), "verify" - проверять сертификат сервера,
+// "skip-verify" - не проверять (только для тестовых стендов).
+type TLSConfig struct {
+	Mode     string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	CAFile   string `json:"ca_file,omitempty" yaml:"ca_file,omitempty"`
+	CertFile string `json:"cert_file,omitempty" yaml:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty" yaml:"key_file,omitempty"`
+}
+
+// Enabled сообщает, включен ли TLS
+func (c TLSConfig) Enabled() bool {
+	return c.Mode != "" && c.Mode != tlsModeDisabled
+}
+
+// String кратко описывает режим TLS для логов
+func (c TLSConfig) String() string {
+	if !c.Enabled() {
+		return "без TLS"
+	}
+	if c.CertFile != "" {
+		return "TLS " + c.Mode + ", клиентский сертификат"
+	}
+	return "TLS " + c.Mode
+}
+
+// problems проверяет согласованность настроек TLS секции
+func (c TLSConfig) problems(section string) []string {
+	var problems []string
+	switch c.Mode {
+	case "", tlsModeDisabled, tlsModeVerify, tlsModeSkipVerify:
+	default:
+		problems = append(problems, fmt.Sprintf("некорректный режим %s.tls.mode: %q (допустимо %s, %s, %s)",
+			section, c.Mode, tlsModeDisabled, tlsModeVerify, tlsModeSkipVerify))
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		problems = append(problems, fmt.Sprintf("%s.tls.cert_file и %s.tls.key_file задаются вместе", section, section))
+	}
+	if !c.Enabled() && (c.CAFile != "" || c.CertFile != "") {
+		problems = append(problems, fmt.Sprintf("заданы файлы %s.tls, но TLS выключен (%s)", section,
+			configField{key: section + ".tls.mode"}.envName()))
+	}
+	return problems
+}
+
+// clientConfig собирает *tls.Config для подключения к host.
+// Возвращает nil, если TLS выключен.
+func (c TLSConfig) clientConfig(host string) (*tls.Config, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		ServerName:         host,
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.Mode == tlsModeSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать CA %s: %v", c.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("в файле %s нет сертификатов в формате PEM", c.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось загрузить клиентский сертификат %s: %v", c.CertFile, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
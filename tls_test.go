@@ -0,0 +1,347 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPKI локально сгенерированные CA, сертификат сервера и клиента
+type testPKI struct {
+	dir        string
+	caFile     string
+	serverCert tls.Certificate
+	caPool     *x509.CertPool
+	clientCert string
+	clientKey  string
+}
+
+// newTestPKI выпускает CA и подписанные им сертификаты для localhost
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey := newTestKey(t)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cc-dashboard test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue := func(serial int64, name string, usage x509.ExtKeyUsage) ([]byte, *ecdsa.PrivateKey) {
+		key := newTestKey(t)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der, key
+	}
+
+	pki := &testPKI{dir: dir, caPool: x509.NewCertPool()}
+	pki.caPool.AddCert(caCert)
+	pki.caFile = writePEM(t, dir, "ca.pem", "CERTIFICATE", caDER)
+
+	serverDER, serverKey := issue(2, "localhost", x509.ExtKeyUsageServerAuth)
+	pki.serverCert = tls.Certificate{Certificate: [][]byte{serverDER}, PrivateKey: serverKey}
+
+	clientDER, clientKey := issue(3, "report_user", x509.ExtKeyUsageClientAuth)
+	pki.clientCert = writePEM(t, dir, "client.pem", "CERTIFICATE", clientDER)
+	keyDER, err := x509.MarshalECPrivateKey(clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pki.clientKey = writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDER)
+	return pki
+}
+
+func newTestKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// startTLSServer запускает TLS-сервер, который требует клиентский сертификат
+// от того же CA и отвечает "ok" после рукопожатия
+func startTLSServer(t *testing.T, pki *testPKI) string {
+	t.Helper()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{pki.serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pki.caPool,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if err := conn.(*tls.Conn).Handshake(); err == nil {
+					conn.Write([]byte("ok"))
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// dialTLS подключается к серверу с настройками, собранными из TLSConfig
+func dialTLS(t *testing.T, addr string, config TLSConfig) error {
+	t.Helper()
+	tlsConfig, err := config.clientConfig("localhost")
+	if err != nil {
+		t.Fatalf("clientConfig: %v", err)
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", addr, tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// В TLS 1.3 отказ сервера в клиентском сертификате приходит при чтении
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	reply := make([]byte, 2)
+	if _, err := conn.Read(reply); err != nil {
+		return err
+	}
+	if string(reply) != "ok" {
+		return errors.New("неожиданный ответ сервера: " + string(reply))
+	}
+	return nil
+}
+
+func TestTLSConfigHandshake(t *testing.T) {
+	pki := newTestPKI(t)
+	addr := startTLSServer(t, pki)
+
+	tests := []struct {
+		name    string
+		config  TLSConfig
+		wantErr bool
+	}{
+		{
+			name:   "verify with CA and client cert",
+			config: TLSConfig{Mode: tlsModeVerify, CAFile: pki.caFile, CertFile: pki.clientCert, KeyFile: pki.clientKey},
+		},
+		{
+			name:    "verify without CA bundle",
+			config:  TLSConfig{Mode: tlsModeVerify, CertFile: pki.clientCert, KeyFile: pki.clientKey},
+			wantErr: true,
+		},
+		{
+			name:   "skip-verify without CA bundle",
+			config: TLSConfig{Mode: tlsModeSkipVerify, CertFile: pki.clientCert, KeyFile: pki.clientKey},
+		},
+		{
+			name:    "verify without client cert",
+			config:  TLSConfig{Mode: tlsModeVerify, CAFile: pki.caFile},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dialTLS(t, addr, tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("dial error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTLSConfigDisabled(t *testing.T) {
+	for _, mode := range []string{"", tlsModeDisabled} {
+		tlsConfig, err := TLSConfig{Mode: mode}.clientConfig("localhost")
+		if err != nil || tlsConfig != nil {
+			t.Fatalf("mode %q: got %v, %v; want nil, nil", mode, tlsConfig, err)
+		}
+	}
+}
+
+func TestTLSConfigFileErrors(t *testing.T) {
+	pki := newTestPKI(t)
+	notPEM := filepath.Join(pki.dir, "not-pem.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		config TLSConfig
+		want   string
+	}{
+		{"missing CA", TLSConfig{Mode: tlsModeVerify, CAFile: filepath.Join(pki.dir, "missing.pem")}, "не удалось прочитать CA"},
+		{"CA without PEM", TLSConfig{Mode: tlsModeVerify, CAFile: notPEM}, "нет сертификатов"},
+		{"key does not match", TLSConfig{Mode: tlsModeVerify, CertFile: pki.clientCert, KeyFile: pki.caFile}, "клиентский сертификат"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.config.clientConfig("localhost")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMongoDBConfigValidateAuth(t *testing.T) {
+	base := MongoDBConfig{Host: "localhost", Port: "27017", User: "readonly", Database: "request"}
+	tlsWithCert := TLSConfig{Mode: tlsModeVerify, CertFile: "client.pem", KeyFile: "client-key.pem"}
+
+	tests := []struct {
+		name    string
+		modify  func(c *MongoDBConfig)
+		wantErr string
+	}{
+		{"SCRAM-SHA-256", func(c *MongoDBConfig) { c.AuthMechanism = mongoAuthSCRAMSHA256 }, ""},
+		{"X.509 without user", func(c *MongoDBConfig) {
+			c.AuthMechanism, c.User, c.TLS = mongoAuthX509, "", tlsWithCert
+		}, ""},
+		{"X.509 without TLS", func(c *MongoDBConfig) { c.AuthMechanism = mongoAuthX509 }, "MONGODB-X509"},
+		{"unknown mechanism", func(c *MongoDBConfig) { c.AuthMechanism = "PLAIN" }, "неподдерживаемый"},
+		{"unknown TLS mode", func(c *MongoDBConfig) { c.TLS.Mode = "required" }, "mongodb.tls.mode"},
+		{"cert without key", func(c *MongoDBConfig) {
+			c.TLS = TLSConfig{Mode: tlsModeVerify, CertFile: "client.pem"}
+		}, "задаются вместе"},
+		{"files with TLS disabled", func(c *MongoDBConfig) { c.TLS.CAFile = "ca.pem" }, "TLS выключен"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := base
+			tt.modify(&config)
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMongoClientOptionsAuth(t *testing.T) {
+	pki := newTestPKI(t)
+
+	config := MongoDBConfig{Host: "mongo.local", Port: "27017", User: "readonly", Database: "request",
+		AuthMechanism: mongoAuthSCRAMSHA256}
+	clientOptions, err := mongoClientOptions(config, "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := clientOptions.Auth
+	if auth.AuthMechanism != mongoAuthSCRAMSHA256 || auth.AuthSource != "admin" ||
+		auth.Username != "readonly" || auth.Password != "s3cret" {
+		t.Fatalf("unexpected SCRAM credential: %+v", auth)
+	}
+	if clientOptions.TLSConfig != nil {
+		t.Fatal("TLS must stay disabled by default")
+	}
+	if got := clientOptions.Hosts; len(got) != 1 || got[0] != "mongo.local:27017" {
+		t.Fatalf("hosts = %v", got)
+	}
+
+	config.AuthSource = "request"
+	clientOptions, err = mongoClientOptions(config, "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clientOptions.Auth.AuthSource != "request" {
+		t.Fatalf("explicit authSource ignored: %+v", clientOptions.Auth)
+	}
+
+	x509Config := MongoDBConfig{Host: "mongo.local", Port: "27017", Database: "request",
+		AuthMechanism: mongoAuthX509,
+		TLS:           TLSConfig{Mode: tlsModeVerify, CAFile: pki.caFile, CertFile: pki.clientCert, KeyFile: pki.clientKey}}
+	clientOptions, err = mongoClientOptions(x509Config, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth = clientOptions.Auth
+	if auth.AuthMechanism != mongoAuthX509 || auth.AuthSource != "$external" || auth.PasswordSet {
+		t.Fatalf("unexpected X.509 credential: %+v", auth)
+	}
+	if clientOptions.TLSConfig == nil || len(clientOptions.TLSConfig.Certificates) != 1 ||
+		clientOptions.TLSConfig.RootCAs == nil || clientOptions.TLSConfig.ServerName != "mongo.local" {
+		t.Fatalf("unexpected TLS config: %+v", clientOptions.TLSConfig)
+	}
+}
+
+func TestMySQLDriverConfigTLS(t *testing.T) {
+	pki := newTestPKI(t)
+
+	config := MySQLConfig{Host: "mysql.local", Port: "3306", User: "report_user", Database: "report"}
+	cfg, err := mysqlDriverConfig(config, "p@ss:word")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TLS != nil || cfg.Addr != "mysql.local:3306" || cfg.Passwd != "p@ss:word" || !cfg.ParseTime {
+		t.Fatalf("unexpected plain config: %+v", cfg)
+	}
+
+	config.TLS = TLSConfig{Mode: tlsModeSkipVerify, CAFile: pki.caFile, CertFile: pki.clientCert, KeyFile: pki.clientKey}
+	cfg, err = mysqlDriverConfig(config, "p@ss:word")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TLS == nil || !cfg.TLS.InsecureSkipVerify || len(cfg.TLS.Certificates) != 1 {
+		t.Fatalf("unexpected TLS config: %+v", cfg.TLS)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+// Ожидание отвеченных звонков: ASA (среднее ожидание), максимум и перцентили.
+// Репозиторий отдает количества звонков по значениям ожидания, перцентили
+// считаются как у остальных распределений длительностей (distribution.go).
+
+// waitMetrics метрики ожидания отвеченных звонков, сек
+var waitMetrics = []string{"asa", "max_wait", "wait_p50", "wait_p90", "wait_p95"}
+
+// waitStats показатели ожидания группы звонков
+type waitStats struct {
+	asa, max, p50, p90, p95 float64
+}
+
+// value значение метрики ожидания
+func (s waitStats) value(metric string) float64 {
+	switch metric {
+	case "asa":
+		return s.asa
+	case "max_wait":
+		return s.max
+	case "wait_p50":
+		return s.p50
+	case "wait_p90":
+		return s.p90
+	case "wait_p95":
+		return s.p95
+	}
+	return 0
+}
+
+// summarizeWaits считает показатели ожидания; false — звонков нет
+func summarizeWaits(counts []WaitCount) (waitStats, bool) {
+	values := make([]ValueCount, len(counts))
+	for i, count := range counts {
+		values[i] = ValueCount{Value: count.Wait, Count: count.Count}
+	}
+	waits := newCountedValues(values)
+	if waits.total == 0 {
+		return waitStats{}, false
+	}
+	return waitStats{
+		asa: waits.mean(),
+		max: waits.max(),
+		p50: waits.percentile(50),
+		p90: waits.percentile(90),
+		p95: waits.percentile(95),
+	}, true
+}
+
+// groupWaits делит количества ожиданий по ключу: дню, периоду или интервалу
+func groupWaits[K comparable](counts []WaitCount, key func(WaitCount) K) map[K][]WaitCount {
+	groups := make(map[K][]WaitCount)
+	for _, count := range counts {
+		k := key(count)
+		groups[k] = append(groups[k], count)
+	}
+	return groups
+}
+
+// dailyWaits показатели ожидания по дням
+func dailyWaits(counts []WaitCount) map[string]waitStats {
+	stats := make(map[string]waitStats)
+	for date, waits := range groupWaits(counts, func(c WaitCount) string { return c.Date }) {
+		if s, ok := summarizeWaits(waits); ok {
+			stats[date] = s
+		}
+	}
+	return stats
+}
+
+// waitPoints дневной ряд метрики ожидания
+func waitPoints(stats map[string]waitStats, metric string) []DailyPoint {
+	points := make([]DailyPoint, 0, len(stats))
+	for _, date := range sortedKeys(stats) {
+		points = append(points, DailyPoint{Date: date, Value: asSeconds(stats[date].value(metric))})
+	}
+	return points
+}
+
+// waitBuckets метрика ожидания по дням и интервалам шириной width минут
+func waitBuckets(metric string, width int, counts []WaitCount) []BucketRow {
+	type dayBucket struct {
+		date   string
+		bucket int
+	}
+	groups := groupWaits(counts, func(c WaitCount) dayBucket { return dayBucket{c.Date, c.Minute / width} })
+
+	values := make([]BucketValue, 0, len(groups))
+	for key, waits := range groups {
+		if s, ok := summarizeWaits(waits); ok {
+			values = append(values, BucketValue{Date: key.date, Bucket: key.bucket, Value: s.value(metric)})
+		}
+	}
+	return pivotBuckets(values, width)
+}
@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestSummarizeWaits(t *testing.T) {
+	// Количества по значениям ожидания, порядок не важен; пустые не учитываются
+	stats, ok := summarizeWaits([]WaitCount{
+		{Wait: 60, Count: 1},
+		{Wait: 10, Count: 2},
+		{Wait: 5, Count: 1},
+		{Wait: 90, Count: 0},
+	})
+	if !ok {
+		t.Fatal("expected stats")
+	}
+	// 5, 10, 10, 60: p50 между 10 и 10, p90 на 0.7 от 10 до 60
+	want := waitStats{asa: 21.25, max: 60, p50: 10, p90: 45, p95: 52.5}
+	if stats != want {
+		t.Errorf("got %+v, want %+v", stats, want)
+	}
+
+	single, _ := summarizeWaits([]WaitCount{{Wait: 7, Count: 1}})
+	if single != (waitStats{asa: 7, max: 7, p50: 7, p90: 7, p95: 7}) {
+		t.Errorf("single call = %+v", single)
+	}
+	if _, ok := summarizeWaits(nil); ok {
+		t.Error("expected no stats without calls")
+	}
+}